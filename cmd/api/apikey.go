@@ -0,0 +1,43 @@
+package main
+
+//apikey.go defines the apikey subcommand, which creates API keys from the command line.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+)
+
+const apiKeyUsage = "usage: api apikey create <name> <scope>..."
+
+// runAPIKey runs the apikey subcommand against apiKeys with its arguments:
+//
+//	create <name> <scope>...   create an API key with the given scopes and print its secret
+//
+// Without JWT keys it is how the first key is issued, which needs the apikey:admin scope to manage the other keys
+// through the /api/apikey endpoints.
+func runAPIKey(ctx context.Context, apiKeys services.APIKeyService, args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		return errors.New(apiKeyUsage)
+	}
+	if len(args) < 3 {
+		return fmt.Errorf("a name and at least one scope are required: %s", apiKeyUsage)
+	}
+	scopes := args[2:]
+	for _, scope := range scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			return fmt.Errorf("unknown scope %q, must be one of %s", scope, strings.Join(auth.Scopes, ", "))
+		}
+	}
+	key, secret, err := apiKeys.CreateAPIKey(ctx, models.APIKey{Name: args[1], Scopes: slices.Compact(slices.Sorted(slices.Values(scopes)))})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created API key %d %q, it is shown only once:\n%s\n", key.ID, key.Name, secret)
+	return nil
+}
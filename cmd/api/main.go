@@ -1,19 +1,31 @@
 package main
 
 //main.go initiates the local database, local http server and shuts down gracefully in case of errors.
+//Run as "api migrate ..." it manages the database schema instead, see ./migrate.go, and as "api apikey ..." it creates
+//API keys, see ./apikey.go. Flags before the subcommand set the configuration, see config.Load, and
+//"api --print-config" prints it.
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"tech-challenge/internal/auth"
 	"tech-challenge/internal/config"
 	"tech-challenge/internal/database"
+	"tech-challenge/internal/handlers"
+	"tech-challenge/internal/logging"
+	"tech-challenge/internal/ratelimit"
 	"tech-challenge/internal/routes"
-	"time"
+	"tech-challenge/internal/services"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
@@ -22,58 +34,204 @@ import (
 
 func main() {
 
-	cfg, err := config.NewConfig()
+	cfg, opts, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
-	db, err := database.NewDatabase(fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		cfg.DBHost,
-		cfg.DBUser,
-		cfg.DBPassword,
-		cfg.DBName,
-		cfg.DBPort))
-
+	if opts.PrintConfig {
+		if err = cfg.Print(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	logger, err := logging.New(os.Stderr, cfg.LogFormat, cfg.LogLevel)
 	if err != nil {
 		log.Fatal(err)
 	}
-	log.Println("Creating routes...")
+	// records of the log package and of code without a request's logger go to logger too
+	slog.SetDefault(logger)
+
+	migrate := len(opts.Args) > 0 && opts.Args[0] == "migrate"
+	createAPIKey := len(opts.Args) > 0 && opts.Args[0] == "apikey"
+	var db *sql.DB
+	var personService services.PersonService
+	var courseService services.CourseService
+	var enrollmentService services.EnrollmentService
+	var apiKeyService services.APIKeyService
+	if cfg.DBDriver == config.DriverMemory {
+		if migrate || createAPIKey {
+			fatal("Could not run "+opts.Args[0], fmt.Errorf("%s needs DATABASE_DRIVER=%s or %s", opts.Args[0], config.DriverPostgres, config.DriverSQLite))
+		}
+		slog.Warn("Storing data in memory, it will be lost when the server exits")
+		store := services.NewMemoryStore()
+		personService = services.NewMemoryPersonService(store)
+		courseService = services.NewMemoryCourseService(store)
+		enrollmentService = services.NewMemoryEnrollmentService(store)
+		apiKeyService = services.NewMemoryAPIKeyService(store)
+	} else {
+		connectionString := cfg.DBPath
+		if cfg.DBDriver == config.DriverPostgres {
+			connectionString = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+				cfg.DBHost,
+				cfg.DBUser,
+				cfg.DBPassword,
+				cfg.DBName,
+				cfg.DBPort)
+		}
+		db, err = database.NewDatabase(cfg.DBDriver, connectionString, database.Options{
+			MaxOpenConns:    cfg.DBMaxOpenConns,
+			MaxIdleConns:    cfg.DBMaxIdleConns,
+			ConnMaxLifetime: cfg.DBConnMaxLifetime,
+			ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+			ConnectTimeout:  cfg.DBConnectTimeout,
+		})
+
+		if err != nil {
+			fatal("Could not connect to the database", err)
+		}
+
+		if migrate {
+			err = runMigrate(context.Background(), db, cfg.DBDriver, opts.Args[1:])
+			db.Close()
+			if err != nil {
+				fatal("Could not migrate", err)
+			}
+			return
+		}
+		if cfg.MigrateOnStart {
+			slog.Info("Applying migrations")
+			if err = runMigrate(context.Background(), db, cfg.DBDriver, []string{"up"}); err != nil {
+				fatal("Could not apply migrations", err)
+			}
+		}
+		personService = services.NewPersonService(db)
+		courseService = services.NewCourseService(db)
+		enrollmentService = services.NewEnrollmentService(db)
+		apiKeyService = services.NewAPIKeyService(db)
+		if createAPIKey {
+			err = runAPIKey(context.Background(), apiKeyService, opts.Args[1:])
+			db.Close()
+			if err != nil {
+				fatal("Could not create API key", err)
+			}
+			return
+		}
+	}
+
+	slog.Info("Creating routes")
 	r := chi.NewRouter()
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"https://*", "http://*", "ws://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedOrigins:   cfg.CORSAllowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", handlers.RequestIDHeader},
+		ExposedHeaders:   []string{"Link", "X-Total-Count", handlers.RequestIDHeader, "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
+	if cfg.HTTPTrustProxyHeaders {
+		r.Use(middleware.RealIP)
+	}
+	r.Use(handlers.RequestID)
 	r.Use(middleware.Compress(5))
-	r.Use(middleware.Logger)
-	routes.SetupRoutes(r, db)
+	r.Use(handlers.RequestLogger(logger))
+	rateLimit, rateLimitPerIP, rateLimitRules, err := rateLimits(cfg.RateLimit, cfg.RateLimitPerIP, cfg.RateLimitRoutes)
+	if err != nil {
+		fatal("Could not set up rate limiting", err)
+	}
+	// addresses are limited before authentication, so requests with bad credentials count too
+	r.Use(handlers.RateLimitIP(rateLimitPerIP))
+	// before Authenticate, so looking up API keys is bounded too
+	r.Use(handlers.DBTimeout(cfg.DBTimeout))
+	var policy *auth.Policy
+	if cfg.AuthEnabled {
+		// without JWT keys only API keys are accepted, the first of which "api apikey create" issues
+		var verifier handlers.TokenVerifier
+		if cfg.AuthJWTHMACSecret != "" || len(cfg.AuthJWTEd25519PublicKeys) > 0 {
+			verifier, err = auth.NewVerifier(auth.Options{
+				HMACSecret:        cfg.AuthJWTHMACSecret,
+				Ed25519PublicKeys: cfg.AuthJWTEd25519PublicKeys,
+				Issuer:            cfg.AuthJWTIssuer,
+				Audience:          cfg.AuthJWTAudience,
+			})
+			if err != nil {
+				fatal("Could not set up authentication", err)
+			}
+		} else {
+			slog.Warn("No JWT keys are configured, only API keys are accepted")
+		}
+		r.Use(handlers.Authenticate(verifier, apiKeyService, cfg.AuthPublicPaths))
+		policy = auth.NewPolicy(personService)
+	} else {
+		slog.Warn("Authentication is disabled, anyone can call the API. Set AUTH_ENABLED=true to require tokens or API keys")
+	}
+	r.Use(handlers.RateLimit(rateLimit, rateLimitRules))
+	routes.SetupRoutes(r, personService, courseService, enrollmentService, apiKeyService, policy)
+
+	// every request's context derives from requestsCtx, so cancelling it cancels the queries of in-flight requests
+	requestsCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
 	srv := &http.Server{
-		Addr:    cfg.HTTPDomain + cfg.HTTPPort,
-		Handler: r,
+		Addr:        cfg.HTTPDomain + cfg.HTTPPort,
+		Handler:     r,
+		BaseContext: func(net.Listener) context.Context { return requestsCtx },
+		ErrorLog:    slog.NewLogLogger(logger.Handler(), slog.LevelError),
 	}
 
 	//starting server
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Could not listen on %s: %v\n", srv.Addr, err)
+			fatal("Could not listen on "+srv.Addr, err)
 		}
 	}()
-	log.Printf("Server is ready to handle requests at %s", srv.Addr)
+	slog.Info("Server is ready to handle requests", slog.String("addr", srv.Addr))
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
 	<-quit
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server")
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(cfg.HTTPShutdownDuration))
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTPShutdownDuration)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		cancelRequests()
+		srv.Close()
+		slog.Error("Server forced to shutdown", slog.String("error", err.Error()))
+	}
+	if db != nil {
+		db.Close()
 	}
 
-	log.Println("Server exiting")
+	slog.Info("Server exiting")
+}
+
+// fatal logs that the server cannot go on because of err, and exits.
+func fatal(message string, err error) {
+	slog.Error(message, slog.String("error", err.Error()))
+	os.Exit(1)
+}
+
+// rateLimits parses the RateLimit, RateLimitPerIP and RateLimitRoutes settings of config.Config.
+func rateLimits(limit string, perIP string, routes []string) (ratelimit.Limit, ratelimit.Limit, []ratelimit.Rule, error) {
+	defaultLimit, err := ratelimit.ParseLimit(limit)
+	if err != nil {
+		return ratelimit.Limit{}, ratelimit.Limit{}, nil, fmt.Errorf("RATE_LIMIT: %w", err)
+	}
+	ipLimit, err := ratelimit.ParseLimit(perIP)
+	if err != nil {
+		return ratelimit.Limit{}, ratelimit.Limit{}, nil, fmt.Errorf("RATE_LIMIT_PER_IP: %w", err)
+	}
+	rules := make([]ratelimit.Rule, 0, len(routes))
+	for _, route := range routes {
+		rule, err := ratelimit.ParseRule(route)
+		if err != nil {
+			return ratelimit.Limit{}, ratelimit.Limit{}, nil, fmt.Errorf("RATE_LIMIT_ROUTES: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return defaultLimit, ipLimit, rules, nil
 }
@@ -0,0 +1,67 @@
+package main
+
+//migrate.go defines the migrate subcommand, which manages the database schema instead of starting the server.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"tech-challenge/internal/database"
+)
+
+const migrateUsage = "usage: api migrate up | down [steps] | version | seed"
+
+// runMigrate runs the migrate subcommand against db, a database of the given driver, with its arguments:
+//
+//	up             apply every pending migration
+//	down [steps]   revert the latest steps migrations, 1 by default
+//	version        print the current schema version
+//	seed           load the example data
+func runMigrate(ctx context.Context, db *sql.DB, driver string, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	migrator, err := database.NewMigrator(db, driver)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Applied migrations", slog.Int("count", applied))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive number: %s", migrateUsage)
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		slog.Info("Reverted migrations", slog.Int("count", reverted))
+	case "version":
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(version)
+	case "seed":
+		if err = database.Seed(ctx, db, driver); err != nil {
+			return err
+		}
+		slog.Info("Seeded database")
+	default:
+		return fmt.Errorf("unknown migrate command %q: %s", args[0], migrateUsage)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package auth
+
+//apikey.go generates the API keys that batch jobs and partner systems authenticate with, and checks them against the
+//salted hashes they are stored as. Only a key's prefix and hash are kept, so a key is shown once, when it is made.
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Scopes an API key can be granted. API keys have no roles, they may perform only the actions of their scopes, see
+// Action.Scope.
+const (
+	ScopePersonRead  = "person:read"
+	ScopePersonWrite = "person:write"
+	ScopeCourseRead  = "course:read"
+	ScopeCourseWrite = "course:write"
+	// ScopeAPIKeyAdmin allows listing, creating, rotating and revoking API keys, including the key's own.
+	ScopeAPIKeyAdmin = "apikey:admin"
+)
+
+// Scopes are every scope an API key can be granted.
+var Scopes = []string{ScopePersonRead, ScopePersonWrite, ScopeCourseRead, ScopeCourseWrite, ScopeAPIKeyAdmin}
+
+// apiKeyTag starts every API key, so leaked keys are easy to recognise and search for.
+const apiKeyTag = "tc"
+
+// GeneratedAPIKey is a new API key and what to store of it.
+type GeneratedAPIKey struct {
+	// Key is the full API key, such as "tc_3f9a0c12d4e5_<secret>". It must only be shown to its owner.
+	Key string
+	// Prefix identifies the key without revealing it, such as "tc_3f9a0c12d4e5".
+	Prefix string
+	// Salt and Hash are the hex encoded random salt and the SHA-256 of salt and Key.
+	Salt string
+	Hash string
+}
+
+// GenerateAPIKey returns a new random API key.
+func GenerateAPIKey() (GeneratedAPIKey, error) {
+	id := make([]byte, 6)
+	secret := make([]byte, 32)
+	salt := make([]byte, 16)
+	for _, b := range [][]byte{id, secret, salt} {
+		if _, err := rand.Read(b); err != nil {
+			return GeneratedAPIKey{}, fmt.Errorf("failed to generate API key: %w", err)
+		}
+	}
+	prefix := apiKeyTag + "_" + hex.EncodeToString(id)
+	key := prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return GeneratedAPIKey{
+		Key:    key,
+		Prefix: prefix,
+		Salt:   hex.EncodeToString(salt),
+		Hash:   hashAPIKey(key, salt),
+	}, nil
+}
+
+// APIKeyPrefix returns the prefix of key, which identifies its stored hash, or false if key is malformed.
+func APIKeyPrefix(key string) (string, bool) {
+	tag, rest, _ := strings.Cut(key, "_")
+	id, secret, _ := strings.Cut(rest, "_")
+	if tag != apiKeyTag || id == "" || secret == "" {
+		return "", false
+	}
+	return tag + "_" + id, true
+}
+
+// CheckAPIKey returns whether key matches the salt and hash stored by GenerateAPIKey, in constant time.
+func CheckAPIKey(key string, salt string, hash string) bool {
+	rawSalt, err := hex.DecodeString(salt)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashAPIKey(key, rawSalt)), []byte(hash)) == 1
+}
+
+func hashAPIKey(key string, salt []byte) string {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(key))
+	return hex.EncodeToString(h.Sum(nil))
+}
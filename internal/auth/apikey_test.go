@@ -0,0 +1,65 @@
+package auth
+
+//apikey_test.go tests ./apikey.go utilizing table based testing best practices.
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	generated, err := GenerateAPIKey()
+	require.NoError(t, err)
+	other, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(generated.Key, generated.Prefix+"_"))
+	assert.NotEqual(t, generated.Key, other.Key)
+	assert.NotEqual(t, generated.Salt, other.Salt)
+	assert.NotContains(t, generated.Hash, generated.Key)
+	prefix, ok := APIKeyPrefix(generated.Key)
+	assert.True(t, ok)
+	assert.Equal(t, generated.Prefix, prefix)
+
+	tests := map[string]struct {
+		key      string
+		salt     string
+		expected bool
+	}{
+		"matching key":   {key: generated.Key, salt: generated.Salt, expected: true},
+		"other key":      {key: other.Key, salt: generated.Salt, expected: false},
+		"other salt":     {key: generated.Key, salt: other.Salt, expected: false},
+		"truncated key":  {key: generated.Key[:len(generated.Key)-1], salt: generated.Salt, expected: false},
+		"malformed salt": {key: generated.Key, salt: "not hex", expected: false},
+		"empty key":      {key: "", salt: generated.Salt, expected: false},
+	}
+	for testName, testConditions := range tests {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testConditions.expected, CheckAPIKey(testConditions.key, testConditions.salt, generated.Hash))
+		})
+	}
+}
+
+func TestAPIKeyPrefix(t *testing.T) {
+	tests := map[string]struct {
+		key            string
+		expectedPrefix string
+		expectsOK      bool
+	}{
+		"valid":        {key: "tc_3f9a0c12d4e5_c2VjcmV0", expectedPrefix: "tc_3f9a0c12d4e5", expectsOK: true},
+		"other tag":    {key: "gh_3f9a0c12d4e5_c2VjcmV0"},
+		"no secret":    {key: "tc_3f9a0c12d4e5"},
+		"empty secret": {key: "tc_3f9a0c12d4e5_"},
+		"empty":        {key: ""},
+	}
+	for testName, testConditions := range tests {
+		t.Run(testName, func(t *testing.T) {
+			prefix, ok := APIKeyPrefix(testConditions.key)
+			assert.Equal(t, testConditions.expectsOK, ok)
+			assert.Equal(t, testConditions.expectedPrefix, prefix)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package auth
+
+//identity.go defines who made a request, and how it travels in the request's context from the authentication
+//middleware to the handlers.
+
+import (
+	"context"
+	"slices"
+)
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	// Subject identifies the caller, such as the sub claim of its token.
+	Subject string
+	// Roles are the roles the caller was granted, see ./policy.go.
+	Roles []string
+	// PersonID is the id of the caller's own person record, or 0 if the caller has none.
+	PersonID int
+	// Scopes are set for callers using an API key, which may perform only the actions of its scopes, whatever
+	// their roles.
+	Scopes []string
+}
+
+// HasRole returns whether the caller was granted role.
+func (i Identity) HasRole(role string) bool {
+	return slices.Contains(i.Roles, role)
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying identity.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the Identity stored in ctx by WithIdentity, and whether there is one.
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
@@ -0,0 +1,215 @@
+package auth
+
+//jwt.go verifies the JSON Web Tokens (RFC 7519) that callers authenticate with. Tokens must be signed with HS256 or
+//EdDSA (Ed25519), the only algorithms a Verifier accepts.
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned for a token that is malformed, signed with an unexpected algorithm or key, or issued
+	// by or for someone else.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpiredToken is returned for a correctly signed token that has expired or is not valid yet.
+	ErrExpiredToken = errors.New("token has expired or is not valid yet")
+)
+
+// clockSkew is how far the clocks of the token issuer and the server may disagree about exp and nbf.
+const clockSkew = 30 * time.Second
+
+// minHMACSecret is the shortest HS256 secret accepted, the length of the SHA-256 output as RFC 7518 requires.
+const minHMACSecret = sha256.Size
+
+// Options configures the keys and claims a Verifier accepts. At least one key is required.
+type Options struct {
+	// HMACSecret verifies HS256 tokens. It must be at least 32 bytes long.
+	HMACSecret string
+	// Ed25519PublicKeys verify EdDSA tokens. Each is a PEM "PUBLIC KEY" block or the base64 of the raw 32-byte key.
+	// Tokens may be signed by any of them, so keys can be rotated.
+	Ed25519PublicKeys []string
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+}
+
+// Verifier checks the signature and claims of tokens and returns the Identity they were issued to.
+type Verifier struct {
+	hmacSecret  []byte
+	ed25519Keys []ed25519.PublicKey
+	issuer      string
+	audience    string
+	now         func() time.Time
+}
+
+// NewVerifier returns a Verifier for opts, or an error if a key is malformed or none is given.
+func NewVerifier(opts Options) (*Verifier, error) {
+	v := &Verifier{issuer: opts.Issuer, audience: opts.Audience, now: time.Now}
+	if opts.HMACSecret != "" {
+		if len(opts.HMACSecret) < minHMACSecret {
+			return nil, fmt.Errorf("HMAC secret must be at least %d bytes long", minHMACSecret)
+		}
+		v.hmacSecret = []byte(opts.HMACSecret)
+	}
+	for i, encoded := range opts.Ed25519PublicKeys {
+		key, err := parseEd25519PublicKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("Ed25519 public key %d: %w", i+1, err)
+		}
+		v.ed25519Keys = append(v.ed25519Keys, key)
+	}
+	if v.hmacSecret == nil && len(v.ed25519Keys) == 0 {
+		return nil, errors.New("no HMAC secret or Ed25519 public key to verify tokens with")
+	}
+	return v, nil
+}
+
+// parseEd25519PublicKey decodes a PEM "PUBLIC KEY" block or the base64 of a raw Ed25519 public key.
+func parseEd25519PublicKey(encoded string) (ed25519.PublicKey, error) {
+	encoded = strings.TrimSpace(encoded)
+	if block, _ := pem.Decode([]byte(encoded)); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PEM public key: %w", err)
+		}
+		key, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("PEM public key is a %T, not an Ed25519 key", parsed)
+		}
+		return key, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("must be a PEM public key or base64")
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("must be %d bytes, got %d", ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// header is the JOSE header of a token.
+type header struct {
+	Algorithm string `json:"alg"`
+}
+
+// claims are the registered claims a Verifier checks, plus roles and person_id.
+type claims struct {
+	Issuer    string       `json:"iss"`
+	Subject   string       `json:"sub"`
+	Audience  audience     `json:"aud"`
+	ExpiresAt *numericDate `json:"exp"`
+	NotBefore *numericDate `json:"nbf"`
+	Roles     []string     `json:"roles"`
+	PersonID  int          `json:"person_id"`
+}
+
+// audience is the aud claim, which is either a single string or an array of them.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// numericDate is a time in seconds since the epoch, possibly fractional, as used by exp and nbf.
+type numericDate struct{ time.Time }
+
+func (d *numericDate) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return err
+	}
+	d.Time = time.Unix(0, int64(seconds*float64(time.Second)))
+	return nil
+}
+
+// Verify checks the signature of token and its exp, nbf, iss and aud claims, and returns the sub, roles and person_id
+// claims as an Identity. Tokens must have exp and sub claims. The error wraps ErrInvalidToken or ErrExpiredToken.
+func (v *Verifier) Verify(token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, fmt.Errorf("%w: must have three parts", ErrInvalidToken)
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return Identity{}, fmt.Errorf("%w: header %w", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: signature is not base64url", ErrInvalidToken)
+	}
+	if err := v.verifySignature(h.Algorithm, parts[0]+"."+parts[1], signature); err != nil {
+		return Identity{}, err
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return Identity{}, fmt.Errorf("%w: claims %w", ErrInvalidToken, err)
+	}
+	now := v.now()
+	switch {
+	case c.ExpiresAt == nil:
+		return Identity{}, fmt.Errorf("%w: exp claim is required", ErrInvalidToken)
+	case now.After(c.ExpiresAt.Add(clockSkew)):
+		return Identity{}, fmt.Errorf("%w: expired at %s", ErrExpiredToken, c.ExpiresAt.UTC().Format(time.RFC3339))
+	case c.NotBefore != nil && now.Add(clockSkew).Before(c.NotBefore.Time):
+		return Identity{}, fmt.Errorf("%w: not valid before %s", ErrExpiredToken, c.NotBefore.UTC().Format(time.RFC3339))
+	case c.Subject == "":
+		return Identity{}, fmt.Errorf("%w: sub claim is required", ErrInvalidToken)
+	case v.issuer != "" && c.Issuer != v.issuer:
+		return Identity{}, fmt.Errorf("%w: issued by %q", ErrInvalidToken, c.Issuer)
+	case v.audience != "" && !slices.Contains(c.Audience, v.audience):
+		return Identity{}, fmt.Errorf("%w: not issued for this audience", ErrInvalidToken)
+	}
+	return Identity{Subject: c.Subject, Roles: c.Roles, PersonID: c.PersonID}, nil
+}
+
+// verifySignature checks signature over signingInput with the keys configured for algorithm. Each algorithm only
+// uses its own kind of key, so a token cannot pass off an Ed25519 public key as an HMAC secret.
+func (v *Verifier) verifySignature(algorithm, signingInput string, signature []byte) error {
+	switch {
+	case algorithm == "HS256" && v.hmacSecret != nil:
+		mac := hmac.New(sha256.New, v.hmacSecret)
+		mac.Write([]byte(signingInput))
+		if hmac.Equal(mac.Sum(nil), signature) {
+			return nil
+		}
+	case algorithm == "EdDSA" && len(v.ed25519Keys) > 0:
+		for _, key := range v.ed25519Keys {
+			if ed25519.Verify(key, []byte(signingInput), signature) {
+				return nil
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, algorithm)
+	}
+	return fmt.Errorf("%w: signature does not match", ErrInvalidToken)
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a token into v.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.New("is not base64url")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("is not valid JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package auth
+
+//jwt_test.go tests ./jwt.go utilizing table based testing best practices.
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+var testNow = time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// signToken returns a token with claims signed by key, a []byte HMAC secret or an ed25519.PrivateKey, or left
+// unsigned for any other key.
+func signToken(t *testing.T, algorithm string, key any, claims map[string]any) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": algorithm, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var signature []byte
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(signingInput))
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerify(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPublic, otherPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	verifier, err := NewVerifier(Options{
+		HMACSecret:        testSecret,
+		Ed25519PublicKeys: []string{base64.StdEncoding.EncodeToString(otherPublic), base64.StdEncoding.EncodeToString(public)},
+		Issuer:            "https://issuer.example.com",
+		Audience:          "tech-challenge",
+	})
+	require.NoError(t, err)
+	verifier.now = func() time.Time { return testNow }
+	hmacOnly, err := NewVerifier(Options{HMACSecret: testSecret})
+	require.NoError(t, err)
+	hmacOnly.now = verifier.now
+
+	valid := func(overrides map[string]any) map[string]any {
+		claims := map[string]any{
+			"iss":       "https://issuer.example.com",
+			"sub":       "alice",
+			"aud":       "tech-challenge",
+			"exp":       testNow.Add(time.Hour).Unix(),
+			"roles":     []string{"admin", "registrar"},
+			"person_id": 7,
+		}
+		for name, value := range overrides {
+			if value == nil {
+				delete(claims, name)
+			} else {
+				claims[name] = value
+			}
+		}
+		return claims
+	}
+	alice := Identity{Subject: "alice", Roles: []string{"admin", "registrar"}, PersonID: 7}
+
+	tests := map[string]struct {
+		verifier       *Verifier
+		token          string
+		expectedReturn Identity
+		expectedErr    error
+	}{
+		"hs256":                     {token: signToken(t, "HS256", []byte(testSecret), valid(nil)), expectedReturn: alice},
+		"eddsa":                     {token: signToken(t, "EdDSA", private, valid(nil)), expectedReturn: alice},
+		"eddsa with a rotated key":  {token: signToken(t, "EdDSA", otherPrivate, valid(nil)), expectedReturn: alice},
+		"audience list":             {token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"aud": []string{"other", "tech-challenge"}})), expectedReturn: alice},
+		"fractional expiry":         {token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"exp": float64(testNow.Unix()) + 0.5})), expectedReturn: alice},
+		"expired within clock skew": {token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"exp": testNow.Add(-10 * time.Second).Unix()})), expectedReturn: alice},
+		"no roles":                  {token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"roles": nil})), expectedReturn: Identity{Subject: "alice", PersonID: 7}},
+		"no issuer or audience":     {verifier: hmacOnly, token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"iss": nil, "aud": nil})), expectedReturn: alice},
+		"expired":                   {token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"exp": testNow.Add(-time.Minute).Unix()})), expectedErr: ErrExpiredToken},
+		"not valid yet":             {token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"nbf": testNow.Add(time.Minute).Unix()})), expectedErr: ErrExpiredToken},
+		"no expiry":                 {token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"exp": nil})), expectedErr: ErrInvalidToken},
+		"no subject":                {token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"sub": nil})), expectedErr: ErrInvalidToken},
+		"wrong issuer":              {token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"iss": "https://evil.example.com"})), expectedErr: ErrInvalidToken},
+		"wrong audience":            {token: signToken(t, "HS256", []byte(testSecret), valid(map[string]any{"aud": "other"})), expectedErr: ErrInvalidToken},
+		"wrong hmac secret":         {token: signToken(t, "HS256", []byte(strings.Repeat("x", 32)), valid(nil)), expectedErr: ErrInvalidToken},
+		"unknown ed25519 key":       {verifier: hmacOnly, token: signToken(t, "EdDSA", private, valid(nil)), expectedErr: ErrInvalidToken},
+		"public key as hmac secret": {token: signToken(t, "HS256", []byte(public), valid(nil)), expectedErr: ErrInvalidToken},
+		"unsigned":                  {token: signToken(t, "none", nil, valid(nil)), expectedErr: ErrInvalidToken},
+		"unsupported algorithm":     {token: signToken(t, "RS256", []byte(testSecret), valid(nil)), expectedErr: ErrInvalidToken},
+		"tampered claims":           {token: tamper(t, signToken(t, "HS256", []byte(testSecret), valid(nil))), expectedErr: ErrInvalidToken},
+		"not a jwt":                 {token: "not-a-jwt", expectedErr: ErrInvalidToken},
+		"malformed header":          {token: "e30K!.e30.", expectedErr: ErrInvalidToken},
+	}
+	for testName, testConditions := range tests {
+		t.Run(testName, func(t *testing.T) {
+			v := testConditions.verifier
+			if v == nil {
+				v = verifier
+			}
+			identity, err := v.Verify(testConditions.token)
+			if testConditions.expectedErr != nil {
+				assert.ErrorIs(t, err, testConditions.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testConditions.expectedReturn, identity)
+		})
+	}
+}
+
+// tamper replaces the claims of token with ones for another subject, keeping the signature.
+func tamper(t *testing.T, token string) string {
+	parts := strings.Split(token, ".")
+	claims, err := json.Marshal(map[string]any{"sub": "mallory", "exp": testNow.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	return parts[0] + "." + base64.RawURLEncoding.EncodeToString(claims) + "." + parts[2]
+}
+
+func TestNewVerifier(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	tests := map[string]struct {
+		opts         Options
+		expectsError bool
+	}{
+		"hmac secret":           {opts: Options{HMACSecret: testSecret}},
+		"base64 ed25519 key":    {opts: Options{Ed25519PublicKeys: []string{base64.StdEncoding.EncodeToString(public)}}},
+		"pem ed25519 key":       {opts: Options{Ed25519PublicKeys: []string{pemKey}}},
+		"no keys":               {opts: Options{Issuer: "https://issuer.example.com"}, expectsError: true},
+		"short hmac secret":     {opts: Options{HMACSecret: "secret"}, expectsError: true},
+		"short ed25519 key":     {opts: Options{Ed25519PublicKeys: []string{base64.StdEncoding.EncodeToString(public[:16])}}, expectsError: true},
+		"malformed ed25519 key": {opts: Options{Ed25519PublicKeys: []string{"not base64!"}}, expectsError: true},
+	}
+	for testName, testConditions := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := NewVerifier(testConditions.opts)
+			if testConditions.expectsError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package auth
+
+//policy.go decides which roles and API key scopes may perform each action of the API.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"tech-challenge/internal/models"
+)
+
+// ErrForbidden is returned by Policy.Authorize when the caller may not perform an action.
+var ErrForbidden = errors.New("forbidden")
+
+// Roles a caller may be granted.
+const (
+	// RoleAdmin may perform every action.
+	RoleAdmin = "admin"
+	// RoleProfessor may read courses and their own person record, and edit and read the roster of the courses they
+	// are enrolled in as a professor.
+	RoleProfessor = "professor"
+	// RoleStudent may read courses and their own person record.
+	RoleStudent = "student"
+)
+
+// Action is something a caller asks the API to do.
+type Action string
+
+// Actions of the course routes, whose Resource is a course.
+const (
+	ListCourses  Action = "list courses"
+	ReadCourse   Action = "read course"
+	CreateCourse Action = "create course"
+	UpdateCourse Action = "update course"
+	DeleteCourse Action = "delete course"
+	ReadRoster   Action = "read course roster"
+)
+
+// Actions of the person routes, whose Resource is a person.
+const (
+	ListPeople      Action = "list people"
+	ReadPerson      Action = "read person"
+	CreatePerson    Action = "create person"
+	UpdatePerson    Action = "update person"
+	DeletePerson    Action = "delete person"
+	ReadEnrollments Action = "read enrollments"
+	Enroll          Action = "enroll person"
+	Drop            Action = "drop person"
+)
+
+// ManageAPIKeys is the action of every API key route, which has no Resource.
+const ManageAPIKeys Action = "manage API keys"
+
+// OnCourse returns whether a is performed on a course rather than on a person.
+func (a Action) OnCourse() bool {
+	return slices.Contains([]Action{ListCourses, ReadCourse, CreateCourse, UpdateCourse, DeleteCourse, ReadRoster}, a)
+}
+
+// Scope returns the API key scope that allows a, or "" if no API key may perform a.
+func (a Action) Scope() string {
+	switch a {
+	case ListCourses, ReadCourse, ReadRoster:
+		return ScopeCourseRead
+	case CreateCourse, UpdateCourse, DeleteCourse:
+		return ScopeCourseWrite
+	case ListPeople, ReadPerson, ReadEnrollments:
+		return ScopePersonRead
+	case CreatePerson, UpdatePerson, DeletePerson, Enroll, Drop:
+		return ScopePersonWrite
+	case ManageAPIKeys:
+		return ScopeAPIKeyAdmin
+	default:
+		return ""
+	}
+}
+
+// Resource is what an Action is performed on. Only the fields identifying it are set: CourseID for course actions,
+// PersonID or PersonName for person actions, and both PersonID and CourseID for Enroll and Drop.
+type Resource struct {
+	CourseID int
+	PersonID int
+	// PersonName is a first and last name separated by a space, matched case-insensitively.
+	PersonName string
+}
+
+// PersonFinder finds the person record of a caller. services.PersonService implements it.
+type PersonFinder interface {
+	// GetPersonByID returns the person with id, or a zero models.Person if there is none.
+	GetPersonByID(ctx context.Context, id int) (models.Person, error)
+}
+
+// Policy decides whether a caller may perform an Action. Callers are tied to their person record by
+// Identity.PersonID, which Policy looks up with people when a rule depends on it.
+type Policy struct {
+	people PersonFinder
+}
+
+// NewPolicy returns a Policy that looks up person records with people.
+func NewPolicy(people PersonFinder) *Policy {
+	return &Policy{people: people}
+}
+
+// Authorize returns nil if identity may perform action on resource, an error wrapping ErrForbidden if it may not, or
+// the error that prevented looking up its person record. A caller with several roles may do what any of them allows.
+// A caller with Scopes is limited to them instead.
+func (p *Policy) Authorize(ctx context.Context, identity Identity, action Action, resource Resource) error {
+	if identity.Scopes != nil {
+		if scope := action.Scope(); scope != "" && slices.Contains(identity.Scopes, scope) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is not allowed by the scopes of %s", ErrForbidden, action, identity.Subject)
+	}
+	if identity.HasRole(RoleAdmin) {
+		return nil
+	}
+	professor, student := identity.HasRole(RoleProfessor), identity.HasRole(RoleStudent)
+	if !professor && !student {
+		return fmt.Errorf("%w: %s needs the %s role", ErrForbidden, action, RoleAdmin)
+	}
+
+	switch action {
+	case ListCourses, ReadCourse:
+		return nil
+	case UpdateCourse, ReadRoster:
+		if professor {
+			self, err := p.self(ctx, identity)
+			if err != nil {
+				return err
+			}
+			if self.Type == RoleProfessor && slices.Contains(self.Courses, resource.CourseID) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s %d needs the %s role, or to be its professor", ErrForbidden, action, resource.CourseID, RoleAdmin)
+	case ReadPerson, ReadEnrollments:
+		self, err := p.self(ctx, identity)
+		if err != nil {
+			return err
+		}
+		if isSelf(self, resource) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s needs the %s role, or to be that person", ErrForbidden, action, RoleAdmin)
+	default:
+		return fmt.Errorf("%w: %s needs the %s role", ErrForbidden, action, RoleAdmin)
+	}
+}
+
+// self returns the person record of identity. A caller without one is forbidden the actions that depend on it.
+func (p *Policy) self(ctx context.Context, identity Identity) (models.Person, error) {
+	if identity.PersonID == 0 {
+		return models.Person{}, fmt.Errorf("%w: %s is not linked to a person", ErrForbidden, identity.Subject)
+	}
+	self, err := p.people.GetPersonByID(ctx, identity.PersonID)
+	if err != nil {
+		return models.Person{}, fmt.Errorf("failed to look up the person of %s: %w", identity.Subject, err)
+	}
+	if self.ID == 0 {
+		return models.Person{}, fmt.Errorf("%w: person %d of %s does not exist", ErrForbidden, identity.PersonID, identity.Subject)
+	}
+	return self, nil
+}
+
+// isSelf returns whether resource identifies the person self.
+func isSelf(self models.Person, resource Resource) bool {
+	if resource.PersonName != "" {
+		return strings.EqualFold(strings.Join(strings.Fields(resource.PersonName), " "), self.FirstName+" "+self.LastName)
+	}
+	return resource.PersonID == self.ID
+}
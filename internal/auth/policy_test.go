@@ -0,0 +1,125 @@
+package auth
+
+//policy_test.go tests ./policy.go utilizing table based testing best practices.
+
+import (
+	"context"
+	"errors"
+	"tech-challenge/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// people is a PersonFinder over a fixed set of person records.
+type people map[int]models.Person
+
+func (p people) GetPersonByID(ctx context.Context, id int) (models.Person, error) {
+	if id == 99 {
+		return models.Person{}, errors.New("database is down")
+	}
+	return p[id], nil
+}
+
+func TestAuthorize(t *testing.T) {
+	policy := NewPolicy(people{
+		1: {ID: 1, FirstName: "Ada", LastName: "Lovelace", Type: "professor", Courses: []int{1, 2}},
+		2: {ID: 2, FirstName: "Jane", LastName: "Doe", Type: "student", Courses: []int{1}},
+	})
+	admin := Identity{Subject: "root", Roles: []string{RoleAdmin}}
+	professor := Identity{Subject: "ada", Roles: []string{RoleProfessor}, PersonID: 1}
+	student := Identity{Subject: "jane", Roles: []string{RoleStudent}, PersonID: 2}
+	// a professor token for a person record of a student
+	studentAsProfessor := Identity{Subject: "jane", Roles: []string{RoleProfessor}, PersonID: 2}
+	unlinked := Identity{Subject: "nobody", Roles: []string{RoleStudent, RoleProfessor}}
+	missing := Identity{Subject: "ghost", Roles: []string{RoleStudent}, PersonID: 3}
+	broken := Identity{Subject: "unlucky", Roles: []string{RoleStudent}, PersonID: 99}
+	noRoles := Identity{Subject: "guest", PersonID: 2}
+	readOnlyKey := Identity{Subject: "apikey:tc_3f9a0c12d4e5", Scopes: []string{ScopeCourseRead, ScopePersonRead}}
+	// scopes limit a caller even if it claims roles
+	adminKey := Identity{Subject: "apikey:tc_000000000000", Roles: []string{RoleAdmin}, Scopes: []string{ScopePersonWrite}}
+	keyAdminKey := Identity{Subject: "apikey:tc_111111111111", Scopes: []string{ScopeAPIKeyAdmin}}
+
+	tests := map[string]struct {
+		identity    Identity
+		action      Action
+		resource    Resource
+		expectedErr error
+		expectsErr  bool
+	}{
+		"admin deletes a course":                {identity: admin, action: DeleteCourse, resource: Resource{CourseID: 1}},
+		"admin lists people":                    {identity: admin, action: ListPeople},
+		"admin enrolls":                         {identity: admin, action: Enroll, resource: Resource{PersonID: 2, CourseID: 2}},
+		"admin without a person record":         {identity: Identity{Subject: "root", Roles: []string{"auditor", RoleAdmin}}, action: UpdatePerson, resource: Resource{PersonID: 1}},
+		"professor lists courses":               {identity: professor, action: ListCourses},
+		"professor updates their course":        {identity: professor, action: UpdateCourse, resource: Resource{CourseID: 2}},
+		"professor reads their roster":          {identity: professor, action: ReadRoster, resource: Resource{CourseID: 1}},
+		"professor updates another course":      {identity: professor, action: UpdateCourse, resource: Resource{CourseID: 3}, expectedErr: ErrForbidden},
+		"professor reads another roster":        {identity: professor, action: ReadRoster, resource: Resource{CourseID: 3}, expectedErr: ErrForbidden},
+		"professor creates a course":            {identity: professor, action: CreateCourse, expectedErr: ErrForbidden},
+		"professor deletes their course":        {identity: professor, action: DeleteCourse, resource: Resource{CourseID: 1}, expectedErr: ErrForbidden},
+		"professor reads themselves":            {identity: professor, action: ReadPerson, resource: Resource{PersonName: "ada  LOVELACE"}},
+		"professor reads a student":             {identity: professor, action: ReadPerson, resource: Resource{PersonID: 2}, expectedErr: ErrForbidden},
+		"professor enrolls a student":           {identity: professor, action: Enroll, resource: Resource{PersonID: 2, CourseID: 2}, expectedErr: ErrForbidden},
+		"student reads a course":                {identity: student, action: ReadCourse, resource: Resource{CourseID: 2}},
+		"student reads themselves by id":        {identity: student, action: ReadPerson, resource: Resource{PersonID: 2}},
+		"student reads themselves by name":      {identity: student, action: ReadPerson, resource: Resource{PersonName: "Jane Doe"}},
+		"student reads their enrollments":       {identity: student, action: ReadEnrollments, resource: Resource{PersonID: 2}},
+		"student reads another person":          {identity: student, action: ReadPerson, resource: Resource{PersonName: "Ada Lovelace"}, expectedErr: ErrForbidden},
+		"student reads other enrollments":       {identity: student, action: ReadEnrollments, resource: Resource{PersonID: 1}, expectedErr: ErrForbidden},
+		"student updates themselves":            {identity: student, action: UpdatePerson, resource: Resource{PersonID: 2}, expectedErr: ErrForbidden},
+		"student updates their course":          {identity: student, action: UpdateCourse, resource: Resource{CourseID: 1}, expectedErr: ErrForbidden},
+		"student reads their course roster":     {identity: student, action: ReadRoster, resource: Resource{CourseID: 1}, expectedErr: ErrForbidden},
+		"student lists people":                  {identity: student, action: ListPeople, expectedErr: ErrForbidden},
+		"student drops a course":                {identity: student, action: Drop, resource: Resource{PersonID: 2, CourseID: 1}, expectedErr: ErrForbidden},
+		"professor role on a student record":    {identity: studentAsProfessor, action: UpdateCourse, resource: Resource{CourseID: 1}, expectedErr: ErrForbidden},
+		"unlinked caller reads a course":        {identity: unlinked, action: ReadCourse, resource: Resource{CourseID: 1}},
+		"unlinked caller reads a person":        {identity: unlinked, action: ReadPerson, resource: Resource{PersonID: 0}, expectedErr: ErrForbidden},
+		"unlinked professor updates a course":   {identity: unlinked, action: UpdateCourse, resource: Resource{CourseID: 1}, expectedErr: ErrForbidden},
+		"caller with a deleted person record":   {identity: missing, action: ReadPerson, resource: Resource{PersonID: 3}, expectedErr: ErrForbidden},
+		"caller without roles reads a course":   {identity: noRoles, action: ReadCourse, resource: Resource{CourseID: 1}, expectedErr: ErrForbidden},
+		"caller without roles reads themselves": {identity: noRoles, action: ReadPerson, resource: Resource{PersonID: 2}, expectedErr: ErrForbidden},
+		"api key reads a roster":                {identity: readOnlyKey, action: ReadRoster, resource: Resource{CourseID: 3}},
+		"api key reads a person":                {identity: readOnlyKey, action: ReadPerson, resource: Resource{PersonID: 1}},
+		"api key updates a course":              {identity: readOnlyKey, action: UpdateCourse, resource: Resource{CourseID: 3}, expectedErr: ErrForbidden},
+		"api key manages api keys":              {identity: readOnlyKey, action: ManageAPIKeys, expectedErr: ErrForbidden},
+		"api key admin manages api keys":        {identity: keyAdminKey, action: ManageAPIKeys},
+		"api key admin reads a course":          {identity: keyAdminKey, action: ReadCourse, resource: Resource{CourseID: 1}, expectedErr: ErrForbidden},
+		"api key with roles enrolls":            {identity: adminKey, action: Enroll, resource: Resource{PersonID: 2, CourseID: 3}},
+		"api key with roles deletes a course":   {identity: adminKey, action: DeleteCourse, resource: Resource{CourseID: 3}, expectedErr: ErrForbidden},
+		"admin manages api keys":                {identity: admin, action: ManageAPIKeys},
+		"professor manages api keys":            {identity: professor, action: ManageAPIKeys, expectedErr: ErrForbidden},
+		"person lookup fails":                   {identity: broken, action: ReadPerson, resource: Resource{PersonID: 99}, expectsErr: true},
+	}
+	for testName, testConditions := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := policy.Authorize(context.Background(), testConditions.identity, testConditions.action, testConditions.resource)
+			switch {
+			case testConditions.expectedErr != nil:
+				assert.ErrorIs(t, err, testConditions.expectedErr)
+			case testConditions.expectsErr:
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, ErrForbidden)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestActionOnCourse(t *testing.T) {
+	assert.True(t, ReadRoster.OnCourse())
+	assert.True(t, UpdateCourse.OnCourse())
+	assert.False(t, ReadEnrollments.OnCourse())
+	assert.False(t, Enroll.OnCourse())
+}
+
+func TestActionScope(t *testing.T) {
+	for _, action := range []Action{ListCourses, ReadCourse, CreateCourse, UpdateCourse, DeleteCourse, ReadRoster,
+		ListPeople, ReadPerson, CreatePerson, UpdatePerson, DeletePerson, ReadEnrollments, Enroll, Drop, ManageAPIKeys} {
+		assert.Contains(t, Scopes, action.Scope(), action)
+	}
+	assert.Equal(t, ScopeCourseRead, ReadRoster.Scope())
+	assert.Equal(t, ScopePersonWrite, Drop.Scope())
+	assert.Equal(t, ScopeAPIKeyAdmin, ManageAPIKeys.Scope())
+}
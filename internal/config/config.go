@@ -1,46 +1,139 @@
 package config
 
-//config.go defines a struct that loads environmental variables from .env
+//config.go defines a struct that loads its settings from flags, environmental variables, .env and a config file. The
+//env and default tags of its fields are read by ./loader.go, so adding a setting only takes a new field.
 
 import (
 	"fmt"
 	"os"
+	"reflect"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Env                  string `env:"ENV,required"`
-	DBName               string `env:"DATABASE_NAME,required"`
-	DBUser               string `env:"DATABASE_USER,required"`
-	DBPassword           string `env:"DATABASE_PASSWORD,required"`
-	DBHost               string `env:"DATABASE_HOST,required"`
-	DBPort               string `env:"DATABASE_PORT,required"`
-	HTTPDomain           string `env:"HTTP_DOMAIN,required"`
-	HTTPPort             string `env:"HTTP_PORT,required"`
-	HTTPShutdownDuration int
+	Env string `env:"ENV,required"`
+	// LogFormat is json, for shipping logs to an aggregator, or text, for reading them. LogLevel is the least severe
+	// level logged: debug, info, warn or error.
+	LogFormat string `env:"LOG_FORMAT,oneof=json text" default:"text"`
+	LogLevel  string `env:"LOG_LEVEL,oneof=debug info warn error" default:"info"`
+	// DBDriver selects where data is stored: DriverPostgres, the default, DriverSQLite or DriverMemory. The other DB
+	// fields are only required by DriverPostgres.
+	DBDriver string `env:"DATABASE_DRIVER,oneof=postgres sqlite memory" default:"postgres"`
+	// DBPath is the database file used by DriverSQLite.
+	DBPath     string `env:"DATABASE_PATH" default:"tech-challenge.db"`
+	DBName     string `env:"DATABASE_NAME,required_if=DATABASE_DRIVER:postgres"`
+	DBUser     string `env:"DATABASE_USER,required_if=DATABASE_DRIVER:postgres"`
+	DBPassword string `env:"DATABASE_PASSWORD,required_if=DATABASE_DRIVER:postgres,secret"`
+	DBHost     string `env:"DATABASE_HOST,required_if=DATABASE_DRIVER:postgres"`
+	DBPort     string `env:"DATABASE_PORT,required_if=DATABASE_DRIVER:postgres"`
+	HTTPDomain string `env:"HTTP_DOMAIN,required"`
+	HTTPPort   string `env:"HTTP_PORT,required"`
+	// HTTPShutdownDuration is how long in-flight requests get to finish when the server is asked to stop.
+	HTTPShutdownDuration time.Duration `env:"HTTP_SHUTDOWN_DURATION" default:"10s"`
+	// HTTPTrustProxyHeaders takes the client's address from the X-Forwarded-For or X-Real-IP header, which only a
+	// reverse proxy in front of the server may be trusted to set.
+	HTTPTrustProxyHeaders bool `env:"HTTP_TRUST_PROXY_HEADERS"`
+	// CORSAllowedOrigins are the origin patterns allowed to call the API from a browser, as a comma separated list.
+	CORSAllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" default:"https://*,http://*,ws://*"`
+	// DBTimeout is how long a request may spend querying the database before it fails with a 504.
+	DBTimeout time.Duration `env:"DATABASE_TIMEOUT,min=1ms" default:"5s"`
+	// DBMaxOpenConns and DBMaxIdleConns cap the Postgres connections the server keeps open and idle. 0 open means no
+	// limit.
+	DBMaxOpenConns int `env:"DATABASE_MAX_OPEN_CONNS,min=0" default:"25"`
+	DBMaxIdleConns int `env:"DATABASE_MAX_IDLE_CONNS,min=0" default:"5"`
+	// DBConnMaxLifetime and DBConnMaxIdleTime recycle Postgres connections that have been open or idle this long. 0
+	// keeps them forever.
+	DBConnMaxLifetime time.Duration `env:"DATABASE_CONN_MAX_LIFETIME" default:"30m"`
+	DBConnMaxIdleTime time.Duration `env:"DATABASE_CONN_MAX_IDLE_TIME" default:"5m"`
+	// DBConnectTimeout is how long the server keeps retrying to reach the database when it starts, 0 to try once.
+	DBConnectTimeout time.Duration `env:"DATABASE_CONNECT_TIMEOUT" default:"30s"`
+	// MigrateOnStart applies pending schema migrations before the server starts handling requests.
+	MigrateOnStart bool `env:"DATABASE_MIGRATE_ON_START"`
+	// AuthEnabled requires a bearer token or API key on every request except those for AuthPublicPaths. Tokens are
+	// JWTs signed with AuthJWTHMACSecret (HS256) or the key matching one of AuthJWTEd25519PublicKeys (EdDSA), and are
+	// not accepted if neither is set. Their roles and person_id claims decide what the caller may do, see auth.Policy.
+	AuthEnabled       bool   `env:"AUTH_ENABLED"`
+	AuthJWTHMACSecret string `env:"AUTH_JWT_HMAC_SECRET,secret"`
+	// AuthJWTEd25519PublicKeys is a comma separated list of PEM or base64 encoded public keys.
+	AuthJWTEd25519PublicKeys []string `env:"AUTH_JWT_ED25519_PUBLIC_KEYS"`
+	// AuthJWTIssuer and AuthJWTAudience, when set, must match the iss and aud claims of tokens.
+	AuthJWTIssuer   string `env:"AUTH_JWT_ISSUER"`
+	AuthJWTAudience string `env:"AUTH_JWT_AUDIENCE"`
+	// AuthPublicPaths are the paths anyone may request, as a comma separated list. A trailing * matches any suffix.
+	AuthPublicPaths []string `env:"AUTH_PUBLIC_PATHS" default:"/health"`
+	// RateLimit is how many requests each client may make, such as 300/m, or off. Clients are told apart by API key or
+	// token subject, or by address when they are not authenticated. See ratelimit.ParseLimit.
+	RateLimit string `env:"RATE_LIMIT" default:"300/m"`
+	// RateLimitRoutes are the routes limited differently, as a comma separated list of [METHOD] PATH=LIMIT rules such
+	// as GET /api/person=60/m. The first rule matching a request applies. See ratelimit.ParseRule.
+	RateLimitRoutes []string `env:"RATE_LIMIT_ROUTES" default:"GET /api/person=60/m"`
+	// RateLimitPerIP is how many requests each address may make before they are authenticated, such as 1200/m, or off.
+	// It also limits requests with bad credentials, so it should allow for several clients sharing an address.
+	RateLimitPerIP string `env:"RATE_LIMIT_PER_IP" default:"1200/m"`
 }
 
+// Values of DBDriver.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// Options are the parts of the command line that are not Config settings.
+type Options struct {
+	// PrintConfig asks for the effective Config to be printed with Config.Print instead of running.
+	PrintConfig bool
+	// Args are the arguments left after the flags, such as the migrate subcommand.
+	Args []string
+}
+
+// NewConfig loads the Config from the environment, after adding any variables set in .env, and from the file named
+// by CONFIG_FILE. It is Load without command-line arguments.
 func NewConfig() (Config, error) {
+	newConfig, _, err := Load(nil)
+	return newConfig, err
+}
+
+// Load parses the command-line arguments args, without the program name, and loads the Config. Every setting is
+// taken from the first of these sources that sets it:
+//
+//  1. a flag in args named after its variable, such as --database-timeout=30s
+//  2. the environment, after adding any variables set in .env, such as DATABASE_TIMEOUT=30s
+//  3. the YAML or TOML file named by --config or CONFIG_FILE, keyed by the lowercase variable, such as
+//     database_timeout: 30s
+//  4. the default tag of its field
+//
+// The error names every setting that is missing or malformed, and every unknown key in the file. It is
+// flag.ErrHelp if args asked for help, which has already been printed then.
+func Load(args []string) (Config, Options, error) {
 	godotenv.Load()
 
-	newConfig := Config{
-		Env:                  os.Getenv("ENV"),
-		DBName:               os.Getenv("DATABASE_NAME"),
-		DBUser:               os.Getenv("DATABASE_USER"),
-		DBPassword:           os.Getenv("DATABASE_PASSWORD"),
-		DBHost:               os.Getenv("DATABASE_HOST"),
-		DBPort:               os.Getenv("DATABASE_PORT"),
-		HTTPDomain:           os.Getenv("HTTP_DOMAIN"),
-		HTTPPort:             os.Getenv("HTTP_PORT"),
-		HTTPShutdownDuration: 10,
+	var opts Options
+	configType := reflect.TypeOf(Config{})
+	flagValues, file, err := parseFlags(args, configType, &opts)
+	if err != nil {
+		return Config{}, Options{}, err
 	}
-	if newConfig.Env == "" || newConfig.DBName == "" || newConfig.DBUser == "" ||
-		newConfig.DBPassword == "" || newConfig.DBHost == "" ||
-		newConfig.DBPort == "" || newConfig.HTTPDomain == "" || newConfig.HTTPPort == "" {
-		return Config{}, fmt.Errorf("missing required field")
+	sources := []func(string) string{
+		func(variable string) string { return flagValues[variable] },
+		os.Getenv,
+	}
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+	if file != "" {
+		fileValues, err := readFile(file, configType)
+		if err != nil {
+			return Config{}, Options{}, fmt.Errorf("invalid configuration:\n%w", err)
+		}
+		sources = append(sources, func(variable string) string { return fileValues[variable] })
 	}
 
-	return newConfig, nil
-
+	var newConfig Config
+	if err = load(&newConfig, firstSet(sources...)); err != nil {
+		return Config{}, Options{}, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return newConfig, opts, nil
 }
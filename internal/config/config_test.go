@@ -1,7 +1,12 @@
 package config
 
 import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -11,6 +16,8 @@ func TestConfig(t *testing.T) {
 		input        map[string]string
 		output       Config
 		expectsError bool
+		// errorNames are variables the error must mention
+		errorNames []string
 	}{
 		"success": {
 			input: map[string]string{
@@ -25,6 +32,10 @@ func TestConfig(t *testing.T) {
 			},
 			output: Config{
 				Env:                  "development",
+				LogFormat:            "text",
+				LogLevel:             "info",
+				DBDriver:             "postgres",
+				DBPath:               "tech-challenge.db",
 				DBName:               "test_db",
 				DBUser:               "test_user",
 				DBPassword:           "test_password",
@@ -32,9 +43,93 @@ func TestConfig(t *testing.T) {
 				DBPort:               "5432",
 				HTTPDomain:           "localhost",
 				HTTPPort:             "8000",
-				HTTPShutdownDuration: 10,
+				HTTPShutdownDuration: 10 * time.Second,
+				CORSAllowedOrigins:   []string{"https://*", "http://*", "ws://*"},
+				DBTimeout:            5 * time.Second,
+				DBMaxOpenConns:       25,
+				DBMaxIdleConns:       5,
+				DBConnMaxLifetime:    30 * time.Minute,
+				DBConnMaxIdleTime:    5 * time.Minute,
+				DBConnectTimeout:     30 * time.Second,
+				AuthPublicPaths:      []string{"/health"},
+				RateLimit:            "300/m",
+				RateLimitRoutes:      []string{"GET /api/person=60/m"},
+				RateLimitPerIP:       "1200/m",
 			},
 			expectsError: false},
+		"optional fields set": {
+			input: map[string]string{
+				"ENV":                       "development",
+				"DATABASE_NAME":             "test_db",
+				"DATABASE_USER":             "test_user",
+				"DATABASE_PASSWORD":         "test_password",
+				"DATABASE_HOST":             "localhost",
+				"DATABASE_PORT":             "5432",
+				"HTTP_DOMAIN":               "localhost",
+				"HTTP_PORT":                 "8000",
+				"DATABASE_TIMEOUT":          "30s",
+				"DATABASE_MIGRATE_ON_START": "true",
+				"DATABASE_MAX_OPEN_CONNS":   "50",
+				"DATABASE_CONNECT_TIMEOUT":  "1m",
+				"HTTP_TRUST_PROXY_HEADERS":  "true",
+				"RATE_LIMIT":                "off",
+				"RATE_LIMIT_ROUTES":         "GET /api/person=60/m, POST /api/*=10/s",
+				"LOG_FORMAT":                "json",
+				"LOG_LEVEL":                 "debug",
+			},
+			output: Config{
+				Env:                   "development",
+				LogFormat:             "json",
+				LogLevel:              "debug",
+				DBDriver:              "postgres",
+				DBPath:                "tech-challenge.db",
+				DBName:                "test_db",
+				DBUser:                "test_user",
+				DBPassword:            "test_password",
+				DBHost:                "localhost",
+				DBPort:                "5432",
+				HTTPDomain:            "localhost",
+				HTTPPort:              "8000",
+				HTTPShutdownDuration:  10 * time.Second,
+				HTTPTrustProxyHeaders: true,
+				CORSAllowedOrigins:    []string{"https://*", "http://*", "ws://*"},
+				DBTimeout:             30 * time.Second,
+				DBMaxOpenConns:        50,
+				DBMaxIdleConns:        5,
+				DBConnMaxLifetime:     30 * time.Minute,
+				DBConnMaxIdleTime:     5 * time.Minute,
+				DBConnectTimeout:      time.Minute,
+				MigrateOnStart:        true,
+				AuthPublicPaths:       []string{"/health"},
+				RateLimit:             "off",
+				RateLimitRoutes:       []string{"GET /api/person=60/m", "POST /api/*=10/s"},
+				RateLimitPerIP:        "1200/m",
+			},
+			expectsError: false},
+		"invalid database timeout": {
+			input: map[string]string{
+				"ENV":               "development",
+				"DATABASE_NAME":     "test_db",
+				"DATABASE_USER":     "test_user",
+				"DATABASE_PASSWORD": "test_password",
+				"DATABASE_HOST":     "localhost",
+				"DATABASE_PORT":     "5432",
+				"HTTP_DOMAIN":       "localhost",
+				"HTTP_PORT":         "8000",
+				"DATABASE_TIMEOUT":  "soon",
+			},
+			output:       Config{},
+			expectsError: true},
+		"invalid log format": {
+			input: map[string]string{
+				"ENV":             "development",
+				"DATABASE_DRIVER": "memory",
+				"HTTP_DOMAIN":     "localhost",
+				"HTTP_PORT":       "8000",
+				"LOG_FORMAT":      "xml",
+			},
+			output:       Config{},
+			expectsError: true},
 		"missing required field": {
 			input: map[string]string{
 				"ENV":               "development",
@@ -48,6 +143,158 @@ func TestConfig(t *testing.T) {
 			},
 			output:       Config{},
 			expectsError: true},
+		"memory driver without database settings": {
+			input: map[string]string{
+				"ENV":             "development",
+				"DATABASE_DRIVER": "memory",
+				"HTTP_DOMAIN":     "localhost",
+				"HTTP_PORT":       "8000",
+			},
+			output: Config{
+				Env:                  "development",
+				LogFormat:            "text",
+				LogLevel:             "info",
+				DBDriver:             "memory",
+				DBPath:               "tech-challenge.db",
+				HTTPDomain:           "localhost",
+				HTTPPort:             "8000",
+				HTTPShutdownDuration: 10 * time.Second,
+				CORSAllowedOrigins:   []string{"https://*", "http://*", "ws://*"},
+				DBTimeout:            5 * time.Second,
+				DBMaxOpenConns:       25,
+				DBMaxIdleConns:       5,
+				DBConnMaxLifetime:    30 * time.Minute,
+				DBConnMaxIdleTime:    5 * time.Minute,
+				DBConnectTimeout:     30 * time.Second,
+				AuthPublicPaths:      []string{"/health"},
+				RateLimit:            "300/m",
+				RateLimitRoutes:      []string{"GET /api/person=60/m"},
+				RateLimitPerIP:       "1200/m",
+			},
+			expectsError: false},
+		"sqlite driver with default path": {
+			input: map[string]string{
+				"ENV":             "development",
+				"DATABASE_DRIVER": "sqlite",
+				"HTTP_DOMAIN":     "localhost",
+				"HTTP_PORT":       "8000",
+			},
+			output: Config{
+				Env:                  "development",
+				LogFormat:            "text",
+				LogLevel:             "info",
+				DBDriver:             "sqlite",
+				DBPath:               "tech-challenge.db",
+				HTTPDomain:           "localhost",
+				HTTPPort:             "8000",
+				HTTPShutdownDuration: 10 * time.Second,
+				CORSAllowedOrigins:   []string{"https://*", "http://*", "ws://*"},
+				DBTimeout:            5 * time.Second,
+				DBMaxOpenConns:       25,
+				DBMaxIdleConns:       5,
+				DBConnMaxLifetime:    30 * time.Minute,
+				DBConnMaxIdleTime:    5 * time.Minute,
+				DBConnectTimeout:     30 * time.Second,
+				AuthPublicPaths:      []string{"/health"},
+				RateLimit:            "300/m",
+				RateLimitRoutes:      []string{"GET /api/person=60/m"},
+				RateLimitPerIP:       "1200/m",
+			},
+			expectsError: false},
+		"sqlite driver with path": {
+			input: map[string]string{
+				"ENV":             "development",
+				"DATABASE_DRIVER": "sqlite",
+				"DATABASE_PATH":   "/tmp/courses.db",
+				"HTTP_DOMAIN":     "localhost",
+				"HTTP_PORT":       "8000",
+			},
+			output: Config{
+				Env:                  "development",
+				LogFormat:            "text",
+				LogLevel:             "info",
+				DBDriver:             "sqlite",
+				DBPath:               "/tmp/courses.db",
+				HTTPDomain:           "localhost",
+				HTTPPort:             "8000",
+				HTTPShutdownDuration: 10 * time.Second,
+				CORSAllowedOrigins:   []string{"https://*", "http://*", "ws://*"},
+				DBTimeout:            5 * time.Second,
+				DBMaxOpenConns:       25,
+				DBMaxIdleConns:       5,
+				DBConnMaxLifetime:    30 * time.Minute,
+				DBConnMaxIdleTime:    5 * time.Minute,
+				DBConnectTimeout:     30 * time.Second,
+				AuthPublicPaths:      []string{"/health"},
+				RateLimit:            "300/m",
+				RateLimitRoutes:      []string{"GET /api/person=60/m"},
+				RateLimitPerIP:       "1200/m",
+			},
+			expectsError: false},
+		"unknown driver": {
+			input: map[string]string{
+				"ENV":             "development",
+				"DATABASE_DRIVER": "mongodb",
+				"HTTP_DOMAIN":     "localhost",
+				"HTTP_PORT":       "8000",
+			},
+			output:       Config{},
+			expectsError: true},
+		"every problem reported": {
+			input: map[string]string{
+				"ENV":              "development",
+				"DATABASE_NAME":    "test_db",
+				"DATABASE_TIMEOUT": "0",
+				"HTTP_PORT":        "8000",
+			},
+			output:       Config{},
+			expectsError: true,
+			errorNames:   []string{"DATABASE_USER", "DATABASE_PASSWORD", "DATABASE_HOST", "DATABASE_PORT", "HTTP_DOMAIN", "DATABASE_TIMEOUT"}},
+		"shutdown duration and origins set": {
+			input: map[string]string{
+				"ENV":                    "development",
+				"DATABASE_DRIVER":        "memory",
+				"HTTP_DOMAIN":            "localhost",
+				"HTTP_PORT":              "8000",
+				"HTTP_SHUTDOWN_DURATION": "1m30s",
+				"CORS_ALLOWED_ORIGINS":   "https://example.com, https://*.example.com",
+			},
+			output: Config{
+				Env:                  "development",
+				LogFormat:            "text",
+				LogLevel:             "info",
+				DBDriver:             "memory",
+				DBPath:               "tech-challenge.db",
+				HTTPDomain:           "localhost",
+				HTTPPort:             "8000",
+				HTTPShutdownDuration: 90 * time.Second,
+				CORSAllowedOrigins:   []string{"https://example.com", "https://*.example.com"},
+				DBTimeout:            5 * time.Second,
+				DBMaxOpenConns:       25,
+				DBMaxIdleConns:       5,
+				DBConnMaxLifetime:    30 * time.Minute,
+				DBConnMaxIdleTime:    5 * time.Minute,
+				DBConnectTimeout:     30 * time.Second,
+				AuthPublicPaths:      []string{"/health"},
+				RateLimit:            "300/m",
+				RateLimitRoutes:      []string{"GET /api/person=60/m"},
+				RateLimitPerIP:       "1200/m",
+			},
+			expectsError: false},
+		"invalid migrate on start": {
+			input: map[string]string{
+				"ENV":                       "development",
+				"DATABASE_NAME":             "test_db",
+				"DATABASE_USER":             "test_user",
+				"DATABASE_PASSWORD":         "test_password",
+				"DATABASE_HOST":             "localhost",
+				"DATABASE_PORT":             "5432",
+				"HTTP_DOMAIN":               "localhost",
+				"HTTP_PORT":                 "8000",
+				"DATABASE_MIGRATE_ON_START": "sometimes",
+			},
+			output:       Config{},
+			expectsError: true},
 	}
 
 	for name, testConditions := range tests {
@@ -62,6 +309,9 @@ func TestConfig(t *testing.T) {
 
 			if testConditions.expectsError {
 				assert.Error(t, err)
+				for _, name := range testConditions.errorNames {
+					assert.ErrorContains(t, err, name)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
@@ -69,3 +319,162 @@ func TestConfig(t *testing.T) {
 		})
 	}
 }
+
+// writeConfigFile writes content to a config file named name in a temporary directory and returns its path.
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeConfigFile(t, "config.yaml", `
+env: file
+database_driver: memory
+http_domain: file.example.com
+http_port: "9000"
+http_shutdown_duration: 20s
+cors_allowed_origins: [https://a.example.com, https://b.example.com]
+database_timeout: 7s
+`)
+	// the file's settings only apply while the environment leaves them empty
+	for _, variable := range []string{"ENV", "DATABASE_DRIVER", "HTTP_DOMAIN", "HTTP_SHUTDOWN_DURATION", "CORS_ALLOWED_ORIGINS", "CONFIG_FILE"} {
+		t.Setenv(variable, "")
+	}
+	t.Setenv("HTTP_PORT", "8000")
+	t.Setenv("DATABASE_TIMEOUT", "8s")
+
+	cfg, opts, err := Load([]string{"--config", file, "--database-timeout=9s", "--database-migrate-on-start", "migrate", "up"})
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		Env:                  "file",
+		LogFormat:            "text",
+		LogLevel:             "info",
+		DBDriver:             "memory",
+		DBPath:               "tech-challenge.db",
+		HTTPDomain:           "file.example.com",
+		HTTPPort:             "8000",
+		HTTPShutdownDuration: 20 * time.Second,
+		CORSAllowedOrigins:   []string{"https://a.example.com", "https://b.example.com"},
+		DBTimeout:            9 * time.Second,
+		DBMaxOpenConns:       25,
+		DBMaxIdleConns:       5,
+		DBConnMaxLifetime:    30 * time.Minute,
+		DBConnMaxIdleTime:    5 * time.Minute,
+		DBConnectTimeout:     30 * time.Second,
+		MigrateOnStart:       true,
+		AuthPublicPaths:      []string{"/health"},
+		RateLimit:            "300/m",
+		RateLimitRoutes:      []string{"GET /api/person=60/m"},
+		RateLimitPerIP:       "1200/m",
+	}, cfg)
+	assert.Equal(t, Options{Args: []string{"migrate", "up"}}, opts)
+
+	t.Setenv("CONFIG_FILE", file)
+	cfg, opts, err = Load([]string{"--print-config"})
+	assert.NoError(t, err)
+	assert.Equal(t, "file", cfg.Env)
+	assert.Equal(t, 8*time.Second, cfg.DBTimeout)
+	assert.True(t, opts.PrintConfig)
+}
+
+func TestLoadTOMLFile(t *testing.T) {
+	file := writeConfigFile(t, "config.toml", `
+env = "file"
+database_driver = "memory"
+http_domain = "file.example.com"
+http_port = 9000
+http_trust_proxy_headers = true
+cors_allowed_origins = ["https://a.example.com", "https://b.example.com"]
+database_timeout = "7s"
+database_max_open_conns = 10
+`)
+	for _, variable := range []string{"ENV", "DATABASE_DRIVER", "HTTP_DOMAIN", "HTTP_PORT", "HTTP_TRUST_PROXY_HEADERS",
+		"CORS_ALLOWED_ORIGINS", "DATABASE_TIMEOUT", "DATABASE_MAX_OPEN_CONNS", "CONFIG_FILE"} {
+		t.Setenv(variable, "")
+	}
+
+	cfg, _, err := Load([]string{"--config", file})
+	assert.NoError(t, err)
+	assert.Equal(t, "file", cfg.Env)
+	assert.Equal(t, "memory", cfg.DBDriver)
+	assert.Equal(t, "file.example.com", cfg.HTTPDomain)
+	assert.Equal(t, "9000", cfg.HTTPPort)
+	assert.True(t, cfg.HTTPTrustProxyHeaders)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.CORSAllowedOrigins)
+	assert.Equal(t, 7*time.Second, cfg.DBTimeout)
+	assert.Equal(t, 10, cfg.DBMaxOpenConns)
+}
+
+func TestLoadFailures(t *testing.T) {
+	tests := map[string]struct {
+		file       string
+		content    string
+		args       []string
+		errorNames []string
+	}{
+		"unknown file key":     {file: "config.yaml", content: "env: dev\ndatabase_timout: 5\nENV: dev\n", errorNames: []string{`unknown setting "database_timout"`, `unknown setting "ENV"`}},
+		"nested file value":    {file: "config.yaml", content: "env:\n  name: dev\n", errorNames: []string{"env must be a single value or a list"}},
+		"malformed file":       {file: "config.yml", content: "env: [dev\n", errorNames: []string{"failed to parse config file"}},
+		"nested toml value":    {file: "config.toml", content: "[env]\nname = \"dev\"\n", errorNames: []string{"env must be a single value or a list"}},
+		"malformed toml":       {file: "config.toml", content: "env = [\"dev\"\n", errorNames: []string{"failed to parse config file"}},
+		"unknown format":       {file: "config.json", content: `{"env": "dev"}`, errorNames: []string{"must be YAML or TOML"}},
+		"malformed file value": {file: "config.yaml", content: "env: dev\ndatabase_timeout: soon\n", errorNames: []string{"DATABASE_TIMEOUT must be a duration"}},
+		"missing file":         {args: []string{"--config", "missing.yaml"}, errorNames: []string{"failed to read config file"}},
+		"unknown flag":         {args: []string{"--database-timout=5"}, errorNames: []string{"database-timout"}},
+		"malformed flag":       {args: []string{"--http-shutdown-duration=10"}, errorNames: []string{"HTTP_SHUTDOWN_DURATION must be a duration"}},
+	}
+	for name, testConditions := range tests {
+		t.Run(name, func(t *testing.T) {
+			args := testConditions.args
+			if testConditions.file != "" {
+				args = append([]string{"--config", writeConfigFile(t, testConditions.file, testConditions.content)}, args...)
+			}
+			_, _, err := Load(args)
+			assert.Error(t, err)
+			for _, name := range testConditions.errorNames {
+				assert.ErrorContains(t, err, name)
+			}
+		})
+	}
+}
+
+func TestLoadHelp(t *testing.T) {
+	_, _, err := Load([]string{"-h"})
+	assert.ErrorIs(t, err, flag.ErrHelp)
+}
+
+func TestConfigPrint(t *testing.T) {
+	cfg := Config{
+		Env:                  "development",
+		LogFormat:            "text",
+		LogLevel:             "info",
+		DBDriver:             "postgres",
+		DBPath:               "tech-challenge.db",
+		DBName:               "test_db",
+		DBUser:               "test_user",
+		DBPassword:           "hunter2",
+		DBHost:               "localhost",
+		DBPort:               "5432",
+		HTTPDomain:           "localhost",
+		HTTPPort:             "8000",
+		HTTPShutdownDuration: 90 * time.Second,
+		CORSAllowedOrigins:   []string{"https://*"},
+		DBTimeout:            5 * time.Second,
+		AuthPublicPaths:      []string{"/health"},
+		RateLimit:            "300/m",
+		RateLimitRoutes:      []string{"GET /api/person=60/m"},
+		RateLimitPerIP:       "1200/m",
+	}
+	var out bytes.Buffer
+	assert.NoError(t, cfg.Print(&out))
+	assert.Contains(t, out.String(), "database_password: REDACTED\n")
+	assert.Contains(t, out.String(), "http_shutdown_duration: 1m30s\n")
+	assert.NotContains(t, out.String(), "hunter2")
+
+	// the output is a config file that loads back into the same Config, apart from the secret
+	t.Setenv("DATABASE_PASSWORD", "hunter2")
+	loaded, _, err := Load([]string{"--config", writeConfigFile(t, "printed.yaml", out.String())})
+	assert.NoError(t, err)
+	assert.Equal(t, cfg, loaded)
+}
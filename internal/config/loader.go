@@ -0,0 +1,191 @@
+package config
+
+//loader.go fills a struct from variables according to its env and default struct tags. Where the variables come from is
+//up to the caller, see ./sources.go.
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field is a struct field with an env tag, such as
+//
+//	DBTimeout time.Duration `env:"DATABASE_TIMEOUT,min=1ms" default:"5s"`
+//
+// The tag names the variable, followed by any of these options:
+//
+//	required                the variable must be set
+//	required_if=OTHER:value the variable must be set when variable OTHER, after defaults, equals value
+//	oneof=a b c             the value must be one of the space separated words
+//	min=n                   the integer or duration value must be at least n, such as min=1 or min=1ms
+//	secret                  the value is redacted by Config.Print
+type field struct {
+	value      reflect.Value
+	name       string
+	raw        string
+	secret     bool
+	required   bool
+	requiredIf []string
+	oneOf      []string
+	min        *int64
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// load sets every field of the struct cfg points to that has an env tag, reading variables with getenv. Empty
+// variables count as unset and take the field's default tag, if it has one. The returned error lists every variable
+// that is missing or malformed.
+func load(cfg any, getenv func(string) string) error {
+	fields, err := parseFields(reflect.ValueOf(cfg).Elem(), getenv)
+	if err != nil {
+		return err
+	}
+	// requirements may depend on the values of other variables, so every value is read before any is checked
+	values := make(map[string]string, len(fields))
+	for _, f := range fields {
+		values[f.name] = f.raw
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if f.raw == "" {
+			if f.required || (f.requiredIf != nil && values[f.requiredIf[0]] == f.requiredIf[1]) {
+				errs = append(errs, fmt.Errorf("%s is required", f.name))
+			}
+			continue
+		}
+		if f.oneOf != nil && !slices.Contains(f.oneOf, f.raw) {
+			errs = append(errs, fmt.Errorf("%s must be one of %s, got %q", f.name, strings.Join(f.oneOf, ", "), f.raw))
+			continue
+		}
+		if err := f.set(); err != nil {
+			errs = append(errs, fmt.Errorf("%s %w, got %q", f.name, err, f.raw))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseFields reads the env and default tags of the fields of v, looking up each variable with getenv.
+func parseFields(v reflect.Value, getenv func(string) string) ([]field, error) {
+	var fields []field
+	for i := range v.NumField() {
+		tag, ok := v.Type().Field(i).Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		options := strings.Split(tag, ",")
+		f := field{value: v.Field(i), name: options[0], raw: getenv(options[0])}
+		if f.raw == "" {
+			f.raw = v.Type().Field(i).Tag.Get("default")
+		}
+		for _, option := range options[1:] {
+			key, arg, _ := strings.Cut(option, "=")
+			switch key {
+			case "required":
+				f.required = true
+			case "secret":
+				f.secret = true
+			case "required_if":
+				other, value, ok := strings.Cut(arg, ":")
+				if !ok {
+					return nil, fmt.Errorf("%s: required_if needs VARIABLE:value, got %q", f.name, arg)
+				}
+				f.requiredIf = []string{other, value}
+			case "oneof":
+				f.oneOf = strings.Fields(arg)
+			case "min":
+				if f.value.Type() == durationType {
+					d, err := time.ParseDuration(arg)
+					if err != nil {
+						return nil, fmt.Errorf("%s: min needs a duration, got %q", f.name, arg)
+					}
+					n := int64(d)
+					f.min = &n
+					break
+				}
+				n, err := strconv.ParseInt(arg, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%s: min needs an integer, got %q", f.name, arg)
+				}
+				f.min = &n
+			default:
+				return nil, fmt.Errorf("%s: unknown env tag option %q", f.name, key)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// variables returns the type of every env tagged field of t, a struct type, keyed by its variable name.
+func variables(t reflect.Type) map[string]reflect.Type {
+	types := make(map[string]reflect.Type)
+	for i := range t.NumField() {
+		if tag, ok := t.Field(i).Tag.Lookup("env"); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			types[name] = t.Field(i).Type
+		}
+	}
+	return types
+}
+
+// firstSet looks a variable up in each of sources in turn and returns the first value that is not empty.
+func firstSet(sources ...func(string) string) func(string) string {
+	return func(name string) string {
+		for _, source := range sources {
+			if value := source(name); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+}
+
+// set parses f.raw into f.value according to the field's type. The error completes a sentence starting with the
+// variable's name.
+func (f field) set() error {
+	switch {
+	case f.value.Type() == durationType:
+		d, err := time.ParseDuration(f.raw)
+		if err != nil {
+			return errors.New("must be a duration such as 30s or 1m")
+		}
+		if f.min != nil && int64(d) < *f.min {
+			return fmt.Errorf("must be at least %s", time.Duration(*f.min))
+		}
+		f.value.SetInt(int64(d))
+	case f.value.Kind() == reflect.String:
+		f.value.SetString(f.raw)
+	case f.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(f.raw)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		f.value.SetBool(b)
+	case f.value.CanInt():
+		n, err := strconv.ParseInt(f.raw, 10, f.value.Type().Bits())
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		if f.min != nil && n < *f.min {
+			return fmt.Errorf("must be at least %d", *f.min)
+		}
+		f.value.SetInt(n)
+	case f.value.Kind() == reflect.Slice && f.value.Type().Elem().Kind() == reflect.String:
+		var list []string
+		for _, item := range strings.Split(f.raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		f.value.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("has unsupported type %s", f.value.Type())
+	}
+	return nil
+}
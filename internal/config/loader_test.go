@@ -0,0 +1,101 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type loaderTestConfig struct {
+	Name     string        `env:"NAME,required"`
+	Mode     string        `env:"MODE,oneof=fast slow" default:"fast"`
+	Token    string        `env:"TOKEN,required_if=MODE:slow"`
+	Workers  int           `env:"WORKERS,min=1" default:"4"`
+	Retries  int8          `env:"RETRIES"`
+	Verbose  bool          `env:"VERBOSE"`
+	Interval time.Duration `env:"INTERVAL,min=100ms" default:"1s"`
+	Hosts    []string      `env:"HOSTS"`
+	Ignored  string
+}
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		input        map[string]string
+		output       loaderTestConfig
+		expectsError bool
+		errorNames   []string
+	}{
+		"defaults": {
+			input:  map[string]string{"NAME": "api"},
+			output: loaderTestConfig{Name: "api", Mode: "fast", Workers: 4, Interval: time.Second},
+		},
+		"every type set": {
+			input: map[string]string{"NAME": "api", "MODE": "slow", "TOKEN": "secret", "WORKERS": "8", "RETRIES": "-3",
+				"VERBOSE": "true", "INTERVAL": "250ms", "HOSTS": " a.example.com,,b.example.com ", "Ignored": "x"},
+			output: loaderTestConfig{Name: "api", Mode: "slow", Token: "secret", Workers: 8, Retries: -3, Verbose: true,
+				Interval: 250 * time.Millisecond, Hosts: []string{"a.example.com", "b.example.com"}},
+		},
+		"empty counts as unset": {
+			input:  map[string]string{"NAME": "api", "WORKERS": ""},
+			output: loaderTestConfig{Name: "api", Mode: "fast", Workers: 4, Interval: time.Second},
+		},
+		"required if met": {
+			input:        map[string]string{"NAME": "api", "MODE": "slow"},
+			expectsError: true,
+			errorNames:   []string{"TOKEN is required"},
+		},
+		"duration below min": {
+			input:        map[string]string{"NAME": "api", "INTERVAL": "10ms"},
+			expectsError: true,
+			errorNames:   []string{"INTERVAL must be at least 100ms"},
+		},
+		"every malformed variable": {
+			input: map[string]string{"MODE": "medium", "WORKERS": "0", "RETRIES": "300", "VERBOSE": "yes please",
+				"INTERVAL": "5"},
+			expectsError: true,
+			errorNames: []string{"NAME is required", "MODE must be one of fast, slow", "WORKERS must be at least 1",
+				"RETRIES must be an integer", "VERBOSE must be true or false", "INTERVAL must be a duration"},
+		},
+	}
+	for name, testConditions := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg loaderTestConfig
+			err := load(&cfg, func(key string) string { return testConditions.input[key] })
+			if testConditions.expectsError {
+				assert.Error(t, err)
+				for _, name := range testConditions.errorNames {
+					assert.ErrorContains(t, err, name)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testConditions.output, cfg)
+			}
+		})
+	}
+}
+
+func TestLoadBadTags(t *testing.T) {
+	tests := map[string]any{
+		"unknown option": &struct {
+			A string `env:"A,hidden"`
+		}{},
+		"bad required_if": &struct {
+			A string `env:"A,required_if=B"`
+		}{},
+		"bad min": &struct {
+			A int `env:"A,min=one"`
+		}{},
+		"bad duration min": &struct {
+			A time.Duration `env:"A,min=1"`
+		}{},
+		"unsupported type": &struct {
+			A float64 `env:"A" default:"1.5"`
+		}{},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, load(cfg, func(string) string { return "" }))
+		})
+	}
+}
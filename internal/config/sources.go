@@ -0,0 +1,146 @@
+package config
+
+//sources.go defines the command-line flags and YAML or TOML config file NewConfig reads besides the environment, and
+//Config.Print, which writes a Config back out in the YAML config file format.
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces the values of secret fields in Config.Print.
+const redacted = "REDACTED"
+
+// flagName returns the command-line flag for a variable: DATABASE_TIMEOUT is --database-timeout.
+func flagName(variable string) string {
+	return strings.ReplaceAll(strings.ToLower(variable), "_", "-")
+}
+
+// fileKey returns the config file key for a variable: DATABASE_TIMEOUT is database_timeout.
+func fileKey(variable string) string {
+	return strings.ToLower(variable)
+}
+
+// flagValue collects the value of one variable's flag into values.
+type flagValue struct {
+	values   map[string]string
+	variable string
+	isBool   bool
+}
+
+func (v *flagValue) String() string {
+	return v.values[v.variable]
+}
+func (v *flagValue) Set(value string) error {
+	v.values[v.variable] = value
+	return nil
+}
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+// parseFlags parses args into opts and returns the values of the variables given as flags, and the config file named
+// by --config. Every variable of the struct type t gets a flag.
+func parseFlags(args []string, t reflect.Type, opts *Options) (map[string]string, string, error) {
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	var file string
+	fs.StringVar(&file, "config", "", "YAML or TOML `file` to read settings from, overridden by the environment and flags (env CONFIG_FILE)")
+	fs.BoolVar(&opts.PrintConfig, "print-config", false, "print the effective configuration, with secrets redacted, and exit")
+	values := make(map[string]string)
+	for variable, typ := range variables(t) {
+		fs.Var(&flagValue{values: values, variable: variable, isBool: typ.Kind() == reflect.Bool}, flagName(variable), "overrides "+variable)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, "", err
+	}
+	opts.Args = fs.Args()
+	return values, file, nil
+}
+
+// readFile reads the config file at path, YAML if it ends in .yaml or .yml and TOML if it ends in .toml, and returns
+// its values keyed by variable name. Every key must be the file key of one of the variables of the struct type t, and
+// hold a single value or a list.
+func readFile(path string, t reflect.Type) (map[string]string, error) {
+	var unmarshal func([]byte, any) error
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	case ".toml":
+		unmarshal = toml.Unmarshal
+	default:
+		return nil, fmt.Errorf("config file %s must be YAML or TOML, ending in .yaml, .yml or .toml", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc map[string]any
+	if err = unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	known := variables(t)
+	values := make(map[string]string, len(doc))
+	var errs []error
+	for key, value := range doc {
+		variable := strings.ToUpper(key)
+		if _, ok := known[variable]; !ok || key != fileKey(variable) {
+			errs = append(errs, fmt.Errorf("%s: unknown setting %q", path, key))
+			continue
+		}
+		switch value := value.(type) {
+		case nil:
+		case []any:
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			values[variable] = strings.Join(items, ",")
+		case map[string]any:
+			errs = append(errs, fmt.Errorf("%s: %s must be a single value or a list", path, key))
+		default:
+			values[variable] = fmt.Sprint(value)
+		}
+	}
+	return values, errors.Join(errs...)
+}
+
+// Print writes c to w in the config file format, in field order. The values of secret fields are replaced by
+// REDACTED unless they are empty, so the output shows whether a secret is set without revealing it.
+func (c Config) Print(w io.Writer) error {
+	fields, err := parseFields(reflect.ValueOf(&c).Elem(), func(string) string { return "" })
+	if err != nil {
+		return err
+	}
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range fields {
+		value := f.value.Interface()
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		if f.secret && !f.value.IsZero() {
+			value = redacted
+		}
+		var node yaml.Node
+		if err = node.Encode(value); err != nil {
+			return fmt.Errorf("failed to print %s: %w", f.name, err)
+		}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: fileKey(f.name)}, &node)
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
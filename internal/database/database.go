@@ -1,27 +1,120 @@
 package database
 
-//database.go initiates a connection to the local sql DB.
+//database.go initiates a connection to the Postgres or SQLite database, retrying until it is reachable.
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
 	_ "github.com/lib/pq"
+	"modernc.org/sqlite"
 )
 
-func NewDatabase(connectionString string) (*sql.DB, error) {
-	log.Println("Connecting to the database")
-	db, err := sql.Open("postgres", connectionString)
+// Drivers NewDatabase can connect with. Each is also the name of the directory holding its migrations and seed.
+const (
+	Postgres = "postgres"
+	SQLite   = "sqlite"
+)
+
+// The services sort text with COLLATE "C", the bytewise order Postgres has built in, so both databases order names
+// the same whatever the locale of the Postgres server.
+func init() {
+	sqlite.MustRegisterCollationUtf8("C", strings.Compare)
+}
+
+// Options configures the connection pool of NewDatabase and how long it waits for the database to come up.
+type Options struct {
+	// MaxOpenConns and MaxIdleConns cap the open and idle connections. 0 means no limit on open connections, and no
+	// idle connections.
+	MaxOpenConns int
+	MaxIdleConns int
+	// ConnMaxLifetime and ConnMaxIdleTime close connections that have been open or idle this long. 0 keeps them.
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// ConnectTimeout is how long NewDatabase keeps retrying the first connection. 0 tries only once.
+	ConnectTimeout time.Duration
+}
+
+// The delay between connection attempts starts at retryInitialBackoff and doubles after every failure, up to
+// retryMaxBackoff. They are variables so tests can retry faster.
+var (
+	retryInitialBackoff = 500 * time.Millisecond
+	retryMaxBackoff     = 10 * time.Second
+)
+
+// NewDatabase connects to a database with driver. For Postgres, connectionString is a libpq connection string. For
+// SQLite it is the path of the database file, or ":memory:" for a database that lives as long as the returned *sql.DB.
+// The pool settings in opts only apply to Postgres, since SQLite always uses a single connection.
+func NewDatabase(driver string, connectionString string, opts Options) (*sql.DB, error) {
+	slog.Info("Connecting to the database", slog.String("driver", driver))
+	if driver == SQLite {
+		connectionString = sqliteDSN(connectionString)
+	}
+	db, err := sql.Open(driver, connectionString)
 	if err != nil {
-		log.Println(err)
+		slog.Error("Could not open the database", slog.String("error", err.Error()))
 		return db, err
 	}
+	if driver == SQLite {
+		// SQLite allows a single writer, and every new connection to ":memory:" would open an empty database
+		db.SetMaxOpenConns(1)
+	} else {
+		db.SetMaxOpenConns(opts.MaxOpenConns)
+		db.SetMaxIdleConns(opts.MaxIdleConns)
+		db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+		db.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+	}
 
-	log.Println("Pinging the database")
-	if err := db.Ping(); err != nil {
-		log.Println(err)
+	slog.Info("Pinging the database")
+	if err := ping(db, opts.ConnectTimeout); err != nil {
+		slog.Error("Could not reach the database", slog.String("error", err.Error()))
 		return db, err
 	}
-	log.Println("Successfully connected to the database")
+	slog.Info("Successfully connected to the database")
 	return db, nil
 }
+
+// ping pings db until it answers, waiting longer after each failed attempt, and gives up once timeout has passed.
+func ping(db *sql.DB, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	backoff := retryInitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		if timeout <= 0 {
+			return err
+		}
+		slog.Warn("Database is not ready, retrying",
+			slog.Int("attempt", attempt),
+			slog.Duration("backoff", backoff),
+			slog.String("error", err.Error()))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up connecting to the database after %d attempts in %s: %w", attempt, timeout, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, retryMaxBackoff)
+	}
+}
+
+// sqliteDSN adds the pragmas every SQLite connection needs to path. SQLite ignores foreign keys unless asked, and
+// the services rely on them as much as on Postgres'.
+func sqliteDSN(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
@@ -1,18 +1,25 @@
 package database
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestDatabase(t *testing.T) {
 	tests := map[string]struct {
+		driver       string
 		input        string
 		expectsError bool
 	}{
 		// "successful connect": {
+		// 	driver: Postgres,
 		// 	input: fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
 		// 		"localhost",
 		// 		"courses-db-user",
@@ -22,6 +29,7 @@ func TestDatabase(t *testing.T) {
 		// 	expectsError: false,
 		// },
 		"unsuccessful connect": {
+			driver: Postgres,
 			input: fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
 				"",
 				"courses-db-user",
@@ -30,16 +38,83 @@ func TestDatabase(t *testing.T) {
 				"5432"),
 			expectsError: true,
 		},
+		"sqlite in memory": {
+			driver:       SQLite,
+			input:        ":memory:",
+			expectsError: false,
+		},
+		"unknown driver": {
+			driver:       "mysql",
+			input:        "courses-db-user:courses-db-password@/coursesDB",
+			expectsError: true,
+		},
 	}
 	for testName, testConditions := range tests {
 		t.Run(testName, func(t *testing.T) {
-			_, err := NewDatabase(testConditions.input)
+			db, err := NewDatabase(testConditions.driver, testConditions.input, Options{})
 			if testConditions.expectsError {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				db.Close()
 			}
 		})
 	}
 
 }
+
+// unreadyDriver refuses the first failures connections it is asked for, like a database that is still starting.
+type unreadyDriver struct {
+	failures int32
+	opened   atomic.Int32
+}
+
+func (d *unreadyDriver) Open(string) (driver.Conn, error) {
+	if d.opened.Add(1) <= d.failures {
+		return nil, errors.New("connection refused")
+	}
+	return unreadyConn{}, nil
+}
+
+type unreadyConn struct{ driver.Conn }
+
+func (unreadyConn) Close() error { return nil }
+
+func TestDatabaseRetry(t *testing.T) {
+	initial, maximum := retryInitialBackoff, retryMaxBackoff
+	retryInitialBackoff, retryMaxBackoff = time.Millisecond, 4*time.Millisecond
+	t.Cleanup(func() { retryInitialBackoff, retryMaxBackoff = initial, maximum })
+
+	tests := map[string]struct {
+		failures       int32
+		connectTimeout time.Duration
+		expectsError   bool
+		expectsOpened  int32
+	}{
+		"ready":                    {failures: 0, connectTimeout: time.Second, expectsOpened: 1},
+		"ready after retries":      {failures: 5, connectTimeout: time.Second, expectsOpened: 6},
+		"no retries":               {failures: 1, connectTimeout: 0, expectsError: true, expectsOpened: 1},
+		"not ready before timeout": {failures: 1 << 30, connectTimeout: 50 * time.Millisecond, expectsError: true},
+	}
+	for testName, testConditions := range tests {
+		t.Run(testName, func(t *testing.T) {
+			unready := &unreadyDriver{failures: testConditions.failures}
+			driverName := "unready " + testName
+			sql.Register(driverName, unready)
+
+			db, err := NewDatabase(driverName, "", Options{ConnectTimeout: testConditions.connectTimeout})
+			defer db.Close()
+			if testConditions.expectsError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if testConditions.expectsOpened > 0 {
+				assert.Equal(t, testConditions.expectsOpened, unready.opened.Load())
+			} else {
+				assert.ErrorContains(t, err, "gave up connecting to the database")
+				assert.Greater(t, unready.opened.Load(), int32(1))
+			}
+		})
+	}
+}
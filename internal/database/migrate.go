@@ -0,0 +1,241 @@
+package database
+
+//migrate.go defines the Migrator, which applies the versioned schema migrations in ./migrations/<driver>, and Seed, which loads the example data in ./seed/<driver>.sql.
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+//go:embed migrations/*/*.sql
+var migrationFiles embed.FS
+
+//go:embed seed/*.sql
+var seedFiles embed.FS
+
+// migrationLockID is the key of the Postgres advisory lock held while migrating, so concurrent runners apply each
+// migration once. The value is arbitrary but must stay the same across releases. SQLite has no such lock, but it also
+// only allows one writer at a time.
+const migrationLockID = 5_381_442_017
+
+// migrationName matches migration file names such as 0001_create_schema.up.sql.
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is one version of the schema. Up moves the schema to Version and Down moves it back to the version before.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrator applies migrations to a database, recording the applied versions in the schema_migrations table.
+type Migrator struct {
+	db         *sql.DB
+	driver     string
+	migrations []Migration
+}
+
+// NewMigrator returns a Migrator for the migrations embedded from ./migrations/<driver>, where driver is Postgres or
+// SQLite.
+func NewMigrator(db *sql.DB, driver string) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, path.Join("migrations", driver))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, driver: driver, migrations: migrations}, nil
+}
+
+// loadMigrations reads the migrations in directory dir of fsys, sorted by version. Every version needs both an up and
+// a down script.
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s is not named <version>_<name>.<up|down>.sql", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		script, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, version := range slices.Sorted(maps.Keys(byVersion)) {
+		migration := byVersion[version]
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down script", version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	return migrations, nil
+}
+
+// Up applies every migration that has not been applied yet, oldest first, and returns how many it applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *sql.Conn, versions []int) error {
+		for _, migration := range m.migrations {
+			if slices.Contains(versions, migration.Version) {
+				continue
+			}
+			err := runInTx(ctx, conn, migration.Up,
+				`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			slog.InfoContext(ctx, "Applied migration", slog.Int("version", migration.Version), slog.String("name", migration.Name))
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the latest steps applied migrations, newest first, and returns how many it reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.withLock(ctx, func(conn *sql.Conn, versions []int) error {
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if !slices.Contains(versions, migration.Version) {
+				continue
+			}
+			err := runInTx(ctx, conn, migration.Down,
+				`DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+			if err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			slog.InfoContext(ctx, "Reverted migration", slog.Int("version", migration.Version), slog.String("name", migration.Name))
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Version returns the newest applied migration version, or 0 if none have been applied.
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	version := 0
+	err := m.withLock(ctx, func(conn *sql.Conn, versions []int) error {
+		if len(versions) > 0 {
+			version = slices.Max(versions)
+		}
+		return nil
+	})
+	return version, err
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock, passing it the applied versions.
+// The lock is tied to the connection, which is why every statement must go through conn. Only Postgres is locked.
+func (m *Migrator) withLock(ctx context.Context, fn func(*sql.Conn, []int) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if m.driver == Postgres {
+		if _, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+		defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+	}
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations
+							(
+								version    INTEGER PRIMARY KEY,
+								name       TEXT        NOT NULL,
+								applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+							)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	defer rows.Close()
+	var versions []int
+	for rows.Next() {
+		var version int
+		if err = rows.Scan(&version); err != nil {
+			return fmt.Errorf("failed to scan migration version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	// conn is busy until rows is closed
+	rows.Close()
+
+	return fn(conn, versions)
+}
+
+// runInTx runs script and then record with its args in one transaction, so a migration and its schema_migrations row
+// are applied or rolled back together.
+func runInTx(ctx context.Context, conn *sql.Conn, script string, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Seed loads the example people and courses from ./seed/<driver>.sql into a migrated database. Rows that already
+// exist are left alone.
+func Seed(ctx context.Context, db *sql.DB, driver string) error {
+	seedSQL, err := fs.ReadFile(seedFiles, path.Join("seed", driver+".sql"))
+	if err != nil {
+		return fmt.Errorf("failed to read seed: %w", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, string(seedSQL)); err != nil {
+		return fmt.Errorf("failed to seed database: %w", err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,209 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := map[string]struct {
+		files        fstest.MapFS
+		output       []Migration
+		expectsError bool
+	}{
+		"sorted by version": {
+			files: fstest.MapFS{
+				"migrations/0002_add_email.up.sql":       {Data: []byte("ALTER TABLE person ADD email TEXT;")},
+				"migrations/0002_add_email.down.sql":     {Data: []byte("ALTER TABLE person DROP email;")},
+				"migrations/0001_create_schema.up.sql":   {Data: []byte("CREATE TABLE person ();")},
+				"migrations/0001_create_schema.down.sql": {Data: []byte("DROP TABLE person;")},
+			},
+			output: []Migration{
+				{Version: 1, Name: "create_schema", Up: "CREATE TABLE person ();", Down: "DROP TABLE person;"},
+				{Version: 2, Name: "add_email", Up: "ALTER TABLE person ADD email TEXT;", Down: "ALTER TABLE person DROP email;"},
+			},
+			expectsError: false,
+		},
+		"missing down script": {
+			files: fstest.MapFS{
+				"migrations/0001_create_schema.up.sql": {Data: []byte("CREATE TABLE person ();")},
+			},
+			expectsError: true,
+		},
+		"version used twice": {
+			files: fstest.MapFS{
+				"migrations/0001_create_schema.up.sql": {Data: []byte("CREATE TABLE person ();")},
+				"migrations/0001_add_email.down.sql":   {Data: []byte("ALTER TABLE person DROP email;")},
+			},
+			expectsError: true,
+		},
+		"badly named file": {
+			files: fstest.MapFS{
+				"migrations/create_schema.sql": {Data: []byte("CREATE TABLE person ();")},
+			},
+			expectsError: true,
+		},
+	}
+	for testName, testConditions := range tests {
+		t.Run(testName, func(t *testing.T) {
+			migrations, err := loadMigrations(testConditions.files, "migrations")
+			if testConditions.expectsError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testConditions.output, migrations)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	postgres, err := NewMigrator(nil, Postgres)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, postgres.migrations)
+	for i, migration := range postgres.migrations {
+		assert.Equal(t, i+1, migration.Version, "migration versions must be consecutive")
+	}
+
+	sqlite, err := NewMigrator(nil, SQLite)
+	assert.NoError(t, err)
+	assert.Equal(t, len(postgres.migrations), len(sqlite.migrations), "every Postgres migration needs a SQLite version")
+	for i, migration := range sqlite.migrations {
+		assert.Equal(t, postgres.migrations[i].Version, migration.Version)
+		assert.Equal(t, postgres.migrations[i].Name, migration.Name)
+	}
+}
+
+func TestMigrateAndSeedSQLite(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDatabase(SQLite, ":memory:", Options{})
+	assert.NoError(t, err)
+	defer db.Close()
+	migrator, err := NewMigrator(db, SQLite)
+	assert.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrator.migrations), applied)
+	applied, err = migrator.Up(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, applied)
+
+	// seeding twice must leave one copy of the data, and new rows must get ids after the seeded ones
+	assert.NoError(t, Seed(ctx, db, SQLite))
+	assert.NoError(t, Seed(ctx, db, SQLite))
+	var people, courses, enrollments int
+	err = db.QueryRow(`SELECT (SELECT COUNT(*) FROM person), (SELECT COUNT(*) FROM course), (SELECT COUNT(*) FROM person_course)`).
+		Scan(&people, &courses, &enrollments)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5, 3, 15}, []int{people, courses, enrollments})
+	var id int
+	assert.NoError(t, db.QueryRow(`INSERT INTO course (name) VALUES ('Compilers') RETURNING id`).Scan(&id))
+	assert.Equal(t, 4, id)
+
+	reverted, err := migrator.Down(ctx, len(migrator.migrations))
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrator.migrations), reverted)
+	version, err := migrator.Version(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, version)
+}
+
+// expectLock expects withLock to lock migrations and find applied as the applied versions.
+func expectLock(mock sqlmock.Sqlmock, applied ...int) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version"})
+	for _, version := range applied {
+		rows.AddRow(version)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version FROM schema_migrations`)).WillReturnRows(rows)
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_schema", Up: "CREATE TABLE person ();", Down: "DROP TABLE person;"},
+	{Version: 2, Name: "add_email", Up: "ALTER TABLE person ADD email TEXT;", Down: "ALTER TABLE person DROP email;"},
+}
+
+func TestMigratorUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	migrator := &Migrator{db: db, driver: Postgres, migrations: testMigrations}
+
+	expectLock(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE person ADD email TEXT;`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+		WithArgs(2, "add_email").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorUpFailureRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	migrator := &Migrator{db: db, driver: Postgres, migrations: testMigrations}
+
+	expectLock(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE person ();`)).WillReturnError(errors.New("syntax error"))
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+	assert.EqualError(t, err, "failed to apply migration 1_create_schema: syntax error")
+	assert.Equal(t, 0, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorDown(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	migrator := &Migrator{db: db, driver: Postgres, migrations: testMigrations}
+
+	expectLock(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE person DROP email;`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM schema_migrations WHERE version = $1`)).
+		WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	reverted, err := migrator.Down(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reverted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	migrator := &Migrator{db: db, driver: Postgres, migrations: testMigrations}
+
+	expectLock(mock, 1, 2)
+	expectUnlock(mock)
+
+	version, err := migrator.Version(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,28 @@
+-- IF NOT EXISTS lets databases created by the old db_seed.sql adopt this migration without losing data.
+
+-- person
+CREATE TABLE IF NOT EXISTS person
+(
+    id         SERIAL PRIMARY KEY,
+    first_name TEXT                                          NOT NULL,
+    last_name  TEXT                                          NOT NULL,
+    type       TEXT CHECK (type IN ('professor', 'student')) NOT NULL,
+    age        INTEGER                                       NOT NULL
+);
+
+-- course
+CREATE TABLE IF NOT EXISTS course
+(
+    id   SERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+-- person_course
+CREATE TABLE IF NOT EXISTS person_course
+(
+    person_id INTEGER NOT NULL,
+    course_id INTEGER NOT NULL,
+    PRIMARY KEY (person_id, course_id),
+    FOREIGN KEY (person_id) REFERENCES person (id),
+    FOREIGN KEY (course_id) REFERENCES course (id)
+);
@@ -0,0 +1,14 @@
+-- api_key holds the API keys of batch jobs and partner systems. Only a salted SHA-256 hash of each key is stored,
+-- found by the key's prefix. scopes is a comma separated list, such as 'person:read,course:read'.
+CREATE TABLE IF NOT EXISTS api_key
+(
+    id           SERIAL PRIMARY KEY,
+    name         TEXT        NOT NULL,
+    prefix       TEXT        NOT NULL UNIQUE,
+    salt         TEXT        NOT NULL,
+    hash         TEXT        NOT NULL,
+    scopes       TEXT        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ
+);
@@ -0,0 +1,29 @@
+-- The SQLite version of ../postgres/0001_create_schema.up.sql. AUTOINCREMENT stops ids of deleted rows from being
+-- reused, like a Postgres SERIAL.
+
+-- person
+CREATE TABLE IF NOT EXISTS person
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    first_name TEXT                                          NOT NULL,
+    last_name  TEXT                                          NOT NULL,
+    type       TEXT CHECK (type IN ('professor', 'student')) NOT NULL,
+    age        INTEGER                                       NOT NULL
+);
+
+-- course
+CREATE TABLE IF NOT EXISTS course
+(
+    id   INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL
+);
+
+-- person_course
+CREATE TABLE IF NOT EXISTS person_course
+(
+    person_id INTEGER NOT NULL,
+    course_id INTEGER NOT NULL,
+    PRIMARY KEY (person_id, course_id),
+    FOREIGN KEY (person_id) REFERENCES person (id),
+    FOREIGN KEY (course_id) REFERENCES course (id)
+);
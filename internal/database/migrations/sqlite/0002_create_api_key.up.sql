@@ -0,0 +1,13 @@
+-- The SQLite version of ../postgres/0002_create_api_key.up.sql.
+CREATE TABLE IF NOT EXISTS api_key
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    name         TEXT      NOT NULL,
+    prefix       TEXT      NOT NULL UNIQUE,
+    salt         TEXT      NOT NULL,
+    hash         TEXT      NOT NULL,
+    scopes       TEXT      NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    revoked_at   TIMESTAMP
+);
@@ -0,0 +1,37 @@
+-- Example data for local development. Rows that already exist are left alone, so seeding twice is harmless.
+
+INSERT INTO person (id, first_name, last_name, type, age)
+VALUES (1, 'Steve', 'Jobs', 'professor', 56),
+       (2, 'Jeff', 'Bezos', 'professor', 60),
+       (3, 'Larry', 'Page', 'student', 51),
+       (4, 'Bill', 'Gates', 'student', 67),
+       (5, 'Elon', 'Musk', 'student', 52)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO course (id, name)
+VALUES (1, 'Programming'),
+       (2, 'Databases'),
+       (3, 'UI Design')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO person_course (person_id, course_id)
+VALUES (1, 1),
+       (1, 2),
+       (1, 3),
+       (2, 1),
+       (2, 2),
+       (2, 3),
+       (3, 1),
+       (3, 2),
+       (3, 3),
+       (4, 1),
+       (4, 2),
+       (4, 3),
+       (5, 1),
+       (5, 2),
+       (5, 3)
+ON CONFLICT DO NOTHING;
+
+-- the ids above were given explicitly, so move the sequences past them
+SELECT setval(pg_get_serial_sequence('person', 'id'), MAX(id)) FROM person;
+SELECT setval(pg_get_serial_sequence('course', 'id'), MAX(id)) FROM course;
@@ -0,0 +1,97 @@
+package handlers
+
+//apikey.go defines the handler logic of all /api/apikey http endpoints, which only admins may call.
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type APIKeyHandler struct {
+	APIKeyService services.APIKeyService
+}
+
+// issuedAPIKey is the response to creating or rotating an API key, the only time its secret Key is shown.
+type issuedAPIKey struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+func (a *APIKeyHandler) GetAllAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := a.APIKeyService.GetAllAPIKeys(r.Context())
+	if err != nil {
+		writeServiceError(w, r, "could not get API keys", err)
+		return
+	}
+	err = json.NewEncoder(w).Encode(keys)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+
+// CreateAPIKey creates an API key with the name and scopes in the request body, and responds with it and its secret.
+func (a *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var key models.APIKey
+	err := json.NewDecoder(r.Body).Decode(&key)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if fieldErrs := validateAPIKey(key); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "validation for API key object failed", fieldErrs)
+		return
+	}
+	created, secret, err := a.APIKeyService.CreateAPIKey(r.Context(), key)
+	if err != nil {
+		writeServiceError(w, r, "failed to create API key", err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(issuedAPIKey{APIKey: created, Key: secret})
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+
+// RotateAPIKey replaces the secret of an API key, and responds with the key and its new secret.
+func (a *APIKeyHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	idInt, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+		return
+	}
+	rotated, secret, err := a.APIKeyService.RotateAPIKey(r.Context(), idInt)
+	if err != nil {
+		writeServiceError(w, r, "could not rotate API key", err)
+		return
+	}
+	err = json.NewEncoder(w).Encode(issuedAPIKey{APIKey: rotated, Key: secret})
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+func (a *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	idInt, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+		return
+	}
+	err = a.APIKeyService.RevokeAPIKey(r.Context(), idInt)
+	if err != nil {
+		writeServiceError(w, r, "could not revoke API key", err)
+		return
+	}
+	err = json.NewEncoder(w).Encode("API key successfully revoked")
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
@@ -0,0 +1,224 @@
+package handlers
+
+//apikey_test.go tests ./apikey.go utilizing table based testing best practices.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testAPIKey = models.APIKey{
+	ID:        4,
+	Name:      "batch",
+	Prefix:    "tc_3f9a0c12d4e5",
+	Scopes:    []string{"person:read", "course:read"},
+	CreatedAt: time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestGetAllAPIKeys(t *testing.T) {
+	testCases := map[string]struct {
+		serviceReturn    []models.APIKey
+		serviceErr       error
+		expectedReturn   []models.APIKey
+		expectedHTTPCode int
+	}{
+		"success": {
+			serviceReturn:    []models.APIKey{testAPIKey},
+			expectedReturn:   []models.APIKey{testAPIKey},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"internal error": {
+			serviceReturn:    []models.APIKey{},
+			serviceErr:       errors.New("an error occured!"),
+			expectedReturn:   []models.APIKey(nil),
+			expectedHTTPCode: http.StatusInternalServerError,
+		},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/api/apikey/", nil)
+			assert.NoError(t, err)
+			mockService := new(services.MockAPIKeyService)
+			mockService.On("GetAllAPIKeys", mock.Anything).Return(testVars.serviceReturn, testVars.serviceErr)
+			handler := &APIKeyHandler{APIKeyService: mockService}
+
+			rr := httptest.NewRecorder()
+			handler.GetAllAPIKeys(rr, req)
+
+			var responseBody []models.APIKey
+			json.NewDecoder(rr.Body).Decode(&responseBody)
+			assert.Equal(t, testVars.expectedReturn, responseBody)
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	testCases := map[string]struct {
+		requestBody      string
+		serviceCalled    bool
+		serviceErr       error
+		expectedReturn   issuedAPIKey
+		expectedHTTPCode int
+		expectedFields   []string
+	}{
+		"success": {
+			requestBody:      `{"name": "batch", "scopes": ["person:read", "course:read"]}`,
+			serviceCalled:    true,
+			expectedReturn:   issuedAPIKey{APIKey: testAPIKey, Key: "tc_3f9a0c12d4e5_secret"},
+			expectedHTTPCode: http.StatusCreated,
+		},
+		"missing name and scopes": {
+			requestBody:      `{}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedFields:   []string{"name", "scopes"},
+		},
+		"no scopes": {
+			requestBody:      `{"name": "batch", "scopes": []}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedFields:   []string{"scopes"},
+		},
+		"repeated scope": {
+			requestBody:      `{"name": "batch", "scopes": ["course:read", "course:read"]}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedFields:   []string{"scopes"},
+		},
+		"unknown scope": {
+			requestBody:      `{"name": "batch", "scopes": ["course:read", "apikey:write"]}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedFields:   []string{"scopes[1]"},
+		},
+		"malformed body": {
+			requestBody:      `{"name": `,
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"internal error": {
+			requestBody:      `{"name": "batch", "scopes": ["person:read", "course:read"]}`,
+			serviceCalled:    true,
+			serviceErr:       errors.New("Couldn't make API key!"),
+			expectedHTTPCode: http.StatusInternalServerError,
+		},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "/api/apikey/", bytes.NewBufferString(testVars.requestBody))
+			assert.NoError(t, err)
+			mockService := new(services.MockAPIKeyService)
+			if testVars.serviceCalled {
+				requested := models.APIKey{Name: "batch", Scopes: []string{"person:read", "course:read"}}
+				mockService.On("CreateAPIKey", mock.Anything, requested).Return(testVars.expectedReturn.APIKey, testVars.expectedReturn.Key, testVars.serviceErr)
+			}
+			handler := &APIKeyHandler{APIKeyService: mockService}
+
+			rr := httptest.NewRecorder()
+			handler.CreateAPIKey(rr, req)
+
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			if testVars.expectedHTTPCode == http.StatusCreated {
+				var responseBody issuedAPIKey
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&responseBody))
+				assert.Equal(t, testVars.expectedReturn, responseBody)
+			}
+			if testVars.expectedFields != nil {
+				var problem Problem
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&problem))
+				fields := make([]string, 0, len(problem.Errors))
+				for _, fieldErr := range problem.Errors {
+					fields = append(fields, fieldErr.Field)
+				}
+				assert.ElementsMatch(t, testVars.expectedFields, fields)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	testCases := map[string]struct {
+		id               string
+		serviceErr       error
+		expectedHTTPCode int
+	}{
+		"success":        {id: "4", expectedHTTPCode: http.StatusOK},
+		"can't parse":    {id: "four", expectedHTTPCode: http.StatusBadRequest},
+		"not found":      {id: "4", serviceErr: services.ErrAPIKeyNotFound, expectedHTTPCode: http.StatusNotFound},
+		"internal error": {id: "4", serviceErr: errors.New("an error occured!"), expectedHTTPCode: http.StatusInternalServerError},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "/api/apikey/"+testVars.id+"/rotate", nil)
+			assert.NoError(t, err)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testVars.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			mockService := new(services.MockAPIKeyService)
+			if testVars.id == "4" {
+				mockService.On("RotateAPIKey", mock.Anything, 4).Return(testAPIKey, "tc_3f9a0c12d4e5_secret", testVars.serviceErr)
+			}
+			handler := &APIKeyHandler{APIKeyService: mockService}
+
+			rr := httptest.NewRecorder()
+			handler.RotateAPIKey(rr, req)
+
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			if testVars.expectedHTTPCode == http.StatusOK {
+				var responseBody issuedAPIKey
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&responseBody))
+				assert.Equal(t, issuedAPIKey{APIKey: testAPIKey, Key: "tc_3f9a0c12d4e5_secret"}, responseBody)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	testCases := map[string]struct {
+		id               string
+		serviceErr       error
+		expectedReturn   string
+		expectedHTTPCode int
+	}{
+		"success":        {id: "4", expectedReturn: "API key successfully revoked", expectedHTTPCode: http.StatusOK},
+		"can't parse":    {id: "four", expectedHTTPCode: http.StatusBadRequest},
+		"not found":      {id: "4", serviceErr: services.ErrAPIKeyNotFound, expectedHTTPCode: http.StatusNotFound},
+		"internal error": {id: "4", serviceErr: errors.New("an error occured!"), expectedHTTPCode: http.StatusInternalServerError},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodDelete, "/api/apikey/"+testVars.id, nil)
+			assert.NoError(t, err)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testVars.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			mockService := new(services.MockAPIKeyService)
+			if testVars.id == "4" {
+				mockService.On("RevokeAPIKey", mock.Anything, 4).Return(testVars.serviceErr)
+			}
+			handler := &APIKeyHandler{APIKeyService: mockService}
+
+			rr := httptest.NewRecorder()
+			handler.RevokeAPIKey(rr, req)
+
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			if testVars.expectedReturn != "" {
+				var responseBody string
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&responseBody))
+				assert.Equal(t, testVars.expectedReturn, responseBody)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
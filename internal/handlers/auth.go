@@ -0,0 +1,118 @@
+package handlers
+
+//auth.go defines the middleware that authenticates every request with a bearer token, see ../auth, or an API key, see
+//../services/apikey.go.
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/logging"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+)
+
+// TokenVerifier checks a bearer token and returns the caller it was issued to. *auth.Verifier implements it.
+type TokenVerifier interface {
+	Verify(token string) (auth.Identity, error)
+}
+
+// APIKeyAuthenticator returns the stored API key matching a secret API key. services.APIKeyService implements it.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (models.APIKey, error)
+}
+
+// Authenticate returns middleware that requires an "Authorization: Bearer <token>" header accepted by verifier, or an
+// "Authorization: ApiKey <key>" header accepted by apiKeys, stores the caller's auth.Identity in the request's context
+// and adds its subject to the request's logger. Bearer tokens are not accepted if verifier is nil, and API keys are
+// not accepted if apiKeys is nil. Requests without valid credentials fail with a 401. Requests for publicPaths skip
+// authentication. A public path matches exactly, or ends in "*" to match every path it prefixes, such as "/docs/*".
+func Authenticate(verifier TokenVerifier, apiKeys APIKeyAuthenticator, publicPaths []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if matchesPath(r.URL.Path, publicPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			scheme, credentials, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			credentials = strings.TrimSpace(credentials)
+			var identity auth.Identity
+			var ok bool
+			switch {
+			case credentials != "" && strings.EqualFold(scheme, "Bearer") && verifier != nil:
+				identity, ok = verifyBearerToken(w, r, verifier, credentials)
+			case credentials != "" && strings.EqualFold(scheme, "ApiKey") && apiKeys != nil:
+				identity, ok = verifyAPIKey(w, r, apiKeys, credentials)
+			default:
+				missing := make([]string, 0, 2)
+				if verifier != nil {
+					missing = append(missing, "bearer token")
+					w.Header().Add("WWW-Authenticate", `Bearer realm="api"`)
+				}
+				if apiKeys != nil {
+					missing = append(missing, "API key")
+					w.Header().Add("WWW-Authenticate", `ApiKey realm="api"`)
+				}
+				writeProblem(w, r, http.StatusUnauthorized, "missing "+strings.Join(missing, " or "))
+			}
+			if ok {
+				ctx := auth.WithIdentity(r.Context(), identity)
+				ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With(slog.String("subject", identity.Subject)))
+				next.ServeHTTP(w, r.WithContext(ctx))
+			}
+		})
+	}
+}
+
+// verifyBearerToken returns the caller token was issued to, or responds with a 401 and returns false.
+func verifyBearerToken(w http.ResponseWriter, r *http.Request, verifier TokenVerifier, token string) (auth.Identity, bool) {
+	identity, err := verifier.Verify(token)
+	if err != nil {
+		detail := "invalid bearer token"
+		if errors.Is(err, auth.ErrExpiredToken) {
+			detail = "bearer token has expired"
+		}
+		logError(r, err.Error(), http.StatusUnauthorized)
+		w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+		encodeProblem(w, http.StatusUnauthorized, newProblem(r, http.StatusUnauthorized, detail))
+		return auth.Identity{}, false
+	}
+	return identity, true
+}
+
+// verifyAPIKey returns the caller using key, limited to the key's scopes, or responds with an error and returns false.
+func verifyAPIKey(w http.ResponseWriter, r *http.Request, apiKeys APIKeyAuthenticator, key string) (auth.Identity, bool) {
+	stored, err := apiKeys.AuthenticateAPIKey(r.Context(), key)
+	if errors.Is(err, services.ErrInvalidAPIKey) {
+		w.Header().Set("WWW-Authenticate", `ApiKey realm="api", error="invalid_key"`)
+		writeProblem(w, r, http.StatusUnauthorized, "invalid API key")
+		return auth.Identity{}, false
+	}
+	if err != nil {
+		writeServiceError(w, r, "could not check API key", err)
+		return auth.Identity{}, false
+	}
+	scopes := stored.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return auth.Identity{Subject: "apikey:" + stored.Prefix, Scopes: scopes}, true
+}
+
+// matchesPath returns whether path matches one of patterns. A pattern matches exactly, or ends in "*" to match every
+// path it prefixes.
+func matchesPath(path string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package handlers
+
+//auth_test.go tests ./auth.go utilizing table based testing best practices.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// stubVerifier accepts the token "good" for alice, and fails any other token with err.
+type stubVerifier struct{ err error }
+
+func (s stubVerifier) Verify(token string) (auth.Identity, error) {
+	if token == "good" {
+		return auth.Identity{Subject: "alice", Roles: []string{"admin"}}, nil
+	}
+	return auth.Identity{}, s.err
+}
+
+func TestAuthenticate(t *testing.T) {
+	testCases := map[string]struct {
+		path               string
+		authorization      string
+		noVerifier         bool
+		noAPIKeys          bool
+		verifyErr          error
+		apiKeyReturn       models.APIKey
+		apiKeyErr          error
+		expectedHTTPCode   int
+		expectedIdentity   *auth.Identity
+		expectedDetail     string
+		expectedChallenges []string
+	}{
+		"valid token": {
+			path:             "/api/course",
+			authorization:    "Bearer good",
+			expectedHTTPCode: http.StatusOK,
+			expectedIdentity: &auth.Identity{Subject: "alice", Roles: []string{"admin"}},
+		},
+		"lowercase scheme": {
+			path:             "/api/course",
+			authorization:    "bearer good",
+			expectedHTTPCode: http.StatusOK,
+			expectedIdentity: &auth.Identity{Subject: "alice", Roles: []string{"admin"}},
+		},
+		"missing header": {
+			path:               "/api/course",
+			expectedHTTPCode:   http.StatusUnauthorized,
+			expectedDetail:     "missing bearer token or API key",
+			expectedChallenges: []string{`Bearer realm="api"`, `ApiKey realm="api"`},
+		},
+		"missing header without api keys": {
+			path:               "/api/course",
+			noAPIKeys:          true,
+			expectedHTTPCode:   http.StatusUnauthorized,
+			expectedDetail:     "missing bearer token",
+			expectedChallenges: []string{`Bearer realm="api"`},
+		},
+		"basic scheme": {
+			path:               "/api/course",
+			authorization:      "Basic YWxpY2U6c2VjcmV0",
+			expectedHTTPCode:   http.StatusUnauthorized,
+			expectedDetail:     "missing bearer token or API key",
+			expectedChallenges: []string{`Bearer realm="api"`, `ApiKey realm="api"`},
+		},
+		"invalid token": {
+			path:               "/api/course",
+			authorization:      "Bearer bad",
+			verifyErr:          fmt.Errorf("%w: signature does not match", auth.ErrInvalidToken),
+			expectedHTTPCode:   http.StatusUnauthorized,
+			expectedDetail:     "invalid bearer token",
+			expectedChallenges: []string{`Bearer realm="api", error="invalid_token"`},
+		},
+		"expired token": {
+			path:               "/api/course",
+			authorization:      "Bearer bad",
+			verifyErr:          fmt.Errorf("%w: expired at 2024-10-01T12:00:00Z", auth.ErrExpiredToken),
+			expectedHTTPCode:   http.StatusUnauthorized,
+			expectedDetail:     "bearer token has expired",
+			expectedChallenges: []string{`Bearer realm="api", error="invalid_token"`},
+		},
+		"valid api key": {
+			path:             "/api/course",
+			authorization:    "ApiKey tc_3f9a0c12d4e5_secret",
+			apiKeyReturn:     models.APIKey{ID: 4, Prefix: "tc_3f9a0c12d4e5", Scopes: []string{"course:read"}},
+			expectedHTTPCode: http.StatusOK,
+			expectedIdentity: &auth.Identity{Subject: "apikey:tc_3f9a0c12d4e5", Scopes: []string{"course:read"}},
+		},
+		"invalid api key": {
+			path:               "/api/course",
+			authorization:      "ApiKey tc_3f9a0c12d4e5_secret",
+			apiKeyErr:          services.ErrInvalidAPIKey,
+			expectedHTTPCode:   http.StatusUnauthorized,
+			expectedDetail:     "invalid API key",
+			expectedChallenges: []string{`ApiKey realm="api", error="invalid_key"`},
+		},
+		"api key lookup failure": {
+			path:             "/api/course",
+			authorization:    "ApiKey tc_3f9a0c12d4e5_secret",
+			apiKeyErr:        errors.New("database is down"),
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedDetail:   "could not check API key",
+		},
+		"api key without api keys": {
+			path:               "/api/course",
+			authorization:      "ApiKey tc_3f9a0c12d4e5_secret",
+			noAPIKeys:          true,
+			expectedHTTPCode:   http.StatusUnauthorized,
+			expectedDetail:     "missing bearer token",
+			expectedChallenges: []string{`Bearer realm="api"`},
+		},
+		"api key without verifier": {
+			path:             "/api/course",
+			authorization:    "ApiKey tc_3f9a0c12d4e5_secret",
+			noVerifier:       true,
+			apiKeyReturn:     models.APIKey{ID: 4, Prefix: "tc_3f9a0c12d4e5", Scopes: []string{"course:read"}},
+			expectedHTTPCode: http.StatusOK,
+			expectedIdentity: &auth.Identity{Subject: "apikey:tc_3f9a0c12d4e5", Scopes: []string{"course:read"}},
+		},
+		"token without verifier": {
+			path:               "/api/course",
+			authorization:      "Bearer good",
+			noVerifier:         true,
+			expectedHTTPCode:   http.StatusUnauthorized,
+			expectedDetail:     "missing API key",
+			expectedChallenges: []string{`ApiKey realm="api"`},
+		},
+		"public path": {
+			path:             "/health",
+			expectedHTTPCode: http.StatusOK,
+		},
+		"public prefix": {
+			path:             "/docs/openapi.json",
+			expectedHTTPCode: http.StatusOK,
+		},
+		"public path is not a prefix": {
+			path:               "/health/details",
+			expectedHTTPCode:   http.StatusUnauthorized,
+			expectedDetail:     "missing bearer token or API key",
+			expectedChallenges: []string{`Bearer realm="api"`, `ApiKey realm="api"`},
+		},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, testVars.path, nil)
+			if testVars.authorization != "" {
+				req.Header.Set("Authorization", testVars.authorization)
+			}
+			var apiKeys APIKeyAuthenticator
+			if !testVars.noAPIKeys {
+				mockService := new(services.MockAPIKeyService)
+				mockService.On("AuthenticateAPIKey", mock.Anything, "tc_3f9a0c12d4e5_secret").Return(testVars.apiKeyReturn, testVars.apiKeyErr)
+				apiKeys = mockService
+			}
+			var verifier TokenVerifier
+			if !testVars.noVerifier {
+				verifier = stubVerifier{err: testVars.verifyErr}
+			}
+			var identity auth.Identity
+			var hasIdentity bool
+			handler := Authenticate(verifier, apiKeys, []string{"/health", "/docs/*"})(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					identity, hasIdentity = auth.FromContext(r.Context())
+				}))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			assert.Equal(t, testVars.expectedChallenges, rr.Header().Values("WWW-Authenticate"))
+			if testVars.expectedIdentity != nil {
+				assert.True(t, hasIdentity)
+				assert.Equal(t, *testVars.expectedIdentity, identity)
+			} else {
+				assert.False(t, hasIdentity)
+			}
+			if testVars.expectedDetail != "" {
+				var problem Problem
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&problem))
+				assert.Equal(t, testVars.expectedDetail, problem.Detail)
+				assert.Equal(t, problemContentType, rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
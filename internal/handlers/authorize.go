@@ -0,0 +1,73 @@
+package handlers
+
+//authorize.go defines the middleware that asks ../auth.Policy whether the caller of a route may perform its action.
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"tech-challenge/internal/auth"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Authorizer decides whether a caller may perform an action on a resource. *auth.Policy implements it.
+type Authorizer interface {
+	Authorize(ctx context.Context, identity auth.Identity, action auth.Action, resource auth.Resource) error
+}
+
+// Authorize returns middleware for a single route that lets a request through only if authorizer allows its caller,
+// stored in the context by Authenticate, to perform action. The resource is read from the route's URL parameters:
+// {id} is a course id for course actions and a person id otherwise, {name} a person name and {courseId} a course id.
+// auth.ManageAPIKeys has no resource. Forbidden requests fail with a 403. {name} only says who the caller asked for, so
+// name routes must check the person it resolves to again, see PersonHandler.Authorizer.
+func Authorize(authorizer Authorizer, action auth.Action) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if authorizeRequest(w, r, authorizer, action, routeResource(r, action)) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// authorizeRequest returns whether authorizer allows the caller of r to perform action on resource, or responds with
+// an error and returns false.
+func authorizeRequest(w http.ResponseWriter, r *http.Request, authorizer Authorizer, action auth.Action, resource auth.Resource) bool {
+	identity, ok := auth.FromContext(r.Context())
+	if !ok {
+		writeProblem(w, r, http.StatusUnauthorized, "not authenticated")
+		return false
+	}
+	err := authorizer.Authorize(r.Context(), identity, action, resource)
+	if errors.Is(err, auth.ErrForbidden) {
+		// the reason could reveal who is enrolled where, so only the action is shown
+		logError(r, err.Error(), http.StatusForbidden)
+		encodeProblem(w, http.StatusForbidden, newProblem(r, http.StatusForbidden, "not allowed to "+string(action)))
+		return false
+	}
+	if err != nil {
+		writeServiceError(w, r, "could not authorize request", err)
+		return false
+	}
+	return true
+}
+
+// routeResource returns the auth.Resource named by the URL parameters of r, as described by Authorize. Parameters
+// that are not integers leave their id 0, which matches no resource; the handler rejects them afterwards.
+func routeResource(r *http.Request, action auth.Action) auth.Resource {
+	var resource auth.Resource
+	if action == auth.ManageAPIKeys {
+		return resource
+	}
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	if action.OnCourse() {
+		resource.CourseID = id
+	} else {
+		resource.PersonID = id
+		resource.PersonName = chi.URLParam(r, "name")
+		resource.CourseID, _ = strconv.Atoi(chi.URLParam(r, "courseId"))
+	}
+	return resource
+}
@@ -0,0 +1,125 @@
+package handlers
+
+//authorize_test.go tests ./authorize.go utilizing table based testing best practices.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"tech-challenge/internal/auth"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAuthorizer records what it was asked to authorize and answers with err.
+type stubAuthorizer struct {
+	err      error
+	action   auth.Action
+	resource auth.Resource
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, identity auth.Identity, action auth.Action, resource auth.Resource) error {
+	s.action, s.resource = action, resource
+	return s.err
+}
+
+func TestAuthorize(t *testing.T) {
+	testCases := map[string]struct {
+		pattern          string
+		path             string
+		action           auth.Action
+		anonymous        bool
+		authorizeErr     error
+		expectedResource auth.Resource
+		expectedHTTPCode int
+		expectedDetail   string
+	}{
+		"course id": {
+			pattern:          "/api/course/{id}",
+			path:             "/api/course/3",
+			action:           auth.UpdateCourse,
+			expectedResource: auth.Resource{CourseID: 3},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"person name": {
+			pattern:          "/api/person/{name}",
+			path:             "/api/person/Jane%20Doe",
+			action:           auth.ReadPerson,
+			expectedResource: auth.Resource{PersonName: "Jane Doe"},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"person and course ids": {
+			pattern:          "/api/person/{id}/courses/{courseId}",
+			path:             "/api/person/2/courses/5",
+			action:           auth.Enroll,
+			expectedResource: auth.Resource{PersonID: 2, CourseID: 5},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"unparsable id": {
+			pattern:          "/api/person/id/{id}",
+			path:             "/api/person/id/two",
+			action:           auth.ReadPerson,
+			expectedResource: auth.Resource{},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"forbidden": {
+			pattern:          "/api/course/{id}",
+			path:             "/api/course/3",
+			action:           auth.DeleteCourse,
+			authorizeErr:     fmt.Errorf("%w: delete course needs the admin role", auth.ErrForbidden),
+			expectedResource: auth.Resource{CourseID: 3},
+			expectedHTTPCode: http.StatusForbidden,
+			expectedDetail:   "not allowed to delete course",
+		},
+		"lookup failure": {
+			pattern:          "/api/course/{id}/people",
+			path:             "/api/course/3/people",
+			action:           auth.ReadRoster,
+			authorizeErr:     errors.New("failed to look up the person of ada: database is down"),
+			expectedResource: auth.Resource{CourseID: 3},
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedDetail:   "could not authorize request",
+		},
+		"not authenticated": {
+			pattern:          "/api/course/",
+			path:             "/api/course/",
+			action:           auth.ListCourses,
+			anonymous:        true,
+			expectedHTTPCode: http.StatusUnauthorized,
+			expectedDetail:   "not authenticated",
+		},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			authorizer := &stubAuthorizer{err: testVars.authorizeErr}
+			called := false
+			r := chi.NewRouter()
+			r.With(Authorize(authorizer, testVars.action)).Get(testVars.pattern, func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+			req := httptest.NewRequest(http.MethodGet, testVars.path, nil)
+			if !testVars.anonymous {
+				req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{Subject: "ada", Roles: []string{auth.RoleProfessor}}))
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			assert.Equal(t, testVars.expectedHTTPCode == http.StatusOK, called)
+			if !testVars.anonymous {
+				assert.Equal(t, testVars.action, authorizer.action)
+				assert.Equal(t, testVars.expectedResource, authorizer.resource)
+			}
+			if testVars.expectedDetail != "" {
+				var problem Problem
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&problem))
+				assert.Equal(t, testVars.expectedDetail, problem.Detail)
+			}
+		})
+	}
+}
@@ -5,13 +5,11 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
-	"reflect"
 	"strconv"
 	"tech-challenge/internal/models"
 	"tech-challenge/internal/services"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 )
 
 type CourseHandler struct {
@@ -19,16 +17,25 @@ type CourseHandler struct {
 }
 
 func (c *CourseHandler) GetAllCourses(w http.ResponseWriter, r *http.Request) {
-	courses, err := c.CourseService.GetAllCourses()
+	opts, err := parseListOptions(r.URL.Query())
 	if err != nil {
-		logError(r, "internal error: "+err.Error(), http.StatusInternalServerError)
-		http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
 		return
 	}
+	var fieldErrs []FieldError
+	if opts.Sort, fieldErrs = parseSort(r.URL.Query().Get("sort"), services.CourseSortFields); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "cannot sort courses as requested", fieldErrs)
+		return
+	}
+	courses, pageInfo, err := c.CourseService.GetAllCourses(r.Context(), opts)
+	if err != nil {
+		writeServiceError(w, r, "could not get courses", err)
+		return
+	}
+	setPageHeaders(w, r, opts, pageInfo)
 	err = json.NewEncoder(w).Encode(courses)
 	if err != nil {
-		logError(r, "internal error", http.StatusInternalServerError)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
 		return
 	}
 }
@@ -36,25 +43,17 @@ func (c *CourseHandler) GetCourse(w http.ResponseWriter, r *http.Request) {
 	idString := chi.URLParam(r, "id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		logError(r, "bad request: cannot parse id to int", http.StatusBadRequest)
-		http.Error(w, "bad request: cannot parse id to int", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
 		return
 	}
-	course, err := c.CourseService.GetCourse(idInt)
+	course, err := c.CourseService.GetCourse(r.Context(), idInt)
 	if err != nil {
-		logError(r, "internal error: "+err.Error(), http.StatusInternalServerError)
-		http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if reflect.DeepEqual(course, models.Course{}) {
-		logError(r, "course not found", http.StatusNotFound)
-		http.Error(w, "course not found", http.StatusNotFound)
+		writeServiceError(w, r, "could not get course", err)
 		return
 	}
 	err = json.NewEncoder(w).Encode(course)
 	if err != nil {
-		logError(r, "internal error", http.StatusInternalServerError)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
 		return
 	}
 }
@@ -62,39 +61,27 @@ func (c *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
 	idString := chi.URLParam(r, "id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		logError(r, "bad request: cannot parse id to int", http.StatusBadRequest)
-		http.Error(w, "bad request: cannot parse id to int", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
 		return
 	}
 	var course models.Course
 	err = json.NewDecoder(r.Body).Decode(&course)
 	if err != nil {
-		logError(r, err.Error(), http.StatusBadRequest)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
-	validate := validator.New(validator.WithRequiredStructEnabled())
-	err = validate.Struct(course)
-	if err != nil {
-		logError(r, "validation for course object failed", http.StatusBadRequest)
-		http.Error(w, "validation for course object failed", http.StatusBadRequest)
-		return
-	}
-	updatedCourse, err := c.CourseService.UpdateCourse(idInt, course)
-	if err != nil && err.Error() == "course not found" {
-		logError(r, err.Error(), http.StatusNotFound)
-		http.Error(w, err.Error(), http.StatusNotFound)
+	if fieldErrs := validateCourse(course); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "validation for course object failed", fieldErrs)
 		return
 	}
+	updatedCourse, err := c.CourseService.UpdateCourse(r.Context(), idInt, course)
 	if err != nil {
-		logError(r, "error updating course: "+err.Error(), http.StatusInternalServerError)
-		http.Error(w, "error updating course: "+err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, r, "error updating course", err)
 		return
 	}
 	err = json.NewEncoder(w).Encode(updatedCourse)
 	if err != nil {
-		logError(r, "internal error", http.StatusInternalServerError)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
 		return
 	}
 }
@@ -102,27 +89,21 @@ func (c *CourseHandler) CreateCourse(w http.ResponseWriter, r *http.Request) {
 	var course models.Course
 	err := json.NewDecoder(r.Body).Decode(&course)
 	if err != nil {
-		logError(r, err.Error(), http.StatusBadRequest)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
-	validate := validator.New(validator.WithRequiredStructEnabled())
-	err = validate.Struct(course)
-	if err != nil {
-		logError(r, "validation for course object failed: "+err.Error(), http.StatusBadRequest)
-		http.Error(w, "validation for course object failed: "+err.Error(), http.StatusBadRequest)
+	if fieldErrs := validateCourse(course); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "validation for course object failed", fieldErrs)
 		return
 	}
-	insertedID, err := c.CourseService.CreateCourse(course)
+	insertedID, err := c.CourseService.CreateCourse(r.Context(), course)
 	if err != nil {
-		logError(r, "failed to create course: "+err.Error(), http.StatusInternalServerError)
-		http.Error(w, "failed to create course: "+err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, r, "failed to create course", err)
 		return
 	}
 	err = json.NewEncoder(w).Encode(insertedID)
 	if err != nil {
-		logError(r, "internal error", http.StatusInternalServerError)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
 		return
 	}
 }
@@ -130,25 +111,92 @@ func (c *CourseHandler) DeleteCourse(w http.ResponseWriter, r *http.Request) {
 	idString := chi.URLParam(r, "id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		logError(r, "bad request: cannot parse id to int", http.StatusBadRequest)
-		http.Error(w, "bad request: cannot parse id to int", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
 		return
 	}
-	deletedCourseCount, err := c.CourseService.DeleteCourse(idInt)
+	deletedCourseCount, err := c.CourseService.DeleteCourse(r.Context(), idInt)
 	if err != nil {
-		logError(r, "could not delete course: "+err.Error(), http.StatusInternalServerError)
-		http.Error(w, "could not delete course: "+err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, r, "could not delete course", err)
 		return
 	}
 	if deletedCourseCount == 0 {
-		logError(r, "course not found", http.StatusNotFound)
-		http.Error(w, "course not found", http.StatusNotFound)
+		writeProblem(w, r, http.StatusNotFound, "course not found")
 		return
 	}
 	err = json.NewEncoder(w).Encode("course successfully deleted")
 	if err != nil {
-		logError(r, "internal error", http.StatusInternalServerError)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+func (c *CourseHandler) PatchCourse(w http.ResponseWriter, r *http.Request) {
+	idString := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+		return
+	}
+	current, err := c.CourseService.GetCourse(r.Context(), idInt)
+	if err != nil {
+		writeServiceError(w, r, "could not get course", err)
+		return
+	}
+	course, ok := decodeMergePatch(w, r, current)
+	if !ok {
+		return
+	}
+	course.ID = current.ID
+	if fieldErrs := validateCourse(course); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "validation for course object failed", fieldErrs)
+		return
+	}
+
+	if course.Name != current.Name {
+		course, err = c.CourseService.PatchCourse(r.Context(), idInt, services.CoursePatch{Name: &course.Name})
+		if err != nil {
+			writeServiceError(w, r, "error updating course", err)
+			return
+		}
+	}
+	err = json.NewEncoder(w).Encode(course)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+func (c *CourseHandler) GetCourseRoster(w http.ResponseWriter, r *http.Request) {
+	idString := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+		return
+	}
+	params := r.URL.Query()
+	personType := params.Get("type")
+	if personType != "" && personType != "professor" && personType != "student" {
+		writeProblem(w, r, http.StatusBadRequest, `bad request: type must be either "professor" or "student"`)
+		return
+	}
+	opts, err := parseListOptions(params)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	var fieldErrs []FieldError
+	if opts.Sort, fieldErrs = parseSort(params.Get("sort"), services.PersonSortFields); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "cannot sort roster as requested", fieldErrs)
+		return
+	}
+
+	roster, pageInfo, err := c.CourseService.GetCourseRoster(r.Context(), idInt, personType, opts)
+	if err != nil {
+		writeServiceError(w, r, "could not get course roster", err)
+		return
+	}
+	setPageHeaders(w, r, opts, pageInfo)
+	err = json.NewEncoder(w).Encode(roster)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
 		return
 	}
 }
@@ -16,6 +16,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetAllCourses(t *testing.T) {
@@ -64,7 +65,7 @@ func TestGetAllCourses(t *testing.T) {
 			req, err := http.NewRequest("GET", "/api/course/", nil)
 			assert.NoError(t, err)
 
-			mockService.On("GetAllCourses").Return(testVars.serviceReturn, testVars.serviceErr)
+			mockService.On("GetAllCourses", mock.Anything, services.ListOptions{Limit: defaultPageLimit}).Return(testVars.serviceReturn, services.PageInfo{Total: -1}, testVars.serviceErr)
 			handler.GetAllCourses(rr, req)
 			var responseCourses []models.Course
 
@@ -113,7 +114,7 @@ func TestGetCourse(t *testing.T) {
 		"course not found": {
 			id:               "555",
 			serviceReturn:    models.Course{},
-			serviceErr:       nil,
+			serviceErr:       services.ErrCourseNotFound,
 			expectedReturn:   models.Course{},
 			expectedHTTPCode: http.StatusNotFound,
 		},
@@ -136,7 +137,7 @@ func TestGetCourse(t *testing.T) {
 
 			intId, _ := strconv.Atoi(testVars.id)
 			if test != "can't parse" {
-				mockService.On("GetCourse", intId).Return(testVars.serviceReturn, testVars.serviceErr)
+				mockService.On("GetCourse", mock.Anything, intId).Return(testVars.serviceReturn, testVars.serviceErr)
 			}
 			handler.GetCourse(rr, req)
 
@@ -190,6 +191,14 @@ func TestUpdateCourse(t *testing.T) {
 			expectedReturn:   models.Course{},
 			expectedHTTPCode: http.StatusInternalServerError,
 		},
+		"course not found": {
+			id:               "1",
+			requestBody:      models.Course{ID: 2, Name: "UpdatedCourse"},
+			serviceReturn:    models.Course{},
+			serviceErr:       services.ErrCourseNotFound,
+			expectedReturn:   models.Course{},
+			expectedHTTPCode: http.StatusNotFound,
+		},
 	}
 
 	for test, testVars := range testCases {
@@ -212,7 +221,7 @@ func TestUpdateCourse(t *testing.T) {
 
 			intId, _ := strconv.Atoi(testVars.id)
 			if test != "can't parse" && test != "bad validation" {
-				mockService.On("UpdateCourse", intId, testVars.requestBody).Return(testVars.serviceReturn, testVars.serviceErr)
+				mockService.On("UpdateCourse", mock.Anything, intId, testVars.requestBody).Return(testVars.serviceReturn, testVars.serviceErr)
 			}
 			handler.UpdateCourse(rr, req)
 
@@ -270,7 +279,7 @@ func TestCreateCourse(t *testing.T) {
 			rr := httptest.NewRecorder()
 
 			if test != "bad validation" {
-				mockService.On("CreateCourse", testVars.requestBody).Return(testVars.serviceReturn, testVars.serviceErr)
+				mockService.On("CreateCourse", mock.Anything, testVars.requestBody).Return(testVars.serviceReturn, testVars.serviceErr)
 			}
 			handler.CreateCourse(rr, req)
 
@@ -338,7 +347,7 @@ func TestDeleteCourse(t *testing.T) {
 
 			intId, _ := strconv.Atoi(testVars.id)
 			if test != "can't parse" {
-				mockService.On("DeleteCourse", intId).Return(testVars.serviceReturn, testVars.serviceErr)
+				mockService.On("DeleteCourse", mock.Anything, intId).Return(testVars.serviceReturn, testVars.serviceErr)
 			}
 			handler.DeleteCourse(rr, req)
 
@@ -351,3 +360,91 @@ func TestDeleteCourse(t *testing.T) {
 		})
 	}
 }
+func TestGetCourseRoster(t *testing.T) {
+	roster := models.Roster{
+		Course:     models.Course{ID: 2, Name: "Databases"},
+		Professors: []models.Person{{ID: 4, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 48, Courses: []int{2}}},
+		Students:   []models.Person{{ID: 1, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{2}}},
+	}
+	testCases := map[string]struct {
+		id               string
+		query            string
+		expectedType     string
+		expectedOpts     services.ListOptions
+		serviceReturn    models.Roster
+		pageInfo         services.PageInfo
+		serviceErr       error
+		expectedLink     string
+		expectedHTTPCode int
+	}{
+		"success": {
+			id:               "2",
+			expectedOpts:     services.ListOptions{Limit: defaultPageLimit},
+			serviceReturn:    roster,
+			pageInfo:         services.PageInfo{Total: -1},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"success students paginated": {
+			id:               "2",
+			query:            "type=student&limit=1&sort=-age",
+			expectedType:     "student",
+			expectedOpts:     services.ListOptions{Limit: 1, Sort: []services.SortField{{Field: "age", Desc: true}}},
+			serviceReturn:    models.Roster{Course: roster.Course, Professors: []models.Person{}, Students: roster.Students},
+			pageInfo:         services.PageInfo{NextCursor: "abc", Total: -1},
+			expectedLink:     `</api/course/2/people?cursor=abc&limit=1&sort=-age&type=student>; rel="next"`,
+			expectedHTTPCode: http.StatusOK,
+		},
+		"can't parse": {
+			id:               "two",
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"bad type": {
+			id:               "2",
+			query:            "type=dean",
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"course not found": {
+			id:               "2",
+			expectedOpts:     services.ListOptions{Limit: defaultPageLimit},
+			serviceErr:       services.ErrCourseNotFound,
+			expectedHTTPCode: http.StatusNotFound,
+		},
+		"internal error": {
+			id:               "2",
+			expectedOpts:     services.ListOptions{Limit: defaultPageLimit},
+			serviceErr:       errors.New("can't get roster!"),
+			expectedHTTPCode: http.StatusInternalServerError,
+		},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/api/course/"+testVars.id+"/people?"+testVars.query, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testVars.id)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			mockService := new(services.MockCourseService)
+			handler := &CourseHandler{CourseService: mockService}
+			rr := httptest.NewRecorder()
+
+			if testVars.expectedHTTPCode != http.StatusBadRequest {
+				mockService.On("GetCourseRoster", mock.Anything, 2, testVars.expectedType, testVars.expectedOpts).Return(testVars.serviceReturn, testVars.pageInfo, testVars.serviceErr)
+			}
+			handler.GetCourseRoster(rr, req)
+
+			if testVars.expectedHTTPCode == http.StatusOK {
+				var responseRoster models.Roster
+				err = json.NewDecoder(rr.Body).Decode(&responseRoster)
+				assert.NoError(t, err)
+				assert.Equal(t, testVars.serviceReturn, responseRoster)
+			}
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			assert.Equal(t, testVars.expectedLink, rr.Header().Get("Link"))
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+//enrollment.go defines the handler logic of all /api/person/{id}/courses http endpoints.
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"tech-challenge/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type EnrollmentHandler struct {
+	EnrollmentService services.EnrollmentService
+}
+
+func (e *EnrollmentHandler) GetCoursesForPerson(w http.ResponseWriter, r *http.Request) {
+	personID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+		return
+	}
+	courses, err := e.EnrollmentService.GetCoursesForPerson(r.Context(), personID)
+	if err != nil {
+		writeServiceError(w, r, "could not get courses for person", err)
+		return
+	}
+	err = json.NewEncoder(w).Encode(courses)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+func (e *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	personID, courseID, ok := parseEnrollmentIDs(w, r)
+	if !ok {
+		return
+	}
+	created, err := e.EnrollmentService.Enroll(r.Context(), personID, courseID)
+	if err != nil {
+		writeServiceError(w, r, "could not enroll person", err)
+		return
+	}
+	message := "person already enrolled in course"
+	if created {
+		w.WriteHeader(http.StatusCreated)
+		message = "person successfully enrolled in course"
+	}
+	err = json.NewEncoder(w).Encode(message)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+func (e *EnrollmentHandler) Drop(w http.ResponseWriter, r *http.Request) {
+	personID, courseID, ok := parseEnrollmentIDs(w, r)
+	if !ok {
+		return
+	}
+	err := e.EnrollmentService.Drop(r.Context(), personID, courseID)
+	if err != nil {
+		writeServiceError(w, r, "could not drop course", err)
+		return
+	}
+	err = json.NewEncoder(w).Encode("person successfully dropped from course")
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+
+// parseEnrollmentIDs reads the id and courseId URL parameters. It writes a 400 response and returns false if either
+// is not an int.
+func parseEnrollmentIDs(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	personID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+		return 0, 0, false
+	}
+	courseID, err := strconv.Atoi(chi.URLParam(r, "courseId"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse courseId to int")
+		return 0, 0, false
+	}
+	return personID, courseID, true
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetCoursesForPerson(t *testing.T) {
+	testCases := map[string]struct {
+		id               string
+		serviceReturn    []models.Course
+		serviceErr       error
+		expectedReturn   []models.Course
+		expectedHTTPCode int
+	}{
+		"success": {
+			id:               "3",
+			serviceReturn:    []models.Course{{ID: 1, Name: "Programming"}, {ID: 2, Name: "Databases"}},
+			expectedReturn:   []models.Course{{ID: 1, Name: "Programming"}, {ID: 2, Name: "Databases"}},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"success no courses": {
+			id:               "3",
+			serviceReturn:    []models.Course{},
+			expectedReturn:   []models.Course{},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"can't parse": {
+			id:               "three",
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"person not found": {
+			id:               "3",
+			serviceReturn:    []models.Course{},
+			serviceErr:       services.ErrPersonNotFound,
+			expectedHTTPCode: http.StatusNotFound,
+		},
+		"internal error": {
+			id:               "3",
+			serviceReturn:    []models.Course{},
+			serviceErr:       errors.New("can't get courses!"),
+			expectedHTTPCode: http.StatusInternalServerError,
+		},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/api/person/"+testVars.id+"/courses", nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testVars.id)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			mockService := new(services.MockEnrollmentService)
+			handler := &EnrollmentHandler{EnrollmentService: mockService}
+			rr := httptest.NewRecorder()
+
+			if test != "can't parse" {
+				intID, _ := strconv.Atoi(testVars.id)
+				mockService.On("GetCoursesForPerson", mock.Anything, intID).Return(testVars.serviceReturn, testVars.serviceErr)
+			}
+			handler.GetCoursesForPerson(rr, req)
+
+			if testVars.expectedHTTPCode == http.StatusOK {
+				var responseCourses []models.Course
+				err = json.NewDecoder(rr.Body).Decode(&responseCourses)
+				assert.NoError(t, err)
+				assert.Equal(t, testVars.expectedReturn, responseCourses)
+			}
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnroll(t *testing.T) {
+	testCases := map[string]struct {
+		id               string
+		courseID         string
+		serviceReturn    bool
+		serviceErr       error
+		expectedHTTPCode int
+	}{
+		"success enrolled":         {id: "3", courseID: "2", serviceReturn: true, expectedHTTPCode: http.StatusCreated},
+		"success already enrolled": {id: "3", courseID: "2", serviceReturn: false, expectedHTTPCode: http.StatusOK},
+		"can't parse id":           {id: "three", courseID: "2", expectedHTTPCode: http.StatusBadRequest},
+		"can't parse courseId":     {id: "3", courseID: "two", expectedHTTPCode: http.StatusBadRequest},
+		"person not found":         {id: "3", courseID: "2", serviceErr: services.ErrPersonNotFound, expectedHTTPCode: http.StatusNotFound},
+		"course not found":         {id: "3", courseID: "2", serviceErr: services.ErrCourseNotFound, expectedHTTPCode: http.StatusNotFound},
+		"internal error":           {id: "3", courseID: "2", serviceErr: errors.New("can't enroll!"), expectedHTTPCode: http.StatusInternalServerError},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "/api/person/"+testVars.id+"/courses/"+testVars.courseID, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testVars.id)
+			rctx.URLParams.Add("courseId", testVars.courseID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			mockService := new(services.MockEnrollmentService)
+			handler := &EnrollmentHandler{EnrollmentService: mockService}
+			rr := httptest.NewRecorder()
+
+			if testVars.expectedHTTPCode != http.StatusBadRequest {
+				mockService.On("Enroll", mock.Anything, 3, 2).Return(testVars.serviceReturn, testVars.serviceErr)
+			}
+			handler.Enroll(rr, req)
+
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDrop(t *testing.T) {
+	testCases := map[string]struct {
+		id               string
+		courseID         string
+		serviceErr       error
+		expectedHTTPCode int
+	}{
+		"success":              {id: "3", courseID: "2", expectedHTTPCode: http.StatusOK},
+		"can't parse courseId": {id: "3", courseID: "two", expectedHTTPCode: http.StatusBadRequest},
+		"not enrolled":         {id: "3", courseID: "2", serviceErr: services.ErrEnrollmentNotFound, expectedHTTPCode: http.StatusNotFound},
+		"internal error":       {id: "3", courseID: "2", serviceErr: errors.New("can't drop!"), expectedHTTPCode: http.StatusInternalServerError},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodDelete, "/api/person/"+testVars.id+"/courses/"+testVars.courseID, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testVars.id)
+			rctx.URLParams.Add("courseId", testVars.courseID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			mockService := new(services.MockEnrollmentService)
+			handler := &EnrollmentHandler{EnrollmentService: mockService}
+			rr := httptest.NewRecorder()
+
+			if testVars.expectedHTTPCode != http.StatusBadRequest {
+				mockService.On("Drop", mock.Anything, 3, 2).Return(testVars.serviceErr)
+			}
+			handler.Drop(rr, req)
+
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package handlers
+
+//health.go defines the handler of the /health endpoint, which load balancers and orchestrators poll.
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Health reports that the server is up and handling requests. It does not require authentication.
+func Health(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
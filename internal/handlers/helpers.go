@@ -3,11 +3,14 @@ package handlers
 //helpers.go defines miscellaneous helper functions used in ../handlers/* and ../services/*
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"strconv"
 	"strings"
+	"tech-challenge/internal/logging"
+	"tech-challenge/internal/services"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -44,6 +47,34 @@ func areUnique(input []int) bool {
 	}
 	return true
 }
+
+// logError logs why a request failed with status to the request's logger, as an error if the server is to blame and
+// as a warning if the client is.
 func logError(r *http.Request, message string, status int) {
-	log.Printf(strconv.Itoa(status) + " ERROR: " + message + " at: " + r.Method + " " + r.URL.Path)
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), level, message,
+		slog.Int("status", status),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path))
+}
+
+// statusFromError returns the HTTP status code matching the kind of an error returned by ../services.
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidReference), errors.Is(err, services.ErrConstraintViolation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
 }
@@ -0,0 +1,87 @@
+package handlers
+
+//mergepatch.go applies JSON Merge Patch (RFC 7396) request bodies to ../models objects for the PATCH endpoints.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// decodeMergePatch applies the merge patch in the body of r to current and returns the result. It writes the error
+// response itself and returns false if the body is not a merge patch or the patched object does not fit the model.
+func decodeMergePatch[T any](w http.ResponseWriter, r *http.Request, current T) (T, bool) {
+	var patched T
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != mergePatchContentType {
+		w.Header().Set("Accept-Patch", mergePatchContentType)
+		writeProblem(w, r, http.StatusUnsupportedMediaType, "unsupported media type: content type must be "+mergePatchContentType)
+		return patched, false
+	}
+
+	var patch any
+	if err = json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return patched, false
+	}
+	if _, isObject := patch.(map[string]any); !isObject {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: merge patch must be a JSON object")
+		return patched, false
+	}
+
+	patched, err = applyMergePatch(current, patch)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
+		return patched, false
+	}
+	return patched, true
+}
+
+// applyMergePatch returns target with patch merged into its JSON representation. Fields the model does not have are
+// rejected.
+func applyMergePatch[T any](target T, patch any) (T, error) {
+	var patched T
+	raw, err := json.Marshal(target)
+	if err != nil {
+		return patched, err
+	}
+	var document any
+	if err = json.Unmarshal(raw, &document); err != nil {
+		return patched, err
+	}
+	raw, err = json.Marshal(mergePatch(document, patch))
+	if err != nil {
+		return patched, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&patched); err != nil {
+		return patched, fmt.Errorf("cannot apply patch: %w", err)
+	}
+	return patched, nil
+}
+
+// mergePatch implements the MergePatch function of RFC 7396 section 2. Members of patch set to null are removed from
+// target, objects are merged recursively and any other value replaces the target value.
+func mergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+		} else {
+			targetObject[name] = mergePatch(targetObject[name], value)
+		}
+	}
+	return targetObject
+}
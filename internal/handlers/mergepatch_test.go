@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// the examples from RFC 7396 appendix A, limited to object patches
+func TestMergePatch(t *testing.T) {
+	testCases := map[string]struct {
+		target   string
+		patch    string
+		expected string
+	}{
+		"replace":         {target: `{"a":"b"}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		"add":             {target: `{"a":"b"}`, patch: `{"b":"c"}`, expected: `{"a":"b","b":"c"}`},
+		"remove":          {target: `{"a":"b"}`, patch: `{"a":null}`, expected: `{}`},
+		"remove one":      {target: `{"a":"b","b":"c"}`, patch: `{"a":null}`, expected: `{"b":"c"}`},
+		"array replaces":  {target: `{"a":["b"]}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		"replace array":   {target: `{"a":"c"}`, patch: `{"a":["b"]}`, expected: `{"a":["b"]}`},
+		"nested":          {target: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, expected: `{"a":{"b":"d"}}`},
+		"array of object": {target: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, expected: `{"a":[1]}`},
+		"empty target":    {target: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, expected: `{"a":{"bb":{}}}`},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			var target, patch any
+			assert.NoError(t, json.Unmarshal([]byte(testVars.target), &target))
+			assert.NoError(t, json.Unmarshal([]byte(testVars.patch), &patch))
+			result, err := json.Marshal(mergePatch(target, patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, testVars.expected, string(result))
+		})
+	}
+}
+
+func TestPatchPersonByID(t *testing.T) {
+	current := models.Person{ID: 25, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Age: 27, Courses: []int{1, 2}}
+	newAge := 28
+	newCourses := []int{2, 3}
+	testCases := map[string]struct {
+		contentType      string
+		body             string
+		expectedPatch    *services.PersonPatch
+		serviceReturn    models.Person
+		serviceErr       error
+		expectedReturn   models.Person
+		expectedHTTPCode int
+	}{
+		"success age": {
+			contentType:      mergePatchContentType,
+			body:             `{"age": 28}`,
+			expectedPatch:    &services.PersonPatch{Age: &newAge},
+			serviceReturn:    models.Person{ID: 25, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Age: 28, Courses: []int{1, 2}},
+			expectedReturn:   models.Person{ID: 25, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Age: 28, Courses: []int{1, 2}},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"success courses": {
+			contentType:      mergePatchContentType + "; charset=utf-8",
+			body:             `{"courses": [2, 3]}`,
+			expectedPatch:    &services.PersonPatch{Courses: &newCourses},
+			serviceReturn:    models.Person{ID: 25, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Age: 27, Courses: []int{2, 3}},
+			expectedReturn:   models.Person{ID: 25, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Age: 27, Courses: []int{2, 3}},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"success nothing changed": {
+			contentType:      mergePatchContentType,
+			body:             `{"courses": [2, 1], "id": 99}`,
+			expectedReturn:   current,
+			expectedHTTPCode: http.StatusOK,
+		},
+		"failure content type": {
+			contentType:      "application/json",
+			body:             `{"age": 28}`,
+			expectedHTTPCode: http.StatusUnsupportedMediaType,
+		},
+		"failure removes required field": {
+			contentType:      mergePatchContentType,
+			body:             `{"type": null}`,
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"failure unknown field": {
+			contentType:      mergePatchContentType,
+			body:             `{"salary": 100}`,
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"failure not an object": {
+			contentType:      mergePatchContentType,
+			body:             `[1, 2]`,
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"failure unknown course": {
+			contentType:      mergePatchContentType,
+			body:             `{"courses": [2, 3]}`,
+			expectedPatch:    &services.PersonPatch{Courses: &newCourses},
+			serviceReturn:    models.Person{},
+			serviceErr:       services.ErrUnknownCourse,
+			expectedHTTPCode: http.StatusUnprocessableEntity,
+		},
+		"failure internal error": {
+			contentType:      mergePatchContentType,
+			body:             `{"age": 28}`,
+			expectedPatch:    &services.PersonPatch{Age: &newAge},
+			serviceReturn:    models.Person{},
+			serviceErr:       errors.New("can't patch person!"),
+			expectedHTTPCode: http.StatusInternalServerError,
+		},
+	}
+
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPatch, "/api/person/id/25", strings.NewReader(testVars.body))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", testVars.contentType)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "25")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			mockService := new(services.MockPersonService)
+			handler := &PersonHandler{PersonService: mockService}
+			rr := httptest.NewRecorder()
+
+			mockService.On("GetPersonByID", mock.Anything, 25).Return(current, nil)
+			if testVars.expectedPatch != nil {
+				mockService.On("PatchPersonByID", mock.Anything, 25, *testVars.expectedPatch).Return(testVars.serviceReturn, testVars.serviceErr)
+			}
+			handler.PatchPersonByID(rr, req)
+
+			if testVars.expectedHTTPCode == http.StatusOK {
+				var responsePerson models.Person
+				err = json.NewDecoder(rr.Body).Decode(&responsePerson)
+				assert.NoError(t, err)
+				assert.Equal(t, testVars.expectedReturn, responsePerson)
+			}
+			if testVars.expectedHTTPCode == http.StatusUnsupportedMediaType {
+				assert.Equal(t, mergePatchContentType, rr.Header().Get("Accept-Patch"))
+			}
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatchCourse(t *testing.T) {
+	current := models.Course{ID: 4, Name: "Databases"}
+	newName := "Advanced Databases"
+	testCases := map[string]struct {
+		body             string
+		getErr           error
+		callsPatch       bool
+		serviceErr       error
+		expectedReturn   models.Course
+		expectedHTTPCode int
+	}{
+		"success": {
+			body:             `{"name": "Advanced Databases"}`,
+			callsPatch:       true,
+			expectedReturn:   models.Course{ID: 4, Name: newName},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"success unchanged": {
+			body:             `{"name": "Databases"}`,
+			expectedReturn:   current,
+			expectedHTTPCode: http.StatusOK,
+		},
+		"failure course not found": {
+			body:             `{"name": "Advanced Databases"}`,
+			getErr:           services.ErrCourseNotFound,
+			expectedHTTPCode: http.StatusNotFound,
+		},
+		"failure name removed": {
+			body:             `{"name": null}`,
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"failure internal error": {
+			body:             `{"name": "Advanced Databases"}`,
+			callsPatch:       true,
+			serviceErr:       errors.New("can't patch course!"),
+			expectedHTTPCode: http.StatusInternalServerError,
+		},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPatch, "/api/course/4", strings.NewReader(testVars.body))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", mergePatchContentType)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "4")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			mockService := new(services.MockCourseService)
+			handler := &CourseHandler{CourseService: mockService}
+			rr := httptest.NewRecorder()
+
+			if testVars.getErr != nil {
+				mockService.On("GetCourse", mock.Anything, 4).Return(models.Course{}, testVars.getErr)
+			} else {
+				mockService.On("GetCourse", mock.Anything, 4).Return(current, nil)
+			}
+			if testVars.callsPatch {
+				mockService.On("PatchCourse", mock.Anything, 4, services.CoursePatch{Name: &newName}).Return(testVars.expectedReturn, testVars.serviceErr)
+			}
+			handler.PatchCourse(rr, req)
+
+			if testVars.expectedHTTPCode == http.StatusOK {
+				var responseCourse models.Course
+				err = json.NewDecoder(rr.Body).Decode(&responseCourse)
+				assert.NoError(t, err)
+				assert.Equal(t, testVars.expectedReturn, responseCourse)
+			}
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+//pagination.go defines the query parameters and response headers shared by the paginated listing endpoints.
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"tech-challenge/internal/services"
+)
+
+const (
+	// defaultPageLimit is the page size used when a listing request has no limit query parameter.
+	defaultPageLimit = 100
+	// maxPageLimit is the largest page size a client can request.
+	maxPageLimit = 1000
+)
+
+// parseListOptions reads the limit, cursor and include_total query parameters of a listing request.
+func parseListOptions(params url.Values) (services.ListOptions, error) {
+	opts := services.ListOptions{Limit: defaultPageLimit, Cursor: params.Get("cursor")}
+	if params.Has("limit") {
+		limit, err := strconv.Atoi(params.Get("limit"))
+		if err != nil {
+			return services.ListOptions{}, fmt.Errorf("cannot parse limit to int")
+		}
+		if limit < 1 || limit > maxPageLimit {
+			return services.ListOptions{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		opts.Limit = limit
+	}
+	if params.Has("include_total") {
+		includeTotal, err := strconv.ParseBool(params.Get("include_total"))
+		if err != nil {
+			return services.ListOptions{}, fmt.Errorf("cannot parse include_total to bool")
+		}
+		opts.IncludeTotal = includeTotal
+	}
+	return opts, nil
+}
+
+// setPageHeaders describes the page being returned. A Link header points at the next page, if there is one, and
+// X-Total-Count holds the total number of items when it was requested.
+func setPageHeaders(w http.ResponseWriter, r *http.Request, opts services.ListOptions, pageInfo services.PageInfo) {
+	if pageInfo.NextCursor != "" {
+		params := r.URL.Query()
+		params.Set("cursor", pageInfo.NextCursor)
+		params.Set("limit", strconv.Itoa(opts.Limit))
+		next := url.URL{Path: r.URL.Path, RawQuery: params.Encode()}
+		w.Header().Set("Link", "<"+next.String()+`>; rel="next"`)
+	}
+	if opts.IncludeTotal {
+		w.Header().Set("X-Total-Count", strconv.Itoa(pageInfo.Total))
+	}
+}
+
+// parseSort reads a sort query parameter such as "last_name,-age", where a leading "-" sorts that field in descending
+// order. Every field must be one of sortable and appear once. An empty value returns nil, the default order.
+func parseSort(value string, sortable []string) ([]services.SortField, []FieldError) {
+	if value == "" {
+		return nil, nil
+	}
+	var sort []services.SortField
+	var fieldErrs []FieldError
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		sortField := services.SortField{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+		switch {
+		case sortField.Field == "":
+			fieldErrs = append(fieldErrs, FieldError{Field: "sort", Rule: "required", Message: "sort must not contain an empty field"})
+		case !slices.Contains(sortable, sortField.Field):
+			fieldErrs = append(fieldErrs, FieldError{
+				Field:   "sort",
+				Rule:    "oneof",
+				Param:   strings.Join(sortable, " "),
+				Message: "cannot sort by " + sortField.Field + ", must be one of " + strings.Join(sortable, ", "),
+			})
+		case seen[sortField.Field]:
+			fieldErrs = append(fieldErrs, FieldError{Field: "sort", Rule: "unique", Message: "sort must not contain " + sortField.Field + " twice"})
+		default:
+			seen[sortField.Field] = true
+			sort = append(sort, sortField)
+		}
+	}
+	if len(fieldErrs) > 0 {
+		return nil, fieldErrs
+	}
+	return sort, nil
+}
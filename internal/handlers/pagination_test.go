@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseListOptions(t *testing.T) {
+	testCases := map[string]struct {
+		query          string
+		expectedReturn services.ListOptions
+		expectErr      bool
+	}{
+		"defaults":            {query: "", expectedReturn: services.ListOptions{Limit: defaultPageLimit}},
+		"limit and cursor":    {query: "limit=5&cursor=abc", expectedReturn: services.ListOptions{Limit: 5, Cursor: "abc"}},
+		"include total":       {query: "include_total=true", expectedReturn: services.ListOptions{Limit: defaultPageLimit, IncludeTotal: true}},
+		"failure limit zero":  {query: "limit=0", expectErr: true},
+		"failure limit large": {query: "limit=1001", expectErr: true},
+		"failure limit parse": {query: "limit=ten", expectErr: true},
+		"failure total parse": {query: "include_total=maybe", expectErr: true},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/course?"+testVars.query, nil)
+			opts, err := parseListOptions(req.URL.Query())
+			if testVars.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testVars.expectedReturn, opts)
+		})
+	}
+}
+
+func TestGetAllCoursesPageHeaders(t *testing.T) {
+	testCases := map[string]struct {
+		query            string
+		opts             services.ListOptions
+		pageInfo         services.PageInfo
+		expectedLink     string
+		expectedTotal    string
+		expectedHTTPCode int
+	}{
+		"next page and total": {
+			query:            "limit=2&include_total=true",
+			opts:             services.ListOptions{Limit: 2, IncludeTotal: true},
+			pageInfo:         services.PageInfo{NextCursor: "eyJpZCI6Mn0", Total: 5},
+			expectedLink:     `</api/course?cursor=eyJpZCI6Mn0&include_total=true&limit=2>; rel="next"`,
+			expectedTotal:    "5",
+			expectedHTTPCode: http.StatusOK,
+		},
+		"last page": {
+			query:            "limit=2&cursor=eyJpZCI6NH0",
+			opts:             services.ListOptions{Limit: 2, Cursor: "eyJpZCI6NH0"},
+			pageInfo:         services.PageInfo{Total: -1},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"failure invalid cursor": {
+			query:            "cursor=nonsense",
+			opts:             services.ListOptions{Limit: defaultPageLimit, Cursor: "nonsense"},
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			mockService := new(services.MockCourseService)
+			handler := &CourseHandler{CourseService: mockService}
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/course?"+testVars.query, nil)
+
+			var serviceErr error
+			if testVars.expectedHTTPCode == http.StatusBadRequest {
+				serviceErr = services.ErrInvalidCursor
+			}
+			mockService.On("GetAllCourses", mock.Anything, testVars.opts).Return([]models.Course{{ID: 1, Name: "Class 1"}}, testVars.pageInfo, serviceErr)
+			handler.GetAllCourses(rr, req)
+
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			assert.Equal(t, testVars.expectedLink, rr.Header().Get("Link"))
+			assert.Equal(t, testVars.expectedTotal, rr.Header().Get("X-Total-Count"))
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	testCases := map[string]struct {
+		value          string
+		expectedReturn []services.SortField
+		expectedRules  []string
+	}{
+		"empty": {value: "", expectedReturn: nil},
+		"ascending and descending": {
+			value:          "last_name,-age",
+			expectedReturn: []services.SortField{{Field: "last_name"}, {Field: "age", Desc: true}},
+		},
+		"failure unknown field":  {value: "last_name,-salary", expectedRules: []string{"oneof"}},
+		"failure repeated field": {value: "age,-age", expectedRules: []string{"unique"}},
+		"failure empty field":    {value: "age,,id", expectedRules: []string{"required"}},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			sort, fieldErrs := parseSort(testVars.value, services.PersonSortFields)
+			assert.Equal(t, testVars.expectedReturn, sort)
+			var rules []string
+			for _, fieldErr := range fieldErrs {
+				assert.Equal(t, "sort", fieldErr.Field)
+				rules = append(rules, fieldErr.Rule)
+			}
+			assert.Equal(t, testVars.expectedRules, rules)
+		})
+	}
+}
+
+func TestGetAllPeopleSorted(t *testing.T) {
+	mockService := new(services.MockPersonService)
+	handler := &PersonHandler{PersonService: mockService}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/person?sort=-age,first_name", nil)
+	opts := services.ListOptions{Limit: defaultPageLimit, Sort: []services.SortField{{Field: "age", Desc: true}, {Field: "first_name"}}}
+	mockService.On("GetAllPeople", mock.Anything, services.PersonFilter{}, opts).Return([]models.Person{}, services.PageInfo{Total: -1}, nil)
+	handler.GetAllPeople(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/api/person?sort=courses", nil)
+	handler.GetAllPeople(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "cannot sort by courses")
+
+	mockService.AssertExpectations(t)
+}
@@ -4,62 +4,211 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"tech-challenge/internal/auth"
 	"tech-challenge/internal/models"
 	"tech-challenge/internal/services"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 )
 
 type PersonHandler struct {
 	PersonService services.PersonService
+	// Authorizer, when set, checks that the caller may act on the person a name route resolves to, see resolvePerson.
+	Authorizer Authorizer
 }
 
-func (p *PersonHandler) GetAllPeople(w http.ResponseWriter, r *http.Request) {
-	params := r.URL.Query()
-	name := params.Get("name")
-	var age = -1
+// personCandidate is the summary of one person sharing a name, returned when a name-based request is ambiguous.
+type personCandidate struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+	Age  int    `json:"age"`
+}
 
-	if params.Has("age") {
-		ageString := params.Get("age")
+// ambiguousPersonProblem is the body of a 409 response to a name-based request that matched more than one person.
+type ambiguousPersonProblem struct {
+	Problem
+	Candidates []personCandidate `json:"candidates"`
+}
+
+// resolvePerson finds the one person named firstName lastName. If several people share that name, the optional "id"
+// query parameter picks between them, otherwise a 409 listing every candidate is written. With an Authorizer, the
+// caller must be allowed to perform action on the person found, and the 409 only lists the candidates they may.
+// resolvePerson writes the error response itself and returns false whenever it cannot settle on exactly one person.
+func (p *PersonHandler) resolvePerson(w http.ResponseWriter, r *http.Request, action auth.Action, firstName string, lastName string) (models.Person, bool) {
+	wantedID := -1
+	if r.URL.Query().Has("id") {
 		var err error
-		age, err = strconv.Atoi(ageString)
+		wantedID, err = strconv.Atoi(r.URL.Query().Get("id"))
 		if err != nil {
-			logError(r, "bad request: cannot parse age to int", http.StatusBadRequest)
-			http.Error(w, "bad request: cannot parse age to int", http.StatusBadRequest)
-			return
+			writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+			return models.Person{}, false
 		}
 	}
-	if age < 0 && params.Has("age") {
-		logError(r, "bad request: age must be greater than 0", http.StatusBadRequest)
-		http.Error(w, "bad request: age must be greater than 0", http.StatusBadRequest)
-		return
+
+	matches, _, err := p.PersonService.GetAllPeople(r.Context(), services.PersonFilter{FirstName: firstName, LastName: lastName}, services.ListOptions{})
+	if err != nil {
+		writeServiceError(w, r, "could not get person", err)
+		return models.Person{}, false
+	}
+	if wantedID != -1 {
+		matches = slices.DeleteFunc(matches, func(match models.Person) bool { return match.ID != wantedID })
+	}
+	if len(matches) == 0 {
+		writeProblem(w, r, http.StatusNotFound, "person not found")
+		return models.Person{}, false
 	}
+	if len(matches) > 1 {
+		detail := "multiple people named " + firstName + " " + lastName + ", use the id query parameter to choose one"
+		problem := ambiguousPersonProblem{
+			Problem:    newProblem(r, http.StatusConflict, detail),
+			Candidates: make([]personCandidate, 0, len(matches)),
+		}
+		for _, match := range matches {
+			allowed, err := p.allows(r, action, match)
+			if err != nil {
+				writeServiceError(w, r, "could not authorize request", err)
+				return models.Person{}, false
+			}
+			if allowed {
+				problem.Candidates = append(problem.Candidates, personCandidate{ID: match.ID, Type: match.Type, Age: match.Age})
+			}
+		}
+		logError(r, detail, http.StatusConflict)
+		encodeProblem(w, http.StatusConflict, problem)
+		return models.Person{}, false
+	}
+	if p.Authorizer != nil && !authorizeRequest(w, r, p.Authorizer, action, auth.Resource{PersonID: matches[0].ID}) {
+		return models.Person{}, false
+	}
+	return matches[0], true
+}
+
+// allows returns whether the caller of r may perform action on person. Without an Authorizer anyone may.
+func (p *PersonHandler) allows(r *http.Request, action auth.Action, person models.Person) (bool, error) {
+	if p.Authorizer == nil {
+		return true, nil
+	}
+	identity, ok := auth.FromContext(r.Context())
+	if !ok {
+		return false, nil
+	}
+	err := p.Authorizer.Authorize(r.Context(), identity, action, auth.Resource{PersonID: person.ID})
+	if errors.Is(err, auth.ErrForbidden) {
+		return false, nil
+	}
+	return err == nil, err
+}
 
-	firstName, lastName := "", ""
-	if name != "" {
+// parsePersonFilter reads the filters of a person listing request. name takes a full "first last" name, while
+// first_name and last_name filter on one name each. name_match chooses exact, prefix or contains matching for all
+// three, and course_id may be repeated or comma separated to match people enrolled in any of the courses.
+func parsePersonFilter(params url.Values) (services.PersonFilter, error) {
+	var filter services.PersonFilter
+	if name := params.Get("name"); name != "" {
+		if params.Has("first_name") || params.Has("last_name") {
+			return services.PersonFilter{}, fmt.Errorf("name cannot be combined with first_name or last_name")
+		}
 		var err error
-		firstName, lastName, err = formatName(name)
+		filter.FirstName, filter.LastName, err = formatName(name)
 		if err != nil {
-			logError(r, "bad request: "+err.Error(), http.StatusBadRequest)
-			http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
-			return
+			return services.PersonFilter{}, err
+		}
+	}
+	if params.Has("first_name") {
+		filter.FirstName = strings.TrimSpace(params.Get("first_name"))
+	}
+	if params.Has("last_name") {
+		filter.LastName = strings.TrimSpace(params.Get("last_name"))
+	}
+	if params.Has("name_match") {
+		filter.NameMatch = services.NameMatch(params.Get("name_match"))
+		switch filter.NameMatch {
+		case services.NameExact, services.NamePrefix, services.NameContains:
+		default:
+			return services.PersonFilter{}, fmt.Errorf(`name_match must be "exact", "prefix" or "contains"`)
+		}
+	}
+	if params.Has("type") {
+		filter.Type = params.Get("type")
+		if filter.Type != "professor" && filter.Type != "student" {
+			return services.PersonFilter{}, fmt.Errorf(`type must be either "professor" or "student"`)
+		}
+	}
+
+	var err error
+	if filter.Age, err = parseAgeParam(params, "age"); err != nil {
+		return services.PersonFilter{}, err
+	}
+	if filter.MinAge, err = parseAgeParam(params, "age_gte"); err != nil {
+		return services.PersonFilter{}, err
+	}
+	if filter.MaxAge, err = parseAgeParam(params, "age_lte"); err != nil {
+		return services.PersonFilter{}, err
+	}
+
+	for _, values := range params["course_id"] {
+		for _, value := range strings.Split(values, ",") {
+			courseID, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return services.PersonFilter{}, fmt.Errorf("cannot parse course_id to int")
+			}
+			filter.CourseIDs = append(filter.CourseIDs, courseID)
 		}
 	}
+	return filter, nil
+}
+
+// parseAgeParam returns the non-negative age in the key query parameter, or nil if it is absent.
+func parseAgeParam(params url.Values, key string) (*int, error) {
+	if !params.Has(key) {
+		return nil, nil
+	}
+	age, err := strconv.Atoi(params.Get(key))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s to int", key)
+	}
+	if age < 0 {
+		return nil, fmt.Errorf("%s must be greater than 0", key)
+	}
+	return &age, nil
+}
 
-	people, err := p.PersonService.GetAllPeople(age, firstName, lastName)
+func (p *PersonHandler) GetAllPeople(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	filter, err := parsePersonFilter(params)
 	if err != nil {
-		logError(r, "internal error: "+err.Error(), http.StatusInternalServerError)
-		http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
 		return
 	}
+
+	opts, err := parseListOptions(params)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	var fieldErrs []FieldError
+	if opts.Sort, fieldErrs = parseSort(params.Get("sort"), services.PersonSortFields); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "cannot sort people as requested", fieldErrs)
+		return
+	}
+
+	people, pageInfo, err := p.PersonService.GetAllPeople(r.Context(), filter, opts)
+	if err != nil {
+		writeServiceError(w, r, "could not get people", err)
+		return
+	}
+	setPageHeaders(w, r, opts, pageInfo)
 	err = json.NewEncoder(w).Encode(people)
 	if err != nil {
-		logError(r, "internal error", http.StatusInternalServerError)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
 		return
 	}
 }
@@ -67,90 +216,64 @@ func (p *PersonHandler) GetPerson(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 
 	if name == "" {
-		logError(r, "bad request: name required", http.StatusBadRequest)
-		http.Error(w, "bad request: name required", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: name required")
 		return
 	}
 	firstName, lastName, err := formatName(name)
 	if err != nil {
-		logError(r, "bad request: "+err.Error(), http.StatusBadRequest)
-		http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
-		return
-	}
-	person, err := p.PersonService.GetPerson(firstName, lastName)
-	if err != nil {
-		logError(r, "internal error: "+err.Error(), http.StatusInternalServerError)
-		http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
 		return
 	}
-	if reflect.DeepEqual(person, models.Person{}) {
-		logError(r, "person not found", http.StatusNotFound)
-		http.Error(w, "person not found", http.StatusNotFound)
+	person, ok := p.resolvePerson(w, r, auth.ReadPerson, firstName, lastName)
+	if !ok {
 		return
 	}
 	err = json.NewEncoder(w).Encode(person)
 	if err != nil {
-		logError(r, "internal error", http.StatusInternalServerError)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
 		return
 	}
 }
 func (p *PersonHandler) UpdatePerson(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	if name == "" {
-		logError(r, "bad request: name required", http.StatusBadRequest)
-		http.Error(w, "bad request: name required", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: name required")
 		return
 	}
 	firstName, lastName, err := formatName(name)
 	if err != nil {
-		logError(r, "bad request: "+err.Error(), http.StatusBadRequest)
-		http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
 		return
 	}
 	var person models.Person
 	err = json.NewDecoder(r.Body).Decode(&person)
 	if err != nil {
-		logError(r, err.Error(), http.StatusBadRequest)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
-	if !areUnique(person.Courses) {
-		logError(r, "bad request: class IDs must be unique", http.StatusBadRequest)
-		http.Error(w, "bad request: class IDs must be unique", http.StatusBadRequest)
-		return
-	}
-	validate := validator.New(validator.WithRequiredStructEnabled())
-	validate.RegisterValidation("ValidateType", ValidateType)
-	err = validate.Struct(person)
-	if err != nil {
-		logError(r, "validation for person object failed", http.StatusBadRequest)
-		http.Error(w, "validation for person object failed", http.StatusBadRequest)
+	if fieldErrs := validatePerson(person); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "validation for person object failed", fieldErrs)
 		return
 	}
 	person.FirstName, person.LastName, err = formatName(person.FirstName + " " + person.LastName)
 	if err != nil {
-		logError(r, "bad request: "+err.Error(), http.StatusBadRequest)
-		http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
 		return
 	}
 
-	updatedPerson, err := p.PersonService.UpdatePerson(firstName, lastName, person)
-	if err != nil && (err.Error() == "person not found" ||
-		err.Error() == "course not found, trying to join a course that doesn't exist") {
-		logError(r, err.Error(), http.StatusNotFound)
-		http.Error(w, err.Error(), http.StatusNotFound)
+	match, ok := p.resolvePerson(w, r, auth.UpdatePerson, firstName, lastName)
+	if !ok {
 		return
 	}
+
+	updatedPerson, err := p.PersonService.UpdatePersonByID(r.Context(), match.ID, person)
 	if err != nil {
-		logError(r, "error updating person: "+err.Error(), http.StatusInternalServerError)
-		http.Error(w, "error updating person: "+err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, r, "error updating person", err)
 		return
 	}
 	err = json.NewEncoder(w).Encode(updatedPerson)
 	if err != nil {
-		logError(r, "internal error", http.StatusInternalServerError)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
 		return
 	}
 }
@@ -158,33 +281,21 @@ func (p *PersonHandler) CreatePerson(w http.ResponseWriter, r *http.Request) {
 	var person models.Person
 	err := json.NewDecoder(r.Body).Decode(&person)
 	if err != nil {
-		logError(r, err.Error(), http.StatusBadRequest)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
-	if !areUnique(person.Courses) {
-		logError(r, "bad request: class IDs must be unique", http.StatusBadRequest)
-		http.Error(w, "bad request: class IDs must be unique", http.StatusBadRequest)
+	if fieldErrs := validatePerson(person); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "validation for person object failed", fieldErrs)
 		return
 	}
-	validate := validator.New(validator.WithRequiredStructEnabled())
-	validate.RegisterValidation("ValidateType", ValidateType)
-	err = validate.Struct(person)
+	insertedID, err := p.PersonService.CreatePerson(r.Context(), person)
 	if err != nil {
-		logError(r, "validation for person object failed", http.StatusBadRequest)
-		http.Error(w, "validation for person object failed", http.StatusBadRequest)
-		return
-	}
-	insertedID, err := p.PersonService.CreatePerson(person)
-	if err != nil {
-		logError(r, "failed to create person: "+err.Error(), http.StatusInternalServerError)
-		http.Error(w, "failed to create person: "+err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, r, "failed to create person", err)
 		return
 	}
 	err = json.NewEncoder(w).Encode(insertedID)
 	if err != nil {
-		logError(r, "internal error", http.StatusInternalServerError)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
 		return
 	}
 }
@@ -192,31 +303,203 @@ func (p *PersonHandler) DeletePerson(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 
 	if name == "" {
-		logError(r, "bad request: name required", http.StatusBadRequest)
-		http.Error(w, "bad request: name required", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: name required")
 		return
 	}
 	firstName, lastName, err := formatName(name)
 	if err != nil {
-		logError(r, "bad request: "+err.Error(), http.StatusBadRequest)
-		http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	match, ok := p.resolvePerson(w, r, auth.DeletePerson, firstName, lastName)
+	if !ok {
+		return
+	}
+	deletedPersonCount, err := p.PersonService.DeletePersonByID(r.Context(), match.ID)
+	if err != nil {
+		writeServiceError(w, r, "could not delete person", err)
+		return
+	}
+	if deletedPersonCount == 0 {
+		writeProblem(w, r, http.StatusNotFound, "person not found")
+		return
+	}
+	err = json.NewEncoder(w).Encode("person successfully deleted")
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+func (p *PersonHandler) GetPersonByID(w http.ResponseWriter, r *http.Request) {
+	idString := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+		return
+	}
+	person, err := p.PersonService.GetPersonByID(r.Context(), idInt)
+	if err != nil {
+		writeServiceError(w, r, "could not get person", err)
 		return
 	}
-	deletedPersonCount, err := p.PersonService.DeletePerson(firstName, lastName)
-	if deletedPersonCount == 0 || (err != nil && err.Error() == "person not found") {
-		logError(r, "person not found", http.StatusNotFound)
-		http.Error(w, "person not found", http.StatusNotFound)
+	if reflect.DeepEqual(person, models.Person{}) {
+		writeProblem(w, r, http.StatusNotFound, "person not found")
 		return
 	}
+	err = json.NewEncoder(w).Encode(person)
 	if err != nil {
-		logError(r, "could not delete person: "+err.Error(), http.StatusInternalServerError)
-		http.Error(w, "could not delete person: "+err.Error(), http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+func (p *PersonHandler) UpdatePersonByID(w http.ResponseWriter, r *http.Request) {
+	idString := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+		return
+	}
+	var person models.Person
+	err = json.NewDecoder(r.Body).Decode(&person)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if fieldErrs := validatePerson(person); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "validation for person object failed", fieldErrs)
+		return
+	}
+	person.FirstName, person.LastName, err = formatName(person.FirstName + " " + person.LastName)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+
+	updatedPerson, err := p.PersonService.UpdatePersonByID(r.Context(), idInt, person)
+	if err != nil {
+		writeServiceError(w, r, "error updating person", err)
+		return
+	}
+	err = json.NewEncoder(w).Encode(updatedPerson)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+func (p *PersonHandler) DeletePersonByID(w http.ResponseWriter, r *http.Request) {
+	idString := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+		return
+	}
+	deletedPersonCount, err := p.PersonService.DeletePersonByID(r.Context(), idInt)
+	if err != nil {
+		writeServiceError(w, r, "could not delete person", err)
+		return
+	}
+	if deletedPersonCount == 0 {
+		writeProblem(w, r, http.StatusNotFound, "person not found")
 		return
 	}
 	err = json.NewEncoder(w).Encode("person successfully deleted")
 	if err != nil {
-		logError(r, "internal error", http.StatusInternalServerError)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+func (p *PersonHandler) PatchPerson(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: name required")
+		return
+	}
+	firstName, lastName, err := formatName(name)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	current, ok := p.resolvePerson(w, r, auth.UpdatePerson, firstName, lastName)
+	if !ok {
+		return
+	}
+	p.patchPerson(w, r, current)
+}
+func (p *PersonHandler) PatchPersonByID(w http.ResponseWriter, r *http.Request) {
+	idString := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+		return
+	}
+	current, err := p.PersonService.GetPersonByID(r.Context(), idInt)
+	if err != nil {
+		writeServiceError(w, r, "could not get person", err)
+		return
+	}
+	if reflect.DeepEqual(current, models.Person{}) {
+		writeProblem(w, r, http.StatusNotFound, "person not found")
+		return
+	}
+	p.patchPerson(w, r, current)
+}
+
+// patchPerson applies the merge patch in the request body to current, validates the result and stores whatever
+// changed.
+func (p *PersonHandler) patchPerson(w http.ResponseWriter, r *http.Request, current models.Person) {
+	person, ok := decodeMergePatch(w, r, current)
+	if !ok {
+		return
+	}
+	person.ID = current.ID
+	if fieldErrs := validatePerson(person); len(fieldErrs) > 0 {
+		writeValidationProblem(w, r, "validation for person object failed", fieldErrs)
 		return
 	}
+	var err error
+	person.FirstName, person.LastName, err = formatName(person.FirstName + " " + person.LastName)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+
+	patch, changed := diffPerson(current, person)
+	if !changed {
+		person = current
+	} else {
+		person, err = p.PersonService.PatchPersonByID(r.Context(), current.ID, patch)
+		if err != nil {
+			writeServiceError(w, r, "error updating person", err)
+			return
+		}
+	}
+	err = json.NewEncoder(w).Encode(person)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+}
+
+// diffPerson returns the patch that turns current into patched, and whether anything changed. Course lists are
+// compared regardless of order.
+func diffPerson(current models.Person, patched models.Person) (services.PersonPatch, bool) {
+	var patch services.PersonPatch
+	changed := false
+	if patched.FirstName != current.FirstName {
+		patch.FirstName, changed = &patched.FirstName, true
+	}
+	if patched.LastName != current.LastName {
+		patch.LastName, changed = &patched.LastName, true
+	}
+	if patched.Type != current.Type {
+		patch.Type, changed = &patched.Type, true
+	}
+	if patched.Age != current.Age {
+		patch.Age, changed = &patched.Age, true
+	}
+	currentCourses, patchedCourses := slices.Sorted(slices.Values(current.Courses)), slices.Sorted(slices.Values(patched.Courses))
+	if !slices.Equal(currentCourses, patchedCourses) {
+		patch.Courses, changed = &patched.Courses, true
+	}
+	return patch, changed
 }
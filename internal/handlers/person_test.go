@@ -16,7 +16,9 @@ import (
 	"testing"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetAllPeople(t *testing.T) {
@@ -158,8 +160,12 @@ func TestGetAllPeople(t *testing.T) {
 					ageInt, err = strconv.Atoi(testVars.age)
 					assert.NoError(t, err)
 				}
+				filter := services.PersonFilter{FirstName: firstName, LastName: lastName}
+				if ageInt != -1 {
+					filter.Age = &ageInt
+				}
 				if test != "failure negative age" {
-					mockService.On("GetAllPeople", ageInt, firstName, lastName).Return(testVars.serviceReturn, testVars.serviceErr)
+					mockService.On("GetAllPeople", mock.Anything, filter, services.ListOptions{Limit: defaultPageLimit}).Return(testVars.serviceReturn, services.PageInfo{Total: -1}, testVars.serviceErr)
 				}
 			}
 			handler.GetAllPeople(rr, req)
@@ -180,9 +186,10 @@ func TestGetAllPeople(t *testing.T) {
 func TestGetPerson(t *testing.T) {
 	testCases := map[string]struct {
 		name             string
+		queryID          string
 		queryFirstName   string
 		queryLastName    string
-		serviceReturn    models.Person
+		serviceReturn    []models.Person
 		serviceErr       error
 		expectedReturn   models.Person
 		expectedHTTPCode int
@@ -190,7 +197,7 @@ func TestGetPerson(t *testing.T) {
 		name:             "My Favoriteperson",
 		queryFirstName:   "My",
 		queryLastName:    "Favoriteperson",
-		serviceReturn:    models.Person{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}},
+		serviceReturn:    []models.Person{{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}}},
 		serviceErr:       nil,
 		expectedReturn:   models.Person{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}},
 		expectedHTTPCode: http.StatusOK,
@@ -198,15 +205,56 @@ func TestGetPerson(t *testing.T) {
 		name:             "my FaVoRiTePeRsOn",
 		queryFirstName:   "my",
 		queryLastName:    "FaVoRiTePeRsOn",
-		serviceReturn:    models.Person{ID: 25, Age: 27, FirstName: "My", LastName: "FavoritePerson", Type: "professor", Courses: []int{1}},
+		serviceReturn:    []models.Person{{ID: 25, Age: 27, FirstName: "My", LastName: "FavoritePerson", Type: "professor", Courses: []int{1}}},
 		serviceErr:       nil,
 		expectedReturn:   models.Person{ID: 25, Age: 27, FirstName: "My", LastName: "FavoritePerson", Type: "professor", Courses: []int{1}},
 		expectedHTTPCode: http.StatusOK,
+	}, "success disambiguated by id": {
+		name:           "My Favoriteperson",
+		queryID:        "26",
+		queryFirstName: "My",
+		queryLastName:  "Favoriteperson",
+		serviceReturn: []models.Person{
+			{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}},
+			{ID: 26, Age: 19, FirstName: "My", LastName: "Favoriteperson", Type: "student", Courses: []int{2}},
+		},
+		serviceErr:       nil,
+		expectedReturn:   models.Person{ID: 26, Age: 19, FirstName: "My", LastName: "Favoriteperson", Type: "student", Courses: []int{2}},
+		expectedHTTPCode: http.StatusOK,
+	}, "failure ambiguous name": {
+		name:           "My Favoriteperson",
+		queryFirstName: "My",
+		queryLastName:  "Favoriteperson",
+		serviceReturn: []models.Person{
+			{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}},
+			{ID: 26, Age: 19, FirstName: "My", LastName: "Favoriteperson", Type: "student", Courses: []int{2}},
+		},
+		serviceErr:       nil,
+		expectedReturn:   models.Person{},
+		expectedHTTPCode: http.StatusConflict,
+	}, "failure id does not match name": {
+		name:             "My Favoriteperson",
+		queryID:          "99",
+		queryFirstName:   "My",
+		queryLastName:    "Favoriteperson",
+		serviceReturn:    []models.Person{{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}}},
+		serviceErr:       nil,
+		expectedReturn:   models.Person{},
+		expectedHTTPCode: http.StatusNotFound,
+	}, "failure can't parse id": {
+		name:             "My Favoriteperson",
+		queryID:          "abc",
+		queryFirstName:   "My",
+		queryLastName:    "Favoriteperson",
+		serviceReturn:    nil,
+		serviceErr:       nil,
+		expectedReturn:   models.Person{},
+		expectedHTTPCode: http.StatusBadRequest,
 	}, "failure format one name": {
 		name:             "MYFAVORITEPERSON",
 		queryFirstName:   "",
 		queryLastName:    "",
-		serviceReturn:    models.Person{},
+		serviceReturn:    nil,
 		serviceErr:       nil,
 		expectedReturn:   models.Person{},
 		expectedHTTPCode: http.StatusBadRequest,
@@ -214,7 +262,7 @@ func TestGetPerson(t *testing.T) {
 		name:             "MY FAVORITE PERSON",
 		queryFirstName:   "",
 		queryLastName:    "",
-		serviceReturn:    models.Person{},
+		serviceReturn:    nil,
 		serviceErr:       nil,
 		expectedReturn:   models.Person{},
 		expectedHTTPCode: http.StatusBadRequest,
@@ -222,7 +270,7 @@ func TestGetPerson(t *testing.T) {
 		name:             "",
 		queryFirstName:   "",
 		queryLastName:    "",
-		serviceReturn:    models.Person{},
+		serviceReturn:    nil,
 		serviceErr:       nil,
 		expectedReturn:   models.Person{},
 		expectedHTTPCode: http.StatusBadRequest,
@@ -230,7 +278,7 @@ func TestGetPerson(t *testing.T) {
 		name:             "My Favoriteperson",
 		queryFirstName:   "My",
 		queryLastName:    "Favoriteperson",
-		serviceReturn:    models.Person{},
+		serviceReturn:    []models.Person{},
 		serviceErr:       nil,
 		expectedReturn:   models.Person{},
 		expectedHTTPCode: http.StatusNotFound,
@@ -238,7 +286,7 @@ func TestGetPerson(t *testing.T) {
 		name:             "My Favoriteperson",
 		queryFirstName:   "My",
 		queryLastName:    "Favoriteperson",
-		serviceReturn:    models.Person{},
+		serviceReturn:    []models.Person{},
 		serviceErr:       errors.New("not found!"),
 		expectedReturn:   models.Person{},
 		expectedHTTPCode: http.StatusInternalServerError,
@@ -250,6 +298,11 @@ func TestGetPerson(t *testing.T) {
 			rr := httptest.NewRecorder()
 			req, err := http.NewRequest("GET", "/api/person/"+testVars.name, nil)
 			assert.NoError(t, err)
+			if testVars.queryID != "" {
+				q := req.URL.Query()
+				q.Add("id", testVars.queryID)
+				req.URL.RawQuery = q.Encode()
+			}
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("name", testVars.name)
@@ -263,8 +316,9 @@ func TestGetPerson(t *testing.T) {
 				assert.Equal(t, testVars.queryFirstName, firstName)
 				assert.Equal(t, testVars.queryLastName, lastName)
 
-				mockService.On("GetPerson", firstName, lastName).Return(testVars.serviceReturn, testVars.serviceErr)
-
+				if testName != "failure can't parse id" {
+					mockService.On("GetAllPeople", mock.Anything, services.PersonFilter{FirstName: firstName, LastName: lastName}, services.ListOptions{}).Return(testVars.serviceReturn, services.PageInfo{Total: -1}, testVars.serviceErr)
+				}
 			} else {
 				assert.Error(t, err)
 			}
@@ -272,10 +326,16 @@ func TestGetPerson(t *testing.T) {
 			handler.GetPerson(rr, req)
 			var responsePerson models.Person
 
-			if testName != "failure format one name" && testName != "failure format three names" && testName != "failure missing name" && testName != "failure not found" && testName != "failure internal server error" {
+			if testVars.expectedHTTPCode == http.StatusOK {
 				err = json.NewDecoder(rr.Body).Decode(&responsePerson)
 				assert.NoError(t, err)
 			}
+			if testVars.expectedHTTPCode == http.StatusConflict {
+				var response ambiguousPersonProblem
+				err = json.NewDecoder(rr.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, []personCandidate{{ID: 25, Type: "professor", Age: 27}, {ID: 26, Type: "student", Age: 19}}, response.Candidates)
+			}
 
 			assert.Equal(t, testVars.expectedReturn, responsePerson)
 			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
@@ -287,7 +347,9 @@ func TestGetPerson(t *testing.T) {
 func TestUpdatePerson(t *testing.T) {
 	testCases := map[string]struct {
 		name             string
+		queryID          string
 		requestBody      models.Person
+		matches          []models.Person
 		serviceReturn    models.Person
 		serviceErr       error
 		expectedReturn   models.Person
@@ -295,6 +357,7 @@ func TestUpdatePerson(t *testing.T) {
 	}{"success": {
 		name:             "My Favoriteperson",
 		requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 6, 7}},
+		matches:          []models.Person{{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}}},
 		serviceReturn:    models.Person{ID: 25, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{5, 6, 7}},
 		serviceErr:       nil,
 		expectedReturn:   models.Person{ID: 25, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{5, 6, 7}},
@@ -302,6 +365,7 @@ func TestUpdatePerson(t *testing.T) {
 	}, "success uppercase": {
 		name:             "MY FAVORITEPERSON",
 		requestBody:      models.Person{ID: 28, Age: 28, FirstName: "MY", LastName: "NEWNAME", Type: "professor", Courses: []int{5, 6, 7}},
+		matches:          []models.Person{{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}}},
 		serviceReturn:    models.Person{ID: 25, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{5, 6, 7}},
 		serviceErr:       nil,
 		expectedReturn:   models.Person{ID: 25, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{5, 6, 7}},
@@ -309,10 +373,34 @@ func TestUpdatePerson(t *testing.T) {
 	}, "success lowercase": {
 		name:             "my favoriteperson",
 		requestBody:      models.Person{ID: 28, Age: 28, FirstName: "my", LastName: "newname", Type: "professor", Courses: []int{5, 6, 7}},
+		matches:          []models.Person{{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}}},
 		serviceReturn:    models.Person{ID: 25, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{5, 6, 7}},
 		serviceErr:       nil,
 		expectedReturn:   models.Person{ID: 25, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{5, 6, 7}},
 		expectedHTTPCode: http.StatusOK,
+	}, "success disambiguated by id": {
+		name:        "My Favoriteperson",
+		queryID:     "26",
+		requestBody: models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 6, 7}},
+		matches: []models.Person{
+			{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}},
+			{ID: 26, Age: 19, FirstName: "My", LastName: "Favoriteperson", Type: "student", Courses: []int{2}},
+		},
+		serviceReturn:    models.Person{ID: 26, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{5, 6, 7}},
+		serviceErr:       nil,
+		expectedReturn:   models.Person{ID: 26, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{5, 6, 7}},
+		expectedHTTPCode: http.StatusOK,
+	}, "failure ambiguous name": {
+		name:        "My Favoriteperson",
+		requestBody: models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 6, 7}},
+		matches: []models.Person{
+			{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}},
+			{ID: 26, Age: 19, FirstName: "My", LastName: "Favoriteperson", Type: "student", Courses: []int{2}},
+		},
+		serviceReturn:    models.Person{},
+		serviceErr:       nil,
+		expectedReturn:   models.Person{},
+		expectedHTTPCode: http.StatusConflict,
 	}, "failure missing name": {
 		name:             "",
 		requestBody:      models.Person{ID: 28, Age: 28, FirstName: "my", LastName: "newname", Type: "professor", Courses: []int{5, 6, 7}},
@@ -393,20 +481,23 @@ func TestUpdatePerson(t *testing.T) {
 	}, "failure person not found": {
 		name:             "My Favoriteperson",
 		requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{1, 2, 3}},
+		matches:          []models.Person{},
 		serviceReturn:    models.Person{},
-		serviceErr:       fmt.Errorf("person not found"),
+		serviceErr:       nil,
 		expectedReturn:   models.Person{},
 		expectedHTTPCode: http.StatusNotFound,
 	}, "failure course not found": {
 		name:             "My Favoriteperson",
 		requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{88888}},
+		matches:          []models.Person{{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}}},
 		serviceReturn:    models.Person{},
-		serviceErr:       fmt.Errorf("course not found, trying to join a course that doesn't exist"),
+		serviceErr:       services.ErrUnknownCourse,
 		expectedReturn:   models.Person{},
-		expectedHTTPCode: http.StatusNotFound,
+		expectedHTTPCode: http.StatusUnprocessableEntity,
 	}, "failure internal error": {
 		name:             "My Favoriteperson",
 		requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "Newname", Type: "professor", Courses: []int{88888}},
+		matches:          []models.Person{{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}}},
 		serviceReturn:    models.Person{},
 		serviceErr:       fmt.Errorf("new error!"),
 		expectedReturn:   models.Person{},
@@ -423,6 +514,11 @@ func TestUpdatePerson(t *testing.T) {
 			rr := httptest.NewRecorder()
 			req, err := http.NewRequest(http.MethodPut, "/api/person/"+testVars.name, buf)
 			assert.NoError(t, err)
+			if testVars.queryID != "" {
+				q := req.URL.Query()
+				q.Add("id", testVars.queryID)
+				req.URL.RawQuery = q.Encode()
+			}
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("name", testVars.name)
@@ -436,14 +532,21 @@ func TestUpdatePerson(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 			}
-			if testName == "success" || testName == "success uppercase" || testName == "success lowercase" || testName == "failure person not found" || testName == "failure course not found" || testName == "failure internal error" {
-				mockService.On("UpdatePerson", firstName, lastName, testVars.requestBody).Return(testVars.serviceReturn, testVars.serviceErr)
+			if testVars.matches != nil {
+				mockService.On("GetAllPeople", mock.Anything, services.PersonFilter{FirstName: firstName, LastName: lastName}, services.ListOptions{}).Return(testVars.matches, services.PageInfo{Total: -1}, nil)
+			}
+			if testName == "success" || testName == "success uppercase" || testName == "success lowercase" || testName == "success disambiguated by id" || testName == "failure course not found" || testName == "failure internal error" {
+				expectedID := testVars.matches[0].ID
+				if testVars.queryID != "" {
+					expectedID, _ = strconv.Atoi(testVars.queryID)
+				}
+				mockService.On("UpdatePersonByID", mock.Anything, expectedID, testVars.requestBody).Return(testVars.serviceReturn, testVars.serviceErr)
 			}
 
 			handler.UpdatePerson(rr, req)
 			var responsePerson models.Person
 
-			if testName == "success" || testName == "success uppercase" || testName == "success lowercase" {
+			if testVars.expectedHTTPCode == http.StatusOK {
 				err = json.NewDecoder(rr.Body).Decode(&responsePerson)
 				assert.NoError(t, err)
 			}
@@ -540,6 +643,18 @@ func TestCreatePerson(t *testing.T) {
 		serviceErr:       nil,
 		expectedReturn:   -1,
 		expectedHTTPCode: http.StatusBadRequest,
+	}, "failure unknown course": {
+		requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 4, 3, 2, 1}},
+		serviceReturn:    -1,
+		serviceErr:       fmt.Errorf("failed to update course list: %w", services.ErrUnknownCourse),
+		expectedReturn:   -1,
+		expectedHTTPCode: http.StatusUnprocessableEntity,
+	}, "failure constraint conflict": {
+		requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 4, 3, 2, 1}},
+		serviceReturn:    -1,
+		serviceErr:       &services.ConstraintError{Kind: services.ErrConflict, Err: &pq.Error{Code: "23505"}},
+		expectedReturn:   -1,
+		expectedHTTPCode: http.StatusConflict,
 	}, "failure internal error": {
 		requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 4, 3, 2, 1}},
 		serviceReturn:    -1,
@@ -561,8 +676,8 @@ func TestCreatePerson(t *testing.T) {
 			req, err := http.NewRequest(http.MethodPost, "/api/person/", buf)
 			assert.NoError(t, err)
 
-			if testName == "success" || testName == "failure internal error" {
-				mockService.On("CreatePerson", testVars.requestBody).Return(testVars.serviceReturn, testVars.serviceErr)
+			if testName == "success" || testName == "failure internal error" || testName == "failure unknown course" || testName == "failure constraint conflict" {
+				mockService.On("CreatePerson", mock.Anything, testVars.requestBody).Return(testVars.serviceReturn, testVars.serviceErr)
 			}
 			handler.CreatePerson(rr, req)
 
@@ -582,6 +697,8 @@ func TestCreatePerson(t *testing.T) {
 func TestDeletePerson(t *testing.T) {
 	testCases := map[string]struct {
 		name             string
+		queryID          string
+		matches          []models.Person
 		serviceReturn    int64
 		serviceErr       error
 		expectedReturn   string
@@ -589,11 +706,35 @@ func TestDeletePerson(t *testing.T) {
 	}{
 		"success": {
 			name:             "My Testperson",
+			matches:          []models.Person{{ID: 7, FirstName: "My", LastName: "Testperson", Type: "student", Age: 20, Courses: []int{}}},
+			serviceReturn:    1,
+			serviceErr:       nil,
+			expectedReturn:   "person successfully deleted",
+			expectedHTTPCode: http.StatusOK,
+		},
+		"success disambiguated by id": {
+			name:    "My Testperson",
+			queryID: "8",
+			matches: []models.Person{
+				{ID: 7, FirstName: "My", LastName: "Testperson", Type: "student", Age: 20, Courses: []int{}},
+				{ID: 8, FirstName: "My", LastName: "Testperson", Type: "professor", Age: 50, Courses: []int{}},
+			},
 			serviceReturn:    1,
 			serviceErr:       nil,
 			expectedReturn:   "person successfully deleted",
 			expectedHTTPCode: http.StatusOK,
 		},
+		"failure ambiguous name": {
+			name: "My Testperson",
+			matches: []models.Person{
+				{ID: 7, FirstName: "My", LastName: "Testperson", Type: "student", Age: 20, Courses: []int{}},
+				{ID: 8, FirstName: "My", LastName: "Testperson", Type: "professor", Age: 50, Courses: []int{}},
+			},
+			serviceReturn:    -1,
+			serviceErr:       nil,
+			expectedReturn:   "",
+			expectedHTTPCode: http.StatusConflict,
+		},
 		"failure missing name": {
 			name:             "",
 			serviceReturn:    -1,
@@ -617,6 +758,7 @@ func TestDeletePerson(t *testing.T) {
 		},
 		"failure not found 1": {
 			name:             "Johnny Bullet",
+			matches:          []models.Person{},
 			serviceReturn:    0,
 			serviceErr:       nil,
 			expectedReturn:   "",
@@ -624,13 +766,15 @@ func TestDeletePerson(t *testing.T) {
 		},
 		"failure not found 2": {
 			name:             "Johnny Bullet",
-			serviceReturn:    -1,
-			serviceErr:       fmt.Errorf("person not found"),
+			matches:          []models.Person{{ID: 7, FirstName: "Johnny", LastName: "Bullet", Type: "student", Age: 20, Courses: []int{}}},
+			serviceReturn:    0,
+			serviceErr:       nil,
 			expectedReturn:   "",
 			expectedHTTPCode: http.StatusNotFound,
 		},
 		"failure internal error": {
 			name:             "Johnny Bullet",
+			matches:          []models.Person{{ID: 7, FirstName: "Johnny", LastName: "Bullet", Type: "student", Age: 20, Courses: []int{}}},
 			serviceReturn:    -1,
 			serviceErr:       fmt.Errorf("new error!"),
 			expectedReturn:   "",
@@ -642,6 +786,11 @@ func TestDeletePerson(t *testing.T) {
 		t.Run(test, func(t *testing.T) {
 			req, err := http.NewRequest(http.MethodDelete, "/api/person/"+testVars.name, nil)
 			assert.NoError(t, err)
+			if testVars.queryID != "" {
+				q := req.URL.Query()
+				q.Add("id", testVars.queryID)
+				req.URL.RawQuery = q.Encode()
+			}
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("name", testVars.name)
@@ -654,17 +803,24 @@ func TestDeletePerson(t *testing.T) {
 			rr := httptest.NewRecorder()
 
 			firstName, lastName, err := formatName(testVars.name)
-			if test == "success" || test == "failure not found 1" || test == "failure not found 2" || test == "failure internal error" {
+			if testVars.matches != nil {
 				assert.NoError(t, err)
-				mockService.On("DeletePerson", firstName, lastName).Return(testVars.serviceReturn, testVars.serviceErr)
+				mockService.On("GetAllPeople", mock.Anything, services.PersonFilter{FirstName: firstName, LastName: lastName}, services.ListOptions{}).Return(testVars.matches, services.PageInfo{Total: -1}, nil)
 			} else {
 				assert.Error(t, err)
 			}
+			if test == "success" || test == "success disambiguated by id" || test == "failure not found 2" || test == "failure internal error" {
+				expectedID := testVars.matches[0].ID
+				if testVars.queryID != "" {
+					expectedID, _ = strconv.Atoi(testVars.queryID)
+				}
+				mockService.On("DeletePersonByID", mock.Anything, expectedID).Return(testVars.serviceReturn, testVars.serviceErr)
+			}
 			handler.DeletePerson(rr, req)
 
 			var responseCourses string
 			err = json.NewDecoder(rr.Body).Decode(&responseCourses)
-			if test == "success" {
+			if testVars.expectedHTTPCode == http.StatusOK {
 				assert.NoError(t, err)
 			} else {
 				assert.Error(t, err)
@@ -676,3 +832,272 @@ func TestDeletePerson(t *testing.T) {
 		})
 	}
 }
+func TestGetPersonByID(t *testing.T) {
+	testCases := map[string]struct {
+		id               string
+		serviceReturn    models.Person
+		serviceErr       error
+		expectedReturn   models.Person
+		expectedHTTPCode int
+	}{
+		"success": {
+			id:               "25",
+			serviceReturn:    models.Person{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}},
+			serviceErr:       nil,
+			expectedReturn:   models.Person{ID: 25, Age: 27, FirstName: "My", LastName: "Favoriteperson", Type: "professor", Courses: []int{1}},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"can't parse": {
+			id:               "My Favoriteperson",
+			serviceReturn:    models.Person{},
+			serviceErr:       nil,
+			expectedReturn:   models.Person{},
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"person not found": {
+			id:               "25",
+			serviceReturn:    models.Person{},
+			serviceErr:       nil,
+			expectedReturn:   models.Person{},
+			expectedHTTPCode: http.StatusNotFound,
+		},
+		"internal error": {
+			id:               "25",
+			serviceReturn:    models.Person{},
+			serviceErr:       errors.New("can't get person!"),
+			expectedReturn:   models.Person{},
+			expectedHTTPCode: http.StatusInternalServerError,
+		},
+	}
+
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/api/person/id/"+testVars.id, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testVars.id)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			mockService := new(services.MockPersonService)
+			handler := &PersonHandler{PersonService: mockService}
+
+			rr := httptest.NewRecorder()
+
+			intId, _ := strconv.Atoi(testVars.id)
+			if test != "can't parse" {
+				mockService.On("GetPersonByID", mock.Anything, intId).Return(testVars.serviceReturn, testVars.serviceErr)
+			}
+			handler.GetPersonByID(rr, req)
+
+			var responsePerson models.Person
+			if testVars.expectedHTTPCode == http.StatusOK {
+				json.NewDecoder(rr.Body).Decode(&responsePerson)
+			}
+			assert.Equal(t, testVars.expectedReturn, responsePerson)
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+func TestUpdatePersonByID(t *testing.T) {
+	testCases := map[string]struct {
+		id               string
+		requestBody      models.Person
+		serviceReturn    models.Person
+		serviceErr       error
+		expectedReturn   models.Person
+		expectedHTTPCode int
+	}{
+		"success": {
+			id:               "25",
+			requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 6, 7}},
+			serviceReturn:    models.Person{ID: 25, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 6, 7}},
+			serviceErr:       nil,
+			expectedReturn:   models.Person{ID: 25, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 6, 7}},
+			expectedHTTPCode: http.StatusOK,
+		},
+		"can't parse": {
+			id:               "abcd",
+			requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 6, 7}},
+			serviceReturn:    models.Person{},
+			serviceErr:       nil,
+			expectedReturn:   models.Person{},
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"bad validation": {
+			id:               "25",
+			requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "madman", Courses: []int{5, 6, 7}},
+			serviceReturn:    models.Person{},
+			serviceErr:       nil,
+			expectedReturn:   models.Person{},
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"duplicate courses": {
+			id:               "25",
+			requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 5}},
+			serviceReturn:    models.Person{},
+			serviceErr:       nil,
+			expectedReturn:   models.Person{},
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"person not found": {
+			id:               "25",
+			requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 6, 7}},
+			serviceReturn:    models.Person{},
+			serviceErr:       services.ErrPersonNotFound,
+			expectedReturn:   models.Person{},
+			expectedHTTPCode: http.StatusNotFound,
+		},
+		"internal error": {
+			id:               "25",
+			requestBody:      models.Person{ID: 28, Age: 28, FirstName: "My", LastName: "NewName", Type: "professor", Courses: []int{5, 6, 7}},
+			serviceReturn:    models.Person{},
+			serviceErr:       errors.New("couldn't update!"),
+			expectedReturn:   models.Person{},
+			expectedHTTPCode: http.StatusInternalServerError,
+		},
+	}
+
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(testVars.requestBody)
+			assert.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPut, "/api/person/id/"+testVars.id, buf)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testVars.id)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			mockService := new(services.MockPersonService)
+			handler := &PersonHandler{PersonService: mockService}
+
+			rr := httptest.NewRecorder()
+
+			intId, _ := strconv.Atoi(testVars.id)
+			if test != "can't parse" && test != "bad validation" && test != "duplicate courses" {
+				mockService.On("UpdatePersonByID", mock.Anything, intId, testVars.requestBody).Return(testVars.serviceReturn, testVars.serviceErr)
+			}
+			handler.UpdatePersonByID(rr, req)
+
+			var responsePerson models.Person
+			if testVars.expectedHTTPCode == http.StatusOK {
+				json.NewDecoder(rr.Body).Decode(&responsePerson)
+			}
+			assert.Equal(t, testVars.expectedReturn, responsePerson)
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+func TestDeletePersonByID(t *testing.T) {
+	testCases := map[string]struct {
+		id               string
+		serviceReturn    int64
+		serviceErr       error
+		expectedReturn   string
+		expectedHTTPCode int
+	}{
+		"success": {
+			id:               "22",
+			serviceReturn:    1,
+			serviceErr:       nil,
+			expectedReturn:   "person successfully deleted",
+			expectedHTTPCode: http.StatusOK,
+		},
+		"can't parse": {
+			id:               "Johnny Bullet",
+			serviceReturn:    0,
+			serviceErr:       nil,
+			expectedReturn:   "",
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		"internal error": {
+			id:               "4",
+			serviceReturn:    -1,
+			serviceErr:       errors.New("can't delete!"),
+			expectedReturn:   "",
+			expectedHTTPCode: http.StatusInternalServerError,
+		},
+		"person not found": {
+			id:               "4",
+			serviceReturn:    0,
+			serviceErr:       nil,
+			expectedReturn:   "",
+			expectedHTTPCode: http.StatusNotFound,
+		},
+	}
+
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodDelete, "/api/person/id/"+testVars.id, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testVars.id)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			mockService := new(services.MockPersonService)
+			handler := &PersonHandler{PersonService: mockService}
+
+			rr := httptest.NewRecorder()
+
+			intId, _ := strconv.Atoi(testVars.id)
+			if test != "can't parse" {
+				mockService.On("DeletePersonByID", mock.Anything, intId).Return(testVars.serviceReturn, testVars.serviceErr)
+			}
+			handler.DeletePersonByID(rr, req)
+
+			var responseBody string
+			json.NewDecoder(rr.Body).Decode(&responseBody)
+			assert.Equal(t, testVars.expectedReturn, responseBody)
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+func TestParsePersonFilter(t *testing.T) {
+	age, minAge, maxAge := 30, 18, 25
+	testCases := map[string]struct {
+		query          string
+		expectedReturn services.PersonFilter
+		expectErr      bool
+	}{
+		"success none":      {query: "", expectedReturn: services.PersonFilter{}},
+		"success full name": {query: "name=Juniper+Scott&age=30", expectedReturn: services.PersonFilter{FirstName: "Juniper", LastName: "Scott", Age: &age}},
+		"success last name prefix": {
+			query:          "last_name=Sc&name_match=prefix",
+			expectedReturn: services.PersonFilter{LastName: "Sc", NameMatch: services.NamePrefix},
+		},
+		"success type, age range and courses": {
+			query:          "type=student&age_gte=18&age_lte=25&course_id=1,2&course_id=5",
+			expectedReturn: services.PersonFilter{Type: "student", MinAge: &minAge, MaxAge: &maxAge, CourseIDs: []int{1, 2, 5}},
+		},
+		"failure name and first_name": {query: "name=Juniper+Scott&first_name=June", expectErr: true},
+		"failure name_match":          {query: "first_name=June&name_match=fuzzy", expectErr: true},
+		"failure type":                {query: "type=dean", expectErr: true},
+		"failure age_gte parse":       {query: "age_gte=old", expectErr: true},
+		"failure negative age_lte":    {query: "age_lte=-1", expectErr: true},
+		"failure course_id parse":     {query: "course_id=1,two", expectErr: true},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/person?"+testVars.query, nil)
+			filter, err := parsePersonFilter(req.URL.Query())
+			if testVars.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testVars.expectedReturn, filter)
+		})
+	}
+}
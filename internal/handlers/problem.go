@@ -0,0 +1,90 @@
+package handlers
+
+//problem.go defines the application/problem+json (RFC 7807) body that every handler responds with when a request fails.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"tech-challenge/internal/services"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. RequestID and Errors are extension members.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes why one field of a request body was rejected. Field is the field's JSON name, Rule the
+// validate tag it failed and Param that rule's parameter, if any.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// newProblem builds the Problem for a failed request with the given status. detail is shown to the client.
+func newProblem(r *http.Request, status int, detail string) Problem {
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: requestIDFromContext(r.Context()),
+	}
+}
+
+// writeProblem logs and responds with a Problem. detail is shown to the client, so it must not contain internal details.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	logError(r, detail, status)
+	encodeProblem(w, status, newProblem(r, status, detail))
+}
+
+// writeValidationProblem logs and responds with a 400 Problem listing every field that failed validation.
+func writeValidationProblem(w http.ResponseWriter, r *http.Request, detail string, fieldErrs []FieldError) {
+	messages := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		messages = append(messages, fieldErr.Message)
+	}
+	logError(r, detail+": "+strings.Join(messages, "; "), http.StatusBadRequest)
+
+	problem := newProblem(r, http.StatusBadRequest, detail)
+	problem.Errors = fieldErrs
+	encodeProblem(w, http.StatusBadRequest, problem)
+}
+
+// writeServiceError logs and responds to an error returned by ../services. message says what the handler was doing.
+// The full error is only logged; the client sees message plus the error's services.ClientMessage, if it has one.
+func writeServiceError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	status := statusFromError(err)
+	// Postgres reports a query cancelled at the request's deadline as its own error rather than the context's.
+	if status == http.StatusInternalServerError && errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+		status = http.StatusGatewayTimeout
+	}
+	logError(r, message+": "+err.Error(), status)
+
+	detail := message
+	if clientMessage, ok := services.ClientMessage(err); ok {
+		detail += ": " + clientMessage
+	}
+	encodeProblem(w, status, newProblem(r, status, detail))
+}
+
+// encodeProblem writes body, a Problem or a struct embedding one, as application/problem+json.
+func encodeProblem(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+//problem_test.go tests ./problem.go utilizing table based testing best practices.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"tech-challenge/internal/services"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteProblem(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "/api/course/abc", nil)
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+
+	// run the request through the request id middleware so the problem can pick the id up
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, r, http.StatusBadRequest, "bad request: cannot parse id to int")
+	}))
+	handler.ServeHTTP(rr, req)
+
+	var problem Problem
+	err = json.NewDecoder(rr.Body).Decode(&problem)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, problemContentType, rr.Header().Get("Content-Type"))
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, "Bad Request", problem.Title)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "bad request: cannot parse id to int", problem.Detail)
+	assert.Equal(t, "/api/course/abc", problem.Instance)
+	assert.NotEmpty(t, problem.RequestID)
+	assert.Equal(t, rr.Header().Get(RequestIDHeader), problem.RequestID)
+}
+func TestWriteServiceError(t *testing.T) {
+	testCases := map[string]struct {
+		err              error
+		requestExpired   bool
+		expectedHTTPCode int
+		expectedDetail   string
+	}{
+		"not found": {
+			err:              services.ErrPersonNotFound,
+			expectedHTTPCode: http.StatusNotFound,
+			expectedDetail:   "could not update person: person not found",
+		},
+		"wrapped invalid reference": {
+			err:              fmt.Errorf("failed to update course list: %w", services.ErrUnknownCourse),
+			expectedHTTPCode: http.StatusUnprocessableEntity,
+			expectedDetail:   "could not update person: course not found, trying to join a course that doesn't exist",
+		},
+		"constraint violation hides database error": {
+			err: &services.ConstraintError{Kind: services.ErrConflict, Err: &pq.Error{Code: "23505",
+				Message: `duplicate key value violates unique constraint "person_course_pkey"`}},
+			expectedHTTPCode: http.StatusConflict,
+			expectedDetail:   "could not update person: conflict",
+		},
+		"internal error hides database error": {
+			err:              fmt.Errorf("failed to update person: %w", errors.New(`pq: relation "person" does not exist`)),
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedDetail:   "could not update person",
+		},
+		"deadline exceeded": {
+			err:              fmt.Errorf("failed to update person: %w", context.DeadlineExceeded),
+			expectedHTTPCode: http.StatusGatewayTimeout,
+			expectedDetail:   "could not update person",
+		},
+		"query cancelled at request deadline": {
+			err:              fmt.Errorf("failed to update person: %w", &pq.Error{Code: "57014", Message: "canceling statement due to user request"}),
+			requestExpired:   true,
+			expectedHTTPCode: http.StatusGatewayTimeout,
+			expectedDetail:   "could not update person",
+		},
+	}
+	for testName, testVars := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, "/api/person/id/1", nil)
+			assert.NoError(t, err)
+			if testVars.requestExpired {
+				ctx, cancel := context.WithTimeout(req.Context(), -time.Second)
+				defer cancel()
+				req = req.WithContext(ctx)
+			}
+			rr := httptest.NewRecorder()
+
+			writeServiceError(rr, req, "could not update person", testVars.err)
+
+			var problem Problem
+			err = json.NewDecoder(rr.Body).Decode(&problem)
+			assert.NoError(t, err)
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			assert.Equal(t, testVars.expectedHTTPCode, problem.Status)
+			assert.Equal(t, testVars.expectedDetail, problem.Detail)
+			assert.Equal(t, problemContentType, rr.Header().Get("Content-Type"))
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+//ratelimit.go defines the middleware that limits how many requests each client may make, see ../ratelimit.
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/ratelimit"
+	"time"
+)
+
+// routeLimiter is a ratelimit.Rule and the buckets enforcing it, nil if its limit is off.
+type routeLimiter struct {
+	rule    ratelimit.Rule
+	limiter *ratelimit.Limiter
+}
+
+// RateLimit returns middleware that limits the requests of every client to the limit of the first of rules matching
+// the request's method and path, or to defaultLimit if none does. A trailing slash is ignored, as the router ignores
+// it, so "/api/person/" matches a rule for "/api/person". Each rule counts the requests of a client separately. Clients are told their limit by RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers, and
+// requests over it fail with a 429 and a Retry-After header.
+//
+// A client is the API key or token subject stored in the context by Authenticate, which must run first to limit
+// callers by who they are, or else the IP address the request came from. See RateLimitIP for the requests that
+// Authenticate rejects.
+func RateLimit(defaultLimit ratelimit.Limit, rules []ratelimit.Rule) func(http.Handler) http.Handler {
+	limiters := make([]routeLimiter, 0, len(rules)+1)
+	for _, rule := range slices.Concat(rules, []ratelimit.Rule{{Path: "*", Limit: defaultLimit}}) {
+		rule.Path = trimTrailingSlash(rule.Path)
+		limiter := routeLimiter{rule: rule}
+		if !rule.Limit.Off() {
+			limiter.limiter = ratelimit.NewLimiter(rule.Limit)
+		}
+		limiters = append(limiters, limiter)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var limiter *ratelimit.Limiter
+			path := trimTrailingSlash(r.URL.Path)
+			for _, route := range limiters {
+				if (route.rule.Method == "" || route.rule.Method == r.Method) && matchesPath(path, []string{route.rule.Path}) {
+					limiter = route.limiter
+					break
+				}
+			}
+			if limiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if takeRequest(w, r, limiter, rateLimitClient(r)) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// trimTrailingSlash returns path without its trailing slash, unless it is "/".
+func trimTrailingSlash(path string) string {
+	if len(path) > 1 {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// RateLimitIP returns middleware that limits the requests from every IP address to limit, whoever makes them. It runs
+// before Authenticate, so requests with bad credentials are limited too and guessing them is slow. limit should be
+// higher than the limits of RateLimit, since several callers can share an address. The zero Limit disables it.
+func RateLimitIP(limit ratelimit.Limit) func(http.Handler) http.Handler {
+	if limit.Off() {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	limiter := ratelimit.NewLimiter(limit)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if takeRequest(w, r, limiter, clientIP(r)) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// takeRequest takes a request of client from limiter and sets the RateLimit headers of the response. It returns
+// whether the request is allowed, or responds with a 429 and returns false.
+func takeRequest(w http.ResponseWriter, r *http.Request, limiter *ratelimit.Limiter, client string) bool {
+	decision := limiter.Take(client)
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	w.Header().Set("RateLimit-Reset", seconds(decision.Reset))
+	if !decision.Allowed {
+		w.Header().Set("Retry-After", seconds(decision.RetryAfter))
+		writeProblem(w, r, http.StatusTooManyRequests, fmt.Sprintf("rate limit exceeded, retry in %s seconds", seconds(decision.RetryAfter)))
+		return false
+	}
+	return true
+}
+
+// rateLimitClient returns who made r, as described by RateLimit.
+func rateLimitClient(r *http.Request) string {
+	if identity, ok := auth.FromContext(r.Context()); ok {
+		if identity.Scopes != nil {
+			// API key subjects already say so, see verifyAPIKey
+			return identity.Subject
+		}
+		return "subject:" + identity.Subject
+	}
+	return clientIP(r)
+}
+
+// clientIP returns the IP address r came from as a rate limit client.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// seconds formats d as whole seconds, rounded up so clients that wait that long are not turned away again.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,190 @@
+package handlers
+
+//ratelimit_test.go tests ./ratelimit.go utilizing table based testing best practices.
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	twoPerHour := ratelimit.Limit{Requests: 2, Per: time.Hour}
+	onePerHour := ratelimit.Limit{Requests: 1, Per: time.Hour}
+	type request struct {
+		method     string
+		path       string
+		remoteAddr string
+		identity   *auth.Identity
+	}
+	testCases := map[string]struct {
+		rules             []ratelimit.Rule
+		requests          []request
+		expectedHTTPCodes []int
+		expectedLimit     string
+		expectedRemaining string
+	}{
+		"default limit per client": {
+			requests: []request{
+				{path: "/api/course", remoteAddr: "10.0.0.1:5000"},
+				{path: "/api/person", remoteAddr: "10.0.0.1:5001"},
+				{path: "/api/course", remoteAddr: "10.0.0.2:5000"},
+				{path: "/api/course", remoteAddr: "10.0.0.1:5002"},
+			},
+			expectedHTTPCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			expectedLimit:     "2",
+			expectedRemaining: "0",
+		},
+		"route rule counts separately": {
+			rules: []ratelimit.Rule{{Method: http.MethodGet, Path: "/api/person", Limit: onePerHour}},
+			requests: []request{
+				{path: "/api/person", remoteAddr: "10.0.0.1:5000"},
+				{method: http.MethodPost, path: "/api/person", remoteAddr: "10.0.0.1:5000"},
+				{path: "/api/course", remoteAddr: "10.0.0.1:5000"},
+				{path: "/api/person", remoteAddr: "10.0.0.1:5000"},
+			},
+			expectedHTTPCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			expectedLimit:     "1",
+			expectedRemaining: "0",
+		},
+		"route rule ignores a trailing slash": {
+			rules: []ratelimit.Rule{{Method: http.MethodGet, Path: "/api/person", Limit: onePerHour}},
+			requests: []request{
+				{path: "/api/person", remoteAddr: "10.0.0.1:5000"},
+				{path: "/api/person/", remoteAddr: "10.0.0.1:5000"},
+			},
+			expectedHTTPCodes: []int{http.StatusOK, http.StatusTooManyRequests},
+			expectedLimit:     "1",
+			expectedRemaining: "0",
+		},
+		"rule with a trailing slash": {
+			rules: []ratelimit.Rule{{Path: "/api/course/", Limit: onePerHour}},
+			requests: []request{
+				{path: "/api/course/", remoteAddr: "10.0.0.1:5000"},
+				{path: "/api/course", remoteAddr: "10.0.0.1:5000"},
+			},
+			expectedHTTPCodes: []int{http.StatusOK, http.StatusTooManyRequests},
+			expectedLimit:     "1",
+			expectedRemaining: "0",
+		},
+		"first matching rule wins": {
+			rules: []ratelimit.Rule{
+				{Path: "/api/course/*", Limit: ratelimit.Limit{}},
+				{Path: "/api/*", Limit: onePerHour},
+			},
+			requests: []request{
+				{path: "/api/course/1", remoteAddr: "10.0.0.1:5000"},
+				{path: "/api/course/1", remoteAddr: "10.0.0.1:5000"},
+				{path: "/api/course/1", remoteAddr: "10.0.0.1:5000"},
+			},
+			expectedHTTPCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		"authenticated callers are limited by subject": {
+			requests: []request{
+				{path: "/api/course", remoteAddr: "10.0.0.1:5000", identity: &auth.Identity{Subject: "alice"}},
+				{path: "/api/course", remoteAddr: "10.0.0.2:5000", identity: &auth.Identity{Subject: "alice"}},
+				{path: "/api/course", remoteAddr: "10.0.0.1:5000", identity: &auth.Identity{Subject: "bob"}},
+				{path: "/api/course", remoteAddr: "10.0.0.1:5000"},
+				{path: "/api/course", remoteAddr: "10.0.0.3:5000", identity: &auth.Identity{Subject: "alice"}},
+			},
+			expectedHTTPCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			expectedLimit:     "2",
+			expectedRemaining: "0",
+		},
+		"API keys are limited by key": {
+			requests: []request{
+				{path: "/api/course", identity: &auth.Identity{Subject: "apikey:tc_1", Scopes: []string{}}},
+				{path: "/api/course", identity: &auth.Identity{Subject: "apikey:tc_2", Scopes: []string{}}},
+				{path: "/api/course", identity: &auth.Identity{Subject: "apikey:tc_1", Scopes: []string{}}},
+			},
+			expectedHTTPCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+			expectedLimit:     "2",
+			expectedRemaining: "0",
+		},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			handler := RateLimit(twoPerHour, testVars.rules)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			var rr *httptest.ResponseRecorder
+			for i, request := range testVars.requests {
+				method := request.method
+				if method == "" {
+					method = http.MethodGet
+				}
+				req := httptest.NewRequest(method, request.path, nil)
+				req.RemoteAddr = request.remoteAddr
+				if request.identity != nil {
+					req = req.WithContext(auth.WithIdentity(req.Context(), *request.identity))
+				}
+				rr = httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+				assert.Equal(t, testVars.expectedHTTPCodes[i], rr.Code, "request %d", i+1)
+			}
+
+			assert.Equal(t, testVars.expectedLimit, rr.Header().Get("RateLimit-Limit"))
+			assert.Equal(t, testVars.expectedRemaining, rr.Header().Get("RateLimit-Remaining"))
+			if rr.Code == http.StatusTooManyRequests {
+				assert.NotEmpty(t, rr.Header().Get("RateLimit-Reset"))
+				assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+				var problem Problem
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&problem))
+				assert.Equal(t, http.StatusTooManyRequests, problem.Status)
+			} else {
+				assert.Empty(t, rr.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	handler := RateLimit(ratelimit.Limit{Requests: 60, Per: time.Hour}, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/api/course", nil)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, "60", rr.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "59", rr.Header().Get("RateLimit-Remaining"))
+	// one request takes a minute to refill
+	assert.Equal(t, "60", rr.Header().Get("RateLimit-Reset"))
+
+	for i := 0; i < 59; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "3600", rr.Header().Get("RateLimit-Reset"))
+}
+
+func TestRateLimitIP(t *testing.T) {
+	// bad credentials are rejected by Authenticate after the address was limited, so guessing them is limited too
+	handler := RateLimitIP(ratelimit.Limit{Requests: 2, Per: time.Hour})(
+		Authenticate(stubVerifier{err: auth.ErrInvalidToken}, nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	codes := make([]int, 0, 4)
+	for _, remoteAddr := range []string{"10.0.0.1:5000", "10.0.0.1:5001", "10.0.0.1:5002", "10.0.0.2:5000"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/course", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", "Bearer guess")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		codes = append(codes, rr.Code)
+	}
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusUnauthorized}, codes)
+
+	// the zero Limit disables it
+	handler = RateLimitIP(ratelimit.Limit{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/course", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+}
@@ -0,0 +1,94 @@
+package handlers
+
+//requestlog.go defines the middleware that gives every request an ID and a logger, see ../logging, and logs every
+//request once it has been served.
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"tech-challenge/internal/logging"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+)
+
+// RequestIDHeader carries the ID of a request, both from clients or proxies that already assigned one, and back to
+// the client in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the longest inbound request ID kept, so clients cannot bloat every log line of a request.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestID is middleware that stores the ID of every request in its context and sets it as the RequestIDHeader of
+// the response. The ID is the inbound RequestIDHeader, so a request can be followed from the proxy or client that sent
+// it, unless that is missing or malformed, in which case a random one is generated.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !isValidRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// requestIDFromContext returns the ID stored in ctx by RequestID, or "" if there is none.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// isValidRequestID returns whether id is short and made only of characters that are safe to log and echo back.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/', c == '+', c == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 128-bit hex ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// RequestLogger returns middleware that stores logger, with the request's ID from RequestID attached, in the context
+// of every request for the handlers and services serving it, see logging.FromContext. Once served, the request is
+// logged with its status, size and duration.
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestLogger := logger.With(slog.String("request_id", requestIDFromContext(r.Context())))
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			start := time.Now()
+			next.ServeHTTP(ww, r.WithContext(logging.WithLogger(r.Context(), requestLogger)))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			requestLogger.LogAttrs(r.Context(), slog.LevelInfo, "request served",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote_addr", r.RemoteAddr))
+		})
+	}
+}
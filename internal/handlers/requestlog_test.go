@@ -0,0 +1,89 @@
+package handlers
+
+//requestlog_test.go tests ./requestlog.go utilizing table based testing best practices.
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"tech-challenge/internal/logging"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	testCases := map[string]struct {
+		inbound       string
+		expectsReused bool
+	}{
+		"generated when missing": {inbound: ""},
+		"inbound reused":         {inbound: "lb-7f3a:1234", expectsReused: true},
+		"uuid reused":            {inbound: "0b4c8a8e-2f7d-4f7e-9c65-0d8f4b2e8c11", expectsReused: true},
+		"unsafe replaced":        {inbound: "abc\ninjected=true"},
+		"too long replaced":      {inbound: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/course", nil)
+			if testVars.inbound != "" {
+				req.Header.Set(RequestIDHeader, testVars.inbound)
+			}
+			var id string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				id = requestIDFromContext(r.Context())
+			}))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.NotEmpty(t, id)
+			assert.Equal(t, id, rr.Header().Get(RequestIDHeader))
+			if testVars.expectsReused {
+				assert.Equal(t, testVars.inbound, id)
+			} else {
+				assert.NotEqual(t, testVars.inbound, id)
+				assert.Len(t, id, 32)
+			}
+		})
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := logging.New(&out, logging.FormatJSON, "info")
+	require.NoError(t, err)
+
+	// the handler logs a failure with the request's logger, as handlers and services do
+	handler := RequestID(RequestLogger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("querying")
+		writeProblem(w, r, http.StatusNotFound, "course not found")
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/api/course/7", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var records []map[string]any
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var record map[string]any
+		require.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 3)
+	for _, record := range records {
+		assert.Equal(t, "req-1", record["request_id"])
+	}
+	assert.Equal(t, "querying", records[0]["msg"])
+	assert.Equal(t, slog.LevelWarn.String(), records[1]["level"])
+	assert.Equal(t, "course not found", records[1]["msg"])
+	assert.Equal(t, "request served", records[2]["msg"])
+	assert.Equal(t, http.MethodGet, records[2]["method"])
+	assert.Equal(t, "/api/course/7", records[2]["path"])
+	assert.Equal(t, float64(http.StatusNotFound), records[2]["status"])
+	assert.Greater(t, records[2]["bytes"], float64(0))
+}
@@ -0,0 +1,26 @@
+package handlers
+
+//timeout.go defines the middleware that bounds how long a request can spend querying the database.
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DBTimeout returns middleware that gives every request's context a deadline of timeout. Handlers pass that context to
+// ../services, so queries still running at the deadline are cancelled and the request fails with a 504.
+// A timeout of 0 or less disables the deadline.
+func DBTimeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
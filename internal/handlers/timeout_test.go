@@ -0,0 +1,41 @@
+package handlers
+
+//timeout_test.go tests ./timeout.go utilizing table based testing best practices.
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		timeout         time.Duration
+		expectsDeadline bool
+	}{
+		"deadline set":      {timeout: time.Minute, expectsDeadline: true},
+		"disabled for zero": {timeout: 0, expectsDeadline: false},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/api/person", nil)
+			assert.NoError(t, err)
+
+			var deadline time.Time
+			var hasDeadline bool
+			handler := DBTimeout(testVars.timeout)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				deadline, hasDeadline = r.Context().Deadline()
+			}))
+			start := time.Now()
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, testVars.expectsDeadline, hasDeadline)
+			if testVars.expectsDeadline {
+				assert.WithinDuration(t, start.Add(testVars.timeout), deadline, time.Second)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+//validation.go validates ../models objects sent in request bodies and translates failures into FieldErrors for the client.
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/models"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate checks ../models structs against their validate tags. It is safe for concurrent use.
+var validate = newValidator()
+
+// newValidator returns a validator that knows the custom ValidateType and scope rules and reports fields by their JSON
+// names.
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterValidation("ValidateType", ValidateType)
+	v.RegisterValidation("scope", isScope)
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// isScope is the scope rule, which requires one of auth.Scopes.
+func isScope(fl validator.FieldLevel) bool {
+	return slices.Contains(auth.Scopes, fl.Field().String())
+}
+
+// validatePerson returns every reason person cannot be stored, or nil if it is valid.
+func validatePerson(person models.Person) []FieldError {
+	fieldErrs := fieldErrors(validate.Struct(person))
+	if !areUnique(person.Courses) {
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   "courses",
+			Rule:    "unique",
+			Message: "courses must not contain the same course id twice",
+		})
+	}
+	return fieldErrs
+}
+
+// validateAPIKey returns every reason the name and scopes of key cannot be stored, or nil if they are valid.
+func validateAPIKey(key models.APIKey) []FieldError {
+	return fieldErrors(validate.Struct(key))
+}
+
+// validateCourse returns every reason course cannot be stored, or nil if it is valid.
+func validateCourse(course models.Course) []FieldError {
+	return fieldErrors(validate.Struct(course))
+}
+
+// fieldErrors translates the validator.ValidationErrors inside err into FieldErrors. Any other error yields nil.
+func fieldErrors(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+	fieldErrs := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+	return fieldErrs
+}
+
+// fieldErrorMessage describes a single failed rule in plain words.
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fe.Field() + " is required"
+	case "gt":
+		return fe.Field() + " must be greater than " + fe.Param()
+	case "ValidateType":
+		return fe.Field() + ` must be either "professor" or "student"`
+	case "min":
+		return fe.Field() + " must have at least " + fe.Param() + " item(s)"
+	case "unique":
+		return fe.Field() + " must not contain the same value twice"
+	case "scope":
+		return fe.Field() + " must be one of " + strings.Join(auth.Scopes, ", ")
+	case "oneof":
+		return fe.Field() + " must be one of " + strings.Join(strings.Fields(fe.Param()), ", ")
+	default:
+		return fe.Field() + " failed the " + fe.Tag() + " rule"
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+//validation_test.go tests ./validation.go utilizing table based testing best practices.
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePerson(t *testing.T) {
+	testCases := map[string]struct {
+		input          models.Person
+		expectedErrors []FieldError
+	}{
+		"valid": {
+			input:          models.Person{FirstName: "My", LastName: "Person", Type: "student", Age: 20, Courses: []int{1, 2}},
+			expectedErrors: nil,
+		},
+		"invalid type": {
+			input: models.Person{FirstName: "My", LastName: "Person", Type: "madman", Age: 20, Courses: []int{1}},
+			expectedErrors: []FieldError{
+				{Field: "type", Rule: "ValidateType", Message: `type must be either "professor" or "student"`},
+			},
+		},
+		"negative age": {
+			input: models.Person{FirstName: "My", LastName: "Person", Type: "student", Age: -4, Courses: []int{1}},
+			expectedErrors: []FieldError{
+				{Field: "age", Rule: "gt", Param: "0", Message: "age must be greater than 0"},
+			},
+		},
+		"missing names and duplicate courses": {
+			input: models.Person{Type: "professor", Age: 40, Courses: []int{3, 3}},
+			expectedErrors: []FieldError{
+				{Field: "first_name", Rule: "required", Message: "first_name is required"},
+				{Field: "last_name", Rule: "required", Message: "last_name is required"},
+				{Field: "courses", Rule: "unique", Message: "courses must not contain the same course id twice"},
+			},
+		},
+	}
+	for testName, testVars := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testVars.expectedErrors, validatePerson(testVars.input))
+		})
+	}
+}
+func TestValidateCourse(t *testing.T) {
+	assert.Nil(t, validateCourse(models.Course{Name: "Databases"}))
+	assert.Equal(t, []FieldError{{Field: "name", Rule: "required", Message: "name is required"}}, validateCourse(models.Course{}))
+}
+func TestValidateAPIKey(t *testing.T) {
+	assert.Nil(t, validateAPIKey(models.APIKey{Name: "batch", Scopes: auth.Scopes}))
+	assert.Equal(t, []FieldError{{Field: "scopes[1]", Rule: "scope",
+		Message: "scopes[1] must be one of " + strings.Join(auth.Scopes, ", ")}},
+		validateAPIKey(models.APIKey{Name: "batch", Scopes: []string{"course:read", "course:delete"}}))
+}
+func TestCreatePersonValidationProblem(t *testing.T) {
+	mockService := new(services.MockPersonService)
+	handler := &PersonHandler{PersonService: mockService}
+
+	buf := new(bytes.Buffer)
+	err := json.NewEncoder(buf).Encode(models.Person{FirstName: "My", LastName: "Person", Type: "janitor", Courses: []int{1}})
+	assert.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, "/api/person/", buf)
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+
+	handler.CreatePerson(rr, req)
+
+	var problem Problem
+	err = json.NewDecoder(rr.Body).Decode(&problem)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "validation for person object failed", problem.Detail)
+	assert.Equal(t, []FieldError{
+		{Field: "type", Rule: "ValidateType", Message: `type must be either "professor" or "student"`},
+		{Field: "age", Rule: "required", Message: "age is required"},
+	}, problem.Errors)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,51 @@
+package logging
+
+//logging.go builds the log/slog logger of the server, and carries a request's logger in its context so that the
+//handlers and services serving the request log with its request ID.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Formats a logger can write.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// New returns a logger writing to w in format, FormatJSON or FormatText, that drops records below level, one of
+// "debug", "info", "warn" or "error".
+func New(w io.Writer, format string, level string) (*slog.Logger, error) {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unknown log level %q, must be debug, info, warn or error", level)
+	}
+	opts := &slog.HandlerOptions{Level: minLevel}
+	switch format {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q, must be %s or %s", format, FormatJSON, FormatText)
+	}
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx by WithLogger, or slog.Default() if there is none, so code that runs
+// outside of a request can log the same way.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
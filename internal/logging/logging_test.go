@@ -0,0 +1,62 @@
+package logging
+
+//logging_test.go tests ./logging.go utilizing table based testing best practices.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := map[string]struct {
+		format      string
+		level       string
+		expected    string
+		expectedErr bool
+	}{
+		"json":           {format: "json", level: "info", expected: `"msg":"shown"`},
+		"text":           {format: "text", level: "info", expected: "msg=shown"},
+		"debug level":    {format: "text", level: "debug", expected: "msg=hidden"},
+		"upper case":     {format: "text", level: "WARN", expected: "msg=shown"},
+		"unknown format": {format: "xml", level: "info", expectedErr: true},
+		"unknown level":  {format: "json", level: "verbose", expectedErr: true},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			var out bytes.Buffer
+			logger, err := New(&out, testVars.format, testVars.level)
+			if testVars.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			logger.Debug("hidden")
+			logger.Warn("shown")
+			assert.Contains(t, out.String(), testVars.expected)
+			if testVars.level != "debug" {
+				assert.NotContains(t, out.String(), "hidden")
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+
+	var out bytes.Buffer
+	logger, err := New(&out, FormatJSON, "info")
+	require.NoError(t, err)
+	ctx := WithLogger(context.Background(), logger.With("request_id", "abc"))
+	FromContext(ctx).Info("served")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "served", record["msg"])
+	assert.Equal(t, "abc", record["request_id"])
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type APIKey struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name" validate:"required"`
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes" validate:"required,min=1,unique,dive,scope"`
+	// CreatedAt is when the key was created or last rotated.
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
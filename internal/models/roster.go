@@ -0,0 +1,7 @@
+package models
+
+type Roster struct {
+	Course     Course   `json:"course"`
+	Professors []Person `json:"professors"`
+	Students   []Person `json:"students"`
+}
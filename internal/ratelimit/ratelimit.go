@@ -0,0 +1,174 @@
+package ratelimit
+
+//ratelimit.go defines the token buckets that limit how many requests each client may make.
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit allows Requests requests Per period. Clients may use them all at once, after which they are refilled one at a
+// time, evenly spread over the period. The zero Limit allows everything.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseLimit parses a Limit such as "60/m", "10/s" or "1000/1h", or "off" for the zero Limit. The period is a
+// duration such as 30s, whose leading 1 may be left out.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" {
+		return Limit{}, nil
+	}
+	requests, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q must be requests per period such as 60/m, or off", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n < 1 {
+		return Limit{}, fmt.Errorf("limit %q must allow a positive number of requests", s)
+	}
+	per = strings.TrimSpace(per)
+	if per != "" && (per[0] < '0' || per[0] > '9') {
+		per = "1" + per
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("limit %q must have a positive period such as s, m or 30s", s)
+	}
+	return Limit{Requests: n, Per: d}, nil
+}
+
+// Off returns whether l allows everything.
+func (l Limit) Off() bool {
+	return l.Requests <= 0 || l.Per <= 0
+}
+
+func (l Limit) String() string {
+	if l.Off() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+// Rule applies Limit to the requests for a route instead of the default Limit.
+type Rule struct {
+	// Method is the HTTP method the rule applies to, or "" for every method.
+	Method string
+	// Path matches exactly, or ends in "*" to match every path it prefixes, such as "/api/person/*".
+	Path  string
+	Limit Limit
+}
+
+// ParseRule parses a Rule such as "GET /api/person=60/m" or "/api/*=off". See ParseLimit for the limit.
+func ParseRule(s string) (Rule, error) {
+	route, limit, ok := strings.Cut(s, "=")
+	if !ok {
+		return Rule{}, fmt.Errorf("rate limit rule %q must be [METHOD] PATH=LIMIT such as GET /api/person=60/m", s)
+	}
+	var rule Rule
+	fields := strings.Fields(route)
+	switch len(fields) {
+	case 1:
+		rule.Path = fields[0]
+	case 2:
+		rule.Method, rule.Path = strings.ToUpper(fields[0]), fields[1]
+	default:
+		return Rule{}, fmt.Errorf("rate limit rule %q must be [METHOD] PATH=LIMIT such as GET /api/person=60/m", s)
+	}
+	if !strings.HasPrefix(rule.Path, "/") {
+		return Rule{}, fmt.Errorf("rate limit rule %q must have a path starting with /", s)
+	}
+	var err error
+	if rule.Limit, err = ParseLimit(limit); err != nil {
+		return Rule{}, fmt.Errorf("rate limit rule %q: %w", s, err)
+	}
+	return rule, nil
+}
+
+// Decision is the outcome of Limiter.Take.
+type Decision struct {
+	Allowed bool
+	// Limit is the number of requests the limit allows at once, and Remaining how many of them are left.
+	Limit     int
+	Remaining int
+	// Reset is how long until all of Limit is available again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, 0 if it is allowed now.
+	RetryAfter time.Duration
+}
+
+// Limiter keeps a token bucket per client for a single Limit. It is safe for concurrent use.
+type Limiter struct {
+	limit Limit
+	// interval is how long refilling a single request takes.
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket holds the requests a client has left at updated.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewLimiter returns a Limiter enforcing limit, which must not be Off.
+func NewLimiter(limit Limit) *Limiter {
+	return &Limiter{
+		limit:    limit,
+		interval: limit.Per / time.Duration(limit.Requests),
+		now:      time.Now,
+		buckets:  make(map[string]*bucket),
+	}
+}
+
+// Take takes a request from the bucket of client, if it has one left, and returns whether it did.
+func (l *Limiter) Take(client string) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.sweep(now)
+
+	capacity := float64(l.limit.Requests)
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.updated))/float64(l.interval))
+	b.updated = now
+
+	decision := Decision{Limit: l.limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = time.Duration((1 - b.tokens) * float64(l.interval))
+	}
+	decision.Remaining = int(b.tokens)
+	decision.Reset = time.Duration((capacity - b.tokens) * float64(l.interval))
+	return decision
+}
+
+// sweep forgets the buckets that have refilled, which behave like new ones, at most once per period so the clients
+// seen by a long running server do not pile up.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.limit.Per {
+		return
+	}
+	l.lastSweep = now
+	for client, b := range l.buckets {
+		if now.Sub(b.updated) >= l.limit.Per {
+			delete(l.buckets, client)
+		}
+	}
+}
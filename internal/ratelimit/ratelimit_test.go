@@ -0,0 +1,127 @@
+package ratelimit
+
+//ratelimit_test.go tests ./ratelimit.go utilizing table based testing best practices.
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	testCases := map[string]struct {
+		limit       string
+		expected    Limit
+		expectedErr bool
+	}{
+		"per minute":      {limit: "60/m", expected: Limit{Requests: 60, Per: time.Minute}},
+		"per second":      {limit: "10/s", expected: Limit{Requests: 10, Per: time.Second}},
+		"explicit period": {limit: " 1000 / 1h ", expected: Limit{Requests: 1000, Per: time.Hour}},
+		"partial period":  {limit: "5/30s", expected: Limit{Requests: 5, Per: 30 * time.Second}},
+		"off":             {limit: "off", expected: Limit{}},
+		"no period":       {limit: "60", expectedErr: true},
+		"zero requests":   {limit: "0/m", expectedErr: true},
+		"not a number":    {limit: "many/m", expectedErr: true},
+		"unknown period":  {limit: "60/fortnight", expectedErr: true},
+		"negative period": {limit: "60/-1m", expectedErr: true},
+		"empty":           {limit: "", expectedErr: true},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			limit, err := ParseLimit(testVars.limit)
+			if testVars.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testVars.expected, limit)
+		})
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	testCases := map[string]struct {
+		rule        string
+		expected    Rule
+		expectedErr bool
+	}{
+		"method and path": {
+			rule:     "get /api/person=60/m",
+			expected: Rule{Method: "GET", Path: "/api/person", Limit: Limit{Requests: 60, Per: time.Minute}},
+		},
+		"any method": {
+			rule:     "/api/*=off",
+			expected: Rule{Path: "/api/*"},
+		},
+		"no limit":       {rule: "GET /api/person", expectedErr: true},
+		"no path":        {rule: "GET=60/m", expectedErr: true},
+		"relative path":  {rule: "GET api/person=60/m", expectedErr: true},
+		"too many words": {rule: "GET /api/person now=60/m", expectedErr: true},
+		"bad limit":      {rule: "/api/person=60", expectedErr: true},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			rule, err := ParseRule(testVars.rule)
+			if testVars.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testVars.expected, rule)
+		})
+	}
+}
+
+func TestLimiterTake(t *testing.T) {
+	start := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	limiter := NewLimiter(Limit{Requests: 3, Per: 3 * time.Second})
+	limiter.now = func() time.Time { return now }
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		decision := limiter.Take("alice")
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 3, decision.Limit)
+		assert.Equal(t, remaining, decision.Remaining)
+		assert.Equal(t, time.Duration(3-remaining)*time.Second, decision.Reset)
+	}
+	decision := limiter.Take("alice")
+	assert.Equal(t, Decision{Limit: 3, Reset: 3 * time.Second, RetryAfter: time.Second}, decision)
+
+	// every client has a bucket of its own
+	assert.True(t, limiter.Take("bob").Allowed)
+
+	// a request is refilled every second
+	now = start.Add(1500 * time.Millisecond)
+	decision = limiter.Take("alice")
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+	decision = limiter.Take("alice")
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 500*time.Millisecond, decision.RetryAfter)
+
+	// buckets never hold more than the limit
+	now = start.Add(time.Hour)
+	decision = limiter.Take("alice")
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 2, decision.Remaining)
+}
+
+func TestLimiterSweep(t *testing.T) {
+	start := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	limiter := NewLimiter(Limit{Requests: 10, Per: time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	limiter.Take("alice")
+	now = start.Add(30 * time.Second)
+	limiter.Take("bob")
+	assert.Len(t, limiter.buckets, 2)
+
+	// alice's bucket has refilled a minute after her request, bob's has not
+	now = start.Add(70 * time.Second)
+	limiter.Take("carol")
+	assert.Len(t, limiter.buckets, 2)
+	assert.NotContains(t, limiter.buckets, "alice")
+}
@@ -3,34 +3,68 @@ package routes
 //routes.go defines the URL routes of all http endpoints, and what handler functions are called for each endpoint.
 
 import (
-	"database/sql"
 	"net/http"
+	"tech-challenge/internal/auth"
 	"tech-challenge/internal/handlers"
 	"tech-challenge/internal/services"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func SetupRoutes(r chi.Router, db *sql.DB) {
+// SetupRoutes serves the API on r from the given services, which may be backed by Postgres, SQLite or a
+// services.MemoryStore. Every /api route asks policy whether the caller may perform its auth.Action, unless policy is
+// nil, in which case anyone may do anything.
+func SetupRoutes(r chi.Router, personService services.PersonService, courseService services.CourseService, enrollmentService services.EnrollmentService, apiKeyService services.APIKeyService, policy *auth.Policy) {
 	c := new(handlers.CourseHandler)
-	c.CourseService = services.NewCourseService(db)
+	c.CourseService = courseService
 	p := new(handlers.PersonHandler)
-	p.PersonService = services.NewPersonService(db)
+	p.PersonService = personService
+	e := new(handlers.EnrollmentHandler)
+	e.EnrollmentService = enrollmentService
+	k := new(handlers.APIKeyHandler)
+	k.APIKeyService = apiKeyService
+	if policy != nil {
+		p.Authorizer = policy
+	}
+	can := func(action auth.Action) func(http.Handler) http.Handler {
+		if policy == nil {
+			return func(next http.Handler) http.Handler { return next }
+		}
+		return handlers.Authorize(policy, action)
+	}
 
+	r.Get("/health", handlers.Health)
 	r.Route("/api", func(r chi.Router) {
 		r.Route("/course", func(r chi.Router) {
-			r.Get("/", func(w http.ResponseWriter, r *http.Request) { c.GetAllCourses(w, r) })
-			r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) { c.GetCourse(w, r) })
-			r.Put("/{id}", func(w http.ResponseWriter, r *http.Request) { c.UpdateCourse(w, r) })
-			r.Post("/", func(w http.ResponseWriter, r *http.Request) { c.CreateCourse(w, r) })
-			r.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) { c.DeleteCourse(w, r) })
+			r.With(can(auth.ListCourses)).Get("/", func(w http.ResponseWriter, r *http.Request) { c.GetAllCourses(w, r) })
+			r.With(can(auth.ReadCourse)).Get("/{id}", func(w http.ResponseWriter, r *http.Request) { c.GetCourse(w, r) })
+			r.With(can(auth.UpdateCourse)).Put("/{id}", func(w http.ResponseWriter, r *http.Request) { c.UpdateCourse(w, r) })
+			r.With(can(auth.CreateCourse)).Post("/", func(w http.ResponseWriter, r *http.Request) { c.CreateCourse(w, r) })
+			r.With(can(auth.DeleteCourse)).Delete("/{id}", func(w http.ResponseWriter, r *http.Request) { c.DeleteCourse(w, r) })
+			r.With(can(auth.UpdateCourse)).Patch("/{id}", func(w http.ResponseWriter, r *http.Request) { c.PatchCourse(w, r) })
+			r.With(can(auth.ReadRoster)).Get("/{id}/people", func(w http.ResponseWriter, r *http.Request) { c.GetCourseRoster(w, r) })
 		})
 		r.Route("/person", func(r chi.Router) {
-			r.Get("/", func(w http.ResponseWriter, r *http.Request) { p.GetAllPeople(w, r) })
-			r.Get("/{name}", func(w http.ResponseWriter, r *http.Request) { p.GetPerson(w, r) })
-			r.Put("/{name}", func(w http.ResponseWriter, r *http.Request) { p.UpdatePerson(w, r) })
-			r.Post("/", func(w http.ResponseWriter, r *http.Request) { p.CreatePerson(w, r) })
-			r.Delete("/{name}", func(w http.ResponseWriter, r *http.Request) { p.DeletePerson(w, r) })
+			r.With(can(auth.ListPeople)).Get("/", func(w http.ResponseWriter, r *http.Request) { p.GetAllPeople(w, r) })
+			r.With(can(auth.ReadPerson)).Get("/{name}", func(w http.ResponseWriter, r *http.Request) { p.GetPerson(w, r) })
+			r.With(can(auth.UpdatePerson)).Put("/{name}", func(w http.ResponseWriter, r *http.Request) { p.UpdatePerson(w, r) })
+			r.With(can(auth.CreatePerson)).Post("/", func(w http.ResponseWriter, r *http.Request) { p.CreatePerson(w, r) })
+			r.With(can(auth.DeletePerson)).Delete("/{name}", func(w http.ResponseWriter, r *http.Request) { p.DeletePerson(w, r) })
+			r.With(can(auth.UpdatePerson)).Patch("/{name}", func(w http.ResponseWriter, r *http.Request) { p.PatchPerson(w, r) })
+			r.With(can(auth.ReadPerson)).Get("/id/{id}", func(w http.ResponseWriter, r *http.Request) { p.GetPersonByID(w, r) })
+			r.With(can(auth.UpdatePerson)).Put("/id/{id}", func(w http.ResponseWriter, r *http.Request) { p.UpdatePersonByID(w, r) })
+			r.With(can(auth.DeletePerson)).Delete("/id/{id}", func(w http.ResponseWriter, r *http.Request) { p.DeletePersonByID(w, r) })
+			r.With(can(auth.UpdatePerson)).Patch("/id/{id}", func(w http.ResponseWriter, r *http.Request) { p.PatchPersonByID(w, r) })
+			r.With(can(auth.ReadEnrollments)).Get("/{id}/courses", func(w http.ResponseWriter, r *http.Request) { e.GetCoursesForPerson(w, r) })
+			r.With(can(auth.Enroll)).Post("/{id}/courses/{courseId}", func(w http.ResponseWriter, r *http.Request) { e.Enroll(w, r) })
+			r.With(can(auth.Drop)).Delete("/{id}/courses/{courseId}", func(w http.ResponseWriter, r *http.Request) { e.Drop(w, r) })
+		})
+		r.Route("/apikey", func(r chi.Router) {
+			r.Use(can(auth.ManageAPIKeys))
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) { k.GetAllAPIKeys(w, r) })
+			r.Post("/", func(w http.ResponseWriter, r *http.Request) { k.CreateAPIKey(w, r) })
+			r.Post("/{id}/rotate", func(w http.ResponseWriter, r *http.Request) { k.RotateAPIKey(w, r) })
+			r.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) { k.RevokeAPIKey(w, r) })
 		})
 	})
 }
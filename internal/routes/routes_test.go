@@ -0,0 +1,121 @@
+package routes
+
+//routes_test.go tests ./routes.go by serving requests of authenticated callers against the in-memory services.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonNameRoutesAuthorizeResolvedPerson(t *testing.T) {
+	store := services.NewMemoryStore()
+	people := services.NewMemoryPersonService(store)
+	for _, person := range []models.Person{
+		{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 20},
+		{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 31},
+	} {
+		_, err := people.CreatePerson(context.Background(), person)
+		require.NoError(t, err)
+	}
+	student := auth.Identity{Subject: "tim", Roles: []string{auth.RoleStudent}, PersonID: 1}
+	admin := auth.Identity{Subject: "root", Roles: []string{auth.RoleAdmin}}
+
+	testCases := map[string]struct {
+		identity           auth.Identity
+		method             string
+		path               string
+		expectedHTTPCode   int
+		expectedCandidates []int
+	}{
+		"student reads self by id query":       {identity: student, method: http.MethodGet, path: "/api/person/Tim%20Rogers?id=1", expectedHTTPCode: http.StatusOK},
+		"student reads namesake by id query":   {identity: student, method: http.MethodGet, path: "/api/person/Tim%20Rogers?id=2", expectedHTTPCode: http.StatusForbidden},
+		"student reads namesake by id route":   {identity: student, method: http.MethodGet, path: "/api/person/id/2", expectedHTTPCode: http.StatusForbidden},
+		"student only sees self as candidate":  {identity: student, method: http.MethodGet, path: "/api/person/Tim%20Rogers", expectedHTTPCode: http.StatusConflict, expectedCandidates: []int{1}},
+		"student deletes namesake by id query": {identity: student, method: http.MethodDelete, path: "/api/person/Tim%20Rogers?id=2", expectedHTTPCode: http.StatusForbidden},
+		"admin reads namesake by id query":     {identity: admin, method: http.MethodGet, path: "/api/person/Tim%20Rogers?id=2", expectedHTTPCode: http.StatusOK},
+		"admin sees every candidate":           {identity: admin, method: http.MethodGet, path: "/api/person/Tim%20Rogers", expectedHTTPCode: http.StatusConflict, expectedCandidates: []int{1, 2}},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					next.ServeHTTP(w, req.WithContext(auth.WithIdentity(req.Context(), testVars.identity)))
+				})
+			})
+			SetupRoutes(r, people, services.NewMemoryCourseService(store), services.NewMemoryEnrollmentService(store),
+				services.NewMemoryAPIKeyService(store), auth.NewPolicy(people))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(testVars.method, testVars.path, nil))
+
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+			switch rr.Code {
+			case http.StatusOK:
+				var person models.Person
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&person))
+				assert.Equal(t, "Tim", person.FirstName)
+			case http.StatusConflict:
+				var problem struct {
+					Candidates []struct {
+						ID int `json:"id"`
+					} `json:"candidates"`
+				}
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&problem))
+				ids := make([]int, 0, len(problem.Candidates))
+				for _, candidate := range problem.Candidates {
+					ids = append(ids, candidate.ID)
+				}
+				assert.ElementsMatch(t, testVars.expectedCandidates, ids)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRoutesNeedAPIKeyAdminScope(t *testing.T) {
+	store := services.NewMemoryStore()
+	people := services.NewMemoryPersonService(store)
+	testCases := map[string]struct {
+		identity         auth.Identity
+		method           string
+		path             string
+		expectedHTTPCode int
+	}{
+		"key admin lists keys":       {identity: auth.Identity{Subject: "apikey:tc_1", Scopes: []string{auth.ScopeAPIKeyAdmin}}, method: http.MethodGet, path: "/api/apikey", expectedHTTPCode: http.StatusOK},
+		"key admin revokes a key":    {identity: auth.Identity{Subject: "apikey:tc_1", Scopes: []string{auth.ScopeAPIKeyAdmin}}, method: http.MethodDelete, path: "/api/apikey/1", expectedHTTPCode: http.StatusOK},
+		"read only key lists keys":   {identity: auth.Identity{Subject: "apikey:tc_2", Scopes: []string{auth.ScopeCourseRead}}, method: http.MethodGet, path: "/api/apikey", expectedHTTPCode: http.StatusForbidden},
+		"key admin reads a course":   {identity: auth.Identity{Subject: "apikey:tc_1", Scopes: []string{auth.ScopeAPIKeyAdmin}}, method: http.MethodGet, path: "/api/course", expectedHTTPCode: http.StatusForbidden},
+		"admin token lists keys":     {identity: auth.Identity{Subject: "root", Roles: []string{auth.RoleAdmin}}, method: http.MethodGet, path: "/api/apikey", expectedHTTPCode: http.StatusOK},
+		"professor token lists keys": {identity: auth.Identity{Subject: "ada", Roles: []string{auth.RoleProfessor}}, method: http.MethodGet, path: "/api/apikey", expectedHTTPCode: http.StatusForbidden},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			apiKeys := services.NewMemoryAPIKeyService(services.NewMemoryStore())
+			_, _, err := apiKeys.CreateAPIKey(context.Background(), models.APIKey{Name: "ops", Scopes: []string{auth.ScopeAPIKeyAdmin}})
+			require.NoError(t, err)
+			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					next.ServeHTTP(w, req.WithContext(auth.WithIdentity(req.Context(), testVars.identity)))
+				})
+			})
+			SetupRoutes(r, people, services.NewMemoryCourseService(store), services.NewMemoryEnrollmentService(store),
+				apiKeys, auth.NewPolicy(people))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(testVars.method, testVars.path, nil))
+
+			assert.Equal(t, testVars.expectedHTTPCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+//apikey.go defines the service functions used by RealAPIKeyService structs to manage the api_key table, and an APIKeyService interface for testing.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/logging"
+	"tech-challenge/internal/models"
+	"time"
+)
+
+type APIKeyService interface {
+	GetAllAPIKeys(context.Context) ([]models.APIKey, error)
+	CreateAPIKey(context.Context, models.APIKey) (models.APIKey, string, error)
+	RotateAPIKey(context.Context, int) (models.APIKey, string, error)
+	RevokeAPIKey(context.Context, int) error
+	AuthenticateAPIKey(context.Context, string) (models.APIKey, error)
+}
+
+// lastUsedInterval is how out of date APIKey.LastUsedAt may be, so a key used by every request of a batch job is not
+// written by every request.
+const lastUsedInterval = time.Minute
+
+// apiKeyColumns are the columns scanned by scanAPIKey.
+const apiKeyColumns = `"id", "name", "prefix", "scopes", "created_at", "last_used_at", "revoked_at"`
+
+type RealAPIKeyService struct {
+	db *sql.DB
+}
+
+func NewAPIKeyService(db *sql.DB) *RealAPIKeyService {
+	return &RealAPIKeyService{
+		db: db,
+	}
+}
+
+// GetAllAPIKeys returns every API key ordered by id, including revoked ones.
+func (a *RealAPIKeyService) GetAllAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := a.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM "api_key" ORDER BY "id"`)
+	if err != nil {
+		return []models.APIKey{}, fmt.Errorf("failed to get API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]models.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return []models.APIKey{}, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return []models.APIKey{}, fmt.Errorf("failed to scan API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// CreateAPIKey stores a new API key with the name and scopes of key. It returns the stored key and the secret API key
+// itself, which is not stored and cannot be retrieved again.
+func (a *RealAPIKeyService) CreateAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, string, error) {
+	generated, err := auth.GenerateAPIKey()
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+	key = models.APIKey{Name: key.Name, Prefix: generated.Prefix, Scopes: key.Scopes, CreatedAt: now()}
+	err = a.db.QueryRowContext(ctx, `INSERT INTO "api_key" (name, prefix, salt, hash, scopes, created_at)
+							VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		key.Name,
+		key.Prefix,
+		generated.Salt,
+		generated.Hash,
+		strings.Join(key.Scopes, ","),
+		key.CreatedAt).Scan(&key.ID)
+	if err != nil {
+		return models.APIKey{}, "", fmt.Errorf("failed to create API key: %w", mapDBError(err))
+	}
+	return key, generated.Key, nil
+}
+
+// RotateAPIKey replaces the secret of the API key with id, which stops the old secret from working. It returns the key
+// and its new secret like CreateAPIKey, or ErrAPIKeyNotFound if there is no such key or it was revoked.
+func (a *RealAPIKeyService) RotateAPIKey(ctx context.Context, id int) (models.APIKey, string, error) {
+	generated, err := auth.GenerateAPIKey()
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+	rows, err := a.db.QueryContext(ctx, `UPDATE "api_key"
+							SET "prefix" = $1, "salt" = $2, "hash" = $3, "created_at" = $4, "last_used_at" = NULL
+							WHERE "id" = $5 AND "revoked_at" IS NULL
+							RETURNING `+apiKeyColumns,
+		generated.Prefix,
+		generated.Salt,
+		generated.Hash,
+		now(),
+		id)
+	if err != nil {
+		return models.APIKey{}, "", fmt.Errorf("failed to rotate API key: %w", mapDBError(err))
+	}
+	defer rows.Close()
+
+	if isEmpty := !rows.Next(); isEmpty {
+		if err = rows.Err(); err != nil {
+			return models.APIKey{}, "", fmt.Errorf("failed to rotate API key: %w", mapDBError(err))
+		}
+		return models.APIKey{}, "", ErrAPIKeyNotFound
+	}
+	key, err := scanAPIKey(rows)
+	if err != nil {
+		return models.APIKey{}, "", fmt.Errorf("failed to scan API key: %w", err)
+	}
+	return key, generated.Key, nil
+}
+
+// RevokeAPIKey stops the API key with id from working for good. Revoked keys are kept, so GetAllAPIKeys shows when
+// they were last used. Revoking a key twice keeps the time of the first revocation.
+func (a *RealAPIKeyService) RevokeAPIKey(ctx context.Context, id int) error {
+	result, err := a.db.ExecContext(ctx, `UPDATE "api_key"
+							SET "revoked_at" = COALESCE("revoked_at", $1)
+							WHERE "id" = $2`,
+		now(),
+		id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", mapDBError(err))
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get the number of affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns the stored API key matching the secret API key, and records that it was used. It returns
+// ErrInvalidAPIKey if key is malformed, unknown or revoked.
+func (a *RealAPIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (models.APIKey, error) {
+	prefix, ok := auth.APIKeyPrefix(key)
+	if !ok {
+		return models.APIKey{}, ErrInvalidAPIKey
+	}
+	var salt, hash string
+	row := a.db.QueryRowContext(ctx, `SELECT `+apiKeyColumns+`, "salt", "hash" FROM "api_key"
+							WHERE "prefix" = $1`,
+		prefix)
+	stored, err := scanAPIKey(row, &salt, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.APIKey{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return models.APIKey{}, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if matches := auth.CheckAPIKey(key, salt, hash); !matches || stored.RevokedAt != nil {
+		// the client is only told the key is invalid, the reason could help guess keys
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelDebug, "API key rejected",
+			slog.String("prefix", prefix),
+			slog.Bool("secret_matches", matches),
+			slog.Bool("revoked", stored.RevokedAt != nil))
+		return models.APIKey{}, ErrInvalidAPIKey
+	}
+
+	if usedAt := now(); stored.LastUsedAt == nil || usedAt.Sub(*stored.LastUsedAt) >= lastUsedInterval {
+		_, err = a.db.ExecContext(ctx, `UPDATE "api_key" SET "last_used_at" = $1 WHERE "id" = $2`, usedAt, stored.ID)
+		if err != nil {
+			return models.APIKey{}, fmt.Errorf("failed to record API key use: %w", mapDBError(err))
+		}
+		stored.LastUsedAt = &usedAt
+	}
+	return stored, nil
+}
+
+// scanAPIKey scans the apiKeyColumns of row, followed by extra columns into extra.
+func scanAPIKey(row interface{ Scan(...any) error }, extra ...any) (models.APIKey, error) {
+	var key models.APIKey
+	var scopes string
+	var lastUsedAt, revokedAt sql.NullTime
+	dest := append([]any{&key.ID, &key.Name, &key.Prefix, &scopes, &key.CreatedAt, &lastUsedAt, &revokedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return models.APIKey{}, err
+	}
+	key.Scopes = strings.Split(scopes, ",")
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
+
+// now returns the current time in UTC, rounded to the microseconds Postgres stores.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
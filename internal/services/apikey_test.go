@@ -0,0 +1,268 @@
+package services
+
+//apikey_test.go tests ./apikey.go.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var apiKeyColumnNames = []string{"id", "name", "prefix", "scopes", "created_at", "last_used_at", "revoked_at"}
+
+func (s *testSuit) TestGetAllAPIKeys() {
+	t := s.T()
+
+	created := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	used := created.Add(time.Hour)
+	testCases := map[string]struct {
+		mockReturn     *sqlmock.Rows
+		mockReturnErr  error
+		expectedReturn []models.APIKey
+		expectsErr     bool
+	}{
+		"GetSuccess": {
+			mockReturn: sqlmock.NewRows(apiKeyColumnNames).
+				AddRow(1, "batch", "tc_3f9a0c12d4e5", "person:read,course:read", created, used, nil).
+				AddRow(2, "partner", "tc_000000000000", "course:write", created, nil, used),
+			expectedReturn: []models.APIKey{
+				{ID: 1, Name: "batch", Prefix: "tc_3f9a0c12d4e5", Scopes: []string{"person:read", "course:read"}, CreatedAt: created, LastUsedAt: &used},
+				{ID: 2, Name: "partner", Prefix: "tc_000000000000", Scopes: []string{"course:write"}, CreatedAt: created, RevokedAt: &used},
+			},
+		},
+		"NoKeysSuccess": {
+			mockReturn:     sqlmock.NewRows(apiKeyColumnNames),
+			expectedReturn: []models.APIKey{},
+		},
+		"QueryError": {
+			mockReturn:     sqlmock.NewRows(apiKeyColumnNames),
+			mockReturnErr:  errors.New("can't query"),
+			expectedReturn: []models.APIKey{},
+			expectsErr:     true,
+		},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			query := `SELECT "id", "name", "prefix", "scopes", "created_at", "last_used_at", "revoked_at" FROM "api_key" ORDER BY "id"`
+			s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testConditions.mockReturn).WillReturnError(testConditions.mockReturnErr)
+
+			actualReturn, err := s.apiKeyService.GetAllAPIKeys(context.Background())
+			if testConditions.expectsErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testConditions.expectedReturn, actualReturn)
+			assert.NoError(t, s.dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *testSuit) TestCreateAPIKey() {
+	t := s.T()
+
+	testCases := map[string]struct {
+		mockReturnErr error
+		expectedErr   error
+	}{
+		"CreateSuccess": {},
+		"DuplicatePrefix": {
+			mockReturnErr: &pq.Error{Code: "23505", Constraint: "api_key_prefix_key"},
+			expectedErr:   ErrConflict,
+		},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			query := `INSERT INTO "api_key" (name, prefix, salt, hash, scopes, created_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+			s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).
+				WithArgs("batch", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "person:read,course:read", sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4)).
+				WillReturnError(testConditions.mockReturnErr)
+
+			key, secret, err := s.apiKeyService.CreateAPIKey(context.Background(), models.APIKey{ID: 9, Name: "batch", Scopes: []string{"person:read", "course:read"}})
+			if testConditions.expectedErr != nil {
+				assert.ErrorIs(t, err, testConditions.expectedErr)
+				assert.Equal(t, models.APIKey{}, key)
+				assert.Empty(t, secret)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 4, key.ID)
+				assert.Equal(t, []string{"person:read", "course:read"}, key.Scopes)
+				prefix, ok := auth.APIKeyPrefix(secret)
+				assert.True(t, ok)
+				assert.Equal(t, key.Prefix, prefix)
+			}
+			assert.NoError(t, s.dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *testSuit) TestRotateAPIKey() {
+	t := s.T()
+
+	created := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		mockReturn  *sqlmock.Rows
+		expectedErr error
+	}{
+		"RotateSuccess": {
+			mockReturn: sqlmock.NewRows(apiKeyColumnNames).AddRow(4, "batch", "tc_3f9a0c12d4e5", "person:write", created, nil, nil),
+		},
+		"NotFoundOrRevoked": {
+			mockReturn:  sqlmock.NewRows(apiKeyColumnNames),
+			expectedErr: ErrAPIKeyNotFound,
+		},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			query := `UPDATE "api_key" SET "prefix" = $1, "salt" = $2, "hash" = $3, "created_at" = $4, "last_used_at" = NULL WHERE "id" = $5 AND "revoked_at" IS NULL RETURNING "id", "name", "prefix", "scopes", "created_at", "last_used_at", "revoked_at"`
+			s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 4).
+				WillReturnRows(testConditions.mockReturn)
+
+			key, secret, err := s.apiKeyService.RotateAPIKey(context.Background(), 4)
+			if testConditions.expectedErr != nil {
+				assert.ErrorIs(t, err, testConditions.expectedErr)
+				assert.Empty(t, secret)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.APIKey{ID: 4, Name: "batch", Prefix: "tc_3f9a0c12d4e5", Scopes: []string{"person:write"}, CreatedAt: created}, key)
+				assert.NotEmpty(t, secret)
+			}
+			assert.NoError(t, s.dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *testSuit) TestRevokeAPIKey() {
+	t := s.T()
+
+	testCases := map[string]struct {
+		rowsAffected  int64
+		mockReturnErr error
+		expectedErr   error
+		expectsErr    bool
+	}{
+		"RevokeSuccess": {rowsAffected: 1},
+		"NotFound":      {rowsAffected: 0, expectedErr: ErrAPIKeyNotFound},
+		"ExecError":     {mockReturnErr: errors.New("can't update"), expectsErr: true},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			query := `UPDATE "api_key" SET "revoked_at" = COALESCE("revoked_at", $1) WHERE "id" = $2`
+			s.dbMock.ExpectExec(regexp.QuoteMeta(query)).
+				WithArgs(sqlmock.AnyArg(), 4).
+				WillReturnResult(sqlmock.NewResult(0, testConditions.rowsAffected)).
+				WillReturnError(testConditions.mockReturnErr)
+
+			err := s.apiKeyService.RevokeAPIKey(context.Background(), 4)
+			switch {
+			case testConditions.expectedErr != nil:
+				assert.ErrorIs(t, err, testConditions.expectedErr)
+			case testConditions.expectsErr:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, s.dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *testSuit) TestAuthenticateAPIKey() {
+	t := s.T()
+
+	generated, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	other, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	created := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	recently := time.Now().UTC().Add(-10 * time.Second)
+	storedRow := func(lastUsedAt any, revokedAt any, hash string) *sqlmock.Rows {
+		return sqlmock.NewRows(append(apiKeyColumnNames, "salt", "hash")).
+			AddRow(4, "batch", generated.Prefix, "course:read", created, lastUsedAt, revokedAt, generated.Salt, hash)
+	}
+
+	testCases := map[string]struct {
+		key             string
+		mockReturn      *sqlmock.Rows
+		mockReturnErr   error
+		expectsUpdate   bool
+		expectedErr     error
+		expectsLastUsed bool
+	}{
+		"FirstUse": {
+			key:             generated.Key,
+			mockReturn:      storedRow(nil, nil, generated.Hash),
+			expectsUpdate:   true,
+			expectsLastUsed: true,
+		},
+		"RecentlyUsed": {
+			key:             generated.Key,
+			mockReturn:      storedRow(recently, nil, generated.Hash),
+			expectsLastUsed: true,
+		},
+		"UsedLongAgo": {
+			key:             generated.Key,
+			mockReturn:      storedRow(created, nil, generated.Hash),
+			expectsUpdate:   true,
+			expectsLastUsed: true,
+		},
+		"WrongSecret": {
+			key:         generated.Prefix + other.Key[len(other.Prefix):],
+			mockReturn:  storedRow(nil, nil, generated.Hash),
+			expectedErr: ErrInvalidAPIKey,
+		},
+		"Revoked": {
+			key:         generated.Key,
+			mockReturn:  storedRow(nil, created, generated.Hash),
+			expectedErr: ErrInvalidAPIKey,
+		},
+		"UnknownPrefix": {
+			key:           generated.Key,
+			mockReturn:    storedRow(nil, nil, generated.Hash),
+			mockReturnErr: sql.ErrNoRows,
+			expectedErr:   ErrInvalidAPIKey,
+		},
+		"Malformed": {
+			key:         "Bearer something",
+			expectedErr: ErrInvalidAPIKey,
+		},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			if testConditions.mockReturn != nil {
+				query := `SELECT "id", "name", "prefix", "scopes", "created_at", "last_used_at", "revoked_at", "salt", "hash" FROM "api_key" WHERE "prefix" = $1`
+				s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(generated.Prefix).
+					WillReturnRows(testConditions.mockReturn).
+					WillReturnError(testConditions.mockReturnErr)
+			}
+			if testConditions.expectsUpdate {
+				query := `UPDATE "api_key" SET "last_used_at" = $1 WHERE "id" = $2`
+				s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(sqlmock.AnyArg(), 4).WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			key, err := s.apiKeyService.AuthenticateAPIKey(context.Background(), testConditions.key)
+			if testConditions.expectedErr != nil {
+				assert.ErrorIs(t, err, testConditions.expectedErr)
+				assert.Equal(t, models.APIKey{}, key)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 4, key.ID)
+				assert.Equal(t, []string{"course:read"}, key.Scopes)
+			}
+			assert.Equal(t, testConditions.expectsLastUsed, key.LastUsedAt != nil)
+			assert.NoError(t, s.dbMock.ExpectationsWereMet())
+		})
+	}
+}
@@ -3,19 +3,26 @@ package services
 //course.go defines the service functions and logic used by RealCourseService structs to query a db, and a CourseService interface for testing.
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"tech-challenge/internal/models"
 )
 
 type CourseService interface {
-	GetAllCourses() ([]models.Course, error)
-	GetCourse(int) (models.Course, error)
-	UpdateCourse(int, models.Course) (models.Course, error)
-	CreateCourse(models.Course) (int, error)
-	DeleteCourse(int) (int64, error)
+	GetAllCourses(context.Context, ListOptions) ([]models.Course, PageInfo, error)
+	GetCourse(context.Context, int) (models.Course, error)
+	UpdateCourse(context.Context, int, models.Course) (models.Course, error)
+	CreateCourse(context.Context, models.Course) (int, error)
+	DeleteCourse(context.Context, int) (int64, error)
+	PatchCourse(context.Context, int, CoursePatch) (models.Course, error)
+	GetCourseRoster(context.Context, int, string, ListOptions) (models.Roster, PageInfo, error)
 }
 
+// CourseSortFields are the fields GetAllCourses can sort by.
+var CourseSortFields = []string{"id", "name"}
+
 type RealCourseService struct {
 	db *sql.DB
 }
@@ -26,10 +33,23 @@ func NewCourseService(db *sql.DB) *RealCourseService {
 	}
 }
 
-func (c *RealCourseService) GetAllCourses() ([]models.Course, error) {
-	rows, err := c.db.Query(`SELECT * FROM "course"`)
+func (c *RealCourseService) GetAllCourses(ctx context.Context, opts ListOptions) ([]models.Course, PageInfo, error) {
+	query := `SELECT * FROM "course"`
+	pageQuery, pageArgs, err := paginate(query, nil, opts, CourseSortFields)
 	if err != nil {
-		return []models.Course{}, fmt.Errorf("failed to get courses: %w", err)
+		return []models.Course{}, PageInfo{}, err
+	}
+	pageInfo := PageInfo{Total: -1}
+	if opts.IncludeTotal {
+		pageInfo.Total, err = countRows(ctx, c.db, query, nil)
+		if err != nil {
+			return []models.Course{}, PageInfo{}, fmt.Errorf("failed to count courses: %w", err)
+		}
+	}
+
+	rows, err := c.db.QueryContext(ctx, pageQuery, pageArgs...)
+	if err != nil {
+		return []models.Course{}, PageInfo{}, fmt.Errorf("failed to get courses: %w", err)
 	}
 	defer rows.Close()
 
@@ -38,17 +58,18 @@ func (c *RealCourseService) GetAllCourses() ([]models.Course, error) {
 		var course models.Course
 		err = rows.Scan(&course.ID, &course.Name)
 		if err != nil {
-			return []models.Course{}, fmt.Errorf("failed to scan course from row: %w", err)
+			return []models.Course{}, PageInfo{}, fmt.Errorf("failed to scan course from row: %w", err)
 		}
 		courses = append(courses, course)
 	}
 	if err = rows.Err(); err != nil {
-		return []models.Course{}, fmt.Errorf("failed to scan courses: %w", err)
+		return []models.Course{}, PageInfo{}, fmt.Errorf("failed to scan courses: %w", err)
 	}
-	return courses, nil
+	courses, pageInfo.NextCursor = trimPage(courses, opts, courseSortValue)
+	return courses, pageInfo, nil
 }
-func (c *RealCourseService) GetCourse(id int) (models.Course, error) {
-	row, err := c.db.Query(`SELECT * FROM "course" 
+func (c *RealCourseService) GetCourse(ctx context.Context, id int) (models.Course, error) {
+	row, err := c.db.QueryContext(ctx, `SELECT * FROM "course" 
 							WHERE "id" = $1 
 							LIMIT 1`, id)
 	if err != nil {
@@ -58,7 +79,7 @@ func (c *RealCourseService) GetCourse(id int) (models.Course, error) {
 
 	var course models.Course
 	if isEmpty := !row.Next(); isEmpty {
-		return models.Course{}, fmt.Errorf("course not found")
+		return models.Course{}, ErrCourseNotFound
 	}
 	err = row.Scan(&course.ID, &course.Name)
 	if err != nil {
@@ -66,33 +87,58 @@ func (c *RealCourseService) GetCourse(id int) (models.Course, error) {
 	}
 	return course, nil
 }
-func (c *RealCourseService) UpdateCourse(id int, course models.Course) (models.Course, error) {
-	row, err := c.db.Exec(`UPDATE "course" 
+func (c *RealCourseService) UpdateCourse(ctx context.Context, id int, course models.Course) (models.Course, error) {
+	row, err := c.db.ExecContext(ctx, `UPDATE "course" 
 						SET "name" = $1
 						WHERE "id" = $2`,
 		course.Name,
 		id,
 	)
 	if err != nil {
-		return models.Course{}, fmt.Errorf("failed to update course: %w", err)
+		return models.Course{}, fmt.Errorf("failed to update course: %w", mapDBError(err))
 	}
 	rowsAffected, err := row.RowsAffected()
 	if err != nil {
-		return models.Course{}, fmt.Errorf("failed to update course: %w", err)
+		return models.Course{}, fmt.Errorf("failed to update course: %w", mapDBError(err))
 	}
 	if rowsAffected == 0 {
-		return models.Course{}, fmt.Errorf("course not found")
+		return models.Course{}, ErrCourseNotFound
 	}
 	course.ID = id
 	return course, nil
 }
-func (c *RealCourseService) CreateCourse(course models.Course) (int, error) {
-	row, err := c.db.Query(`INSERT INTO "course" (name)
+
+// PatchCourse writes only the columns patch changes on the course with the given id, then returns the stored course.
+func (c *RealCourseService) PatchCourse(ctx context.Context, id int, patch CoursePatch) (models.Course, error) {
+	var columns []column
+	if patch.Name != nil {
+		columns = append(columns, column{"name", *patch.Name})
+	}
+	if len(columns) == 0 {
+		return c.GetCourse(ctx, id)
+	}
+	sets, args := setClause(columns)
+	args = append(args, id)
+
+	var course models.Course
+	err := c.db.QueryRowContext(ctx, `UPDATE "course" SET `+sets+` WHERE "id" = $`+strconv.Itoa(len(args))+` RETURNING "id", "name"`, args...).
+		Scan(&course.ID, &course.Name)
+	if err == sql.ErrNoRows {
+		return models.Course{}, ErrCourseNotFound
+	}
+	if err != nil {
+		return models.Course{}, fmt.Errorf("failed to update course: %w", mapDBError(err))
+	}
+	return course, nil
+}
+func (c *RealCourseService) CreateCourse(ctx context.Context, course models.Course) (int, error) {
+	row, err := c.db.QueryContext(ctx, `INSERT INTO "course" (name)
 							VALUES ($1) RETURNING id`,
 		course.Name)
 	if err != nil {
-		return -1, fmt.Errorf("failed to create course: %w", err)
+		return -1, fmt.Errorf("failed to create course: %w", mapDBError(err))
 	}
+	defer row.Close()
 	var lastInsertedID = -1
 	row.Next()
 	err = row.Scan(&lastInsertedID)
@@ -101,28 +147,28 @@ func (c *RealCourseService) CreateCourse(course models.Course) (int, error) {
 	}
 	return lastInsertedID, nil
 }
-func (c *RealCourseService) DeleteCourse(id int) (int64, error) {
-	tx, err := c.db.Begin()
+func (c *RealCourseService) DeleteCourse(ctx context.Context, id int) (int64, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
 		return -1, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func() {
 		if err != nil {
-			tx.Rollback()
+			rollback(ctx, tx)
 		}
 	}()
 
-	rows, err := tx.Exec(`DELETE FROM "person_course"
+	rows, err := tx.ExecContext(ctx, `DELETE FROM "person_course"
 						WHERE "course_id" = $1`,
 		id)
 	if err != nil {
-		return -1, fmt.Errorf("failed to delete course relations: %w", err)
+		return -1, fmt.Errorf("failed to delete course relations: %w", mapDBError(err))
 	}
-	rows, err = tx.Exec(`DELETE FROM "course"
+	rows, err = tx.ExecContext(ctx, `DELETE FROM "course"
 						WHERE "id" = $1`,
 		id)
 	if err != nil {
-		return -1, fmt.Errorf("failed to delete course with ID: %v. %w", id, err)
+		return -1, fmt.Errorf("failed to delete course with ID: %v. %w", id, mapDBError(err))
 	}
 	rowsAffected, err := rows.RowsAffected()
 	if err != nil {
@@ -130,7 +176,67 @@ func (c *RealCourseService) DeleteCourse(id int) (int64, error) {
 	}
 
 	if err = tx.Commit(); err != nil {
-		return -1, fmt.Errorf("failed to commit transaction: %w", err)
+		return -1, fmt.Errorf("failed to commit transaction: %w", mapDBError(err))
 	}
 	return rowsAffected, nil
 }
+
+// GetCourseRoster returns the people enrolled in the course with the given id, split into professors and students.
+// personType limits the roster to one of the two when it is not empty. opts pages through both lists together, and
+// may sort by any of the PersonSortFields.
+func (c *RealCourseService) GetCourseRoster(ctx context.Context, id int, personType string, opts ListOptions) (models.Roster, PageInfo, error) {
+	course, err := c.GetCourse(ctx, id)
+	if err != nil {
+		return models.Roster{}, PageInfo{}, err
+	}
+
+	query := `SELECT ` + personColumns + ` FROM "person"
+			JOIN "person_course" ON "person_course"."person_id" = "person"."id"
+			WHERE "person_course"."course_id" = $1`
+	args := []any{id}
+	if personType != "" {
+		args = append(args, personType)
+		query += ` AND "person"."type" = $2`
+	}
+
+	pageQuery, pageArgs, err := paginate(query, args, opts, PersonSortFields)
+	if err != nil {
+		return models.Roster{}, PageInfo{}, err
+	}
+	pageInfo := PageInfo{Total: -1}
+	if opts.IncludeTotal {
+		pageInfo.Total, err = countRows(ctx, c.db, query, args)
+		if err != nil {
+			return models.Roster{}, PageInfo{}, fmt.Errorf("failed to count roster: %w", err)
+		}
+	}
+
+	rows, err := c.db.QueryContext(ctx, pageQuery, pageArgs...)
+	if err != nil {
+		return models.Roster{}, PageInfo{}, fmt.Errorf("failed to get roster: %w", err)
+	}
+	defer rows.Close()
+
+	var people []models.Person
+	for rows.Next() {
+		person, err := scanPerson(rows)
+		if err != nil {
+			return models.Roster{}, PageInfo{}, fmt.Errorf("failed to scan person from row: %w", err)
+		}
+		people = append(people, person)
+	}
+	if err = rows.Err(); err != nil {
+		return models.Roster{}, PageInfo{}, fmt.Errorf("failed to scan roster: %w", err)
+	}
+
+	people, pageInfo.NextCursor = trimPage(people, opts, personSortValue)
+	roster := models.Roster{Course: course, Professors: make([]models.Person, 0), Students: make([]models.Person, 0)}
+	for _, person := range people {
+		if person.Type == "professor" {
+			roster.Professors = append(roster.Professors, person)
+		} else {
+			roster.Students = append(roster.Students, person)
+		}
+	}
+	return roster, pageInfo, nil
+}
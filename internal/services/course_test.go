@@ -4,10 +4,13 @@ package services
 //While TBTs would reduce repeated code, they would contain an overabundance of if statements and be less accessible to understand.
 
 import (
+	"bytes"
+	"context"
 	"database/sql/driver"
 	"errors"
 	"fmt"
 	"regexp"
+	"tech-challenge/internal/logging"
 	"tech-challenge/internal/models"
 	"tech-challenge/internal/testutil"
 	"testing"
@@ -55,7 +58,7 @@ func (s *testSuit) TestGetAllCourses() {
 			query := `SELECT * FROM "course"`
 			s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testConditions.mockReturn).WillReturnError(testConditions.mockReturnErr)
 
-			actualReturn, err := s.realCourseService.GetAllCourses()
+			actualReturn, _, err := s.realCourseService.GetAllCourses(context.Background(), ListOptions{})
 			assert.Equal(t, testConditions.expectedErr, err)
 			assert.Equal(t, testConditions.expectedReturn, actualReturn)
 			err = s.dbMock.ExpectationsWereMet()
@@ -98,7 +101,7 @@ func (s *testSuit) TestGetCourse() {
 			mockReturnErr:  nil,
 			mockId:         5,
 			expectedReturn: models.Course{},
-			expectedErr:    fmt.Errorf("course not found"),
+			expectedErr:    ErrCourseNotFound,
 		},
 	}
 	for testName, testConditions := range testCases {
@@ -109,7 +112,7 @@ func (s *testSuit) TestGetCourse() {
 							LIMIT 1`
 			s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testConditions.mockReturn).WillReturnError(testConditions.mockReturnErr)
 
-			actualReturn, err := s.realCourseService.GetCourse(testConditions.mockId)
+			actualReturn, err := s.realCourseService.GetCourse(context.Background(), testConditions.mockId)
 			assert.Equal(t, testConditions.expectedErr, err, testName)
 			assert.Equal(t, testConditions.expectedReturn, actualReturn, testName)
 			err = s.dbMock.ExpectationsWereMet()
@@ -147,7 +150,7 @@ func (s *testSuit) TestUpdateCourse() {
 			inputID:        9,
 			inputCourse:    courseInput,
 			expectedReturn: models.Course{},
-			expectedErr:    fmt.Errorf("course not found"),
+			expectedErr:    ErrCourseNotFound,
 		},
 		"Success": {
 			mockInputArgs:  []driver.Value{courseInput.Name, 0},
@@ -167,7 +170,7 @@ func (s *testSuit) TestUpdateCourse() {
 						WHERE "id" = $2`
 			s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(testConditions.mockInputArgs...).WillReturnResult(testConditions.mockReturn).WillReturnError(testConditions.mockReturnErr)
 
-			actualReturn, err := s.realCourseService.UpdateCourse(testConditions.inputID, testConditions.inputCourse)
+			actualReturn, err := s.realCourseService.UpdateCourse(context.Background(), testConditions.inputID, testConditions.inputCourse)
 			assert.Equal(t, testConditions.expectedErr, err, testName)
 			assert.Equal(t, testConditions.expectedReturn, actualReturn, testName)
 			err = s.dbMock.ExpectationsWereMet()
@@ -189,7 +192,7 @@ func (s *testSuit) TestDeleteCourseSuccess() {
 	s.dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "course" WHERE "id" = $1`)).WithArgs(courseID).WillReturnResult(sqlmock.NewResult(1, 1)).WillReturnError(nil)
 	s.dbMock.ExpectCommit()
 
-	rowsAffected, err := s.realCourseService.DeleteCourse(courseID)
+	rowsAffected, err := s.realCourseService.DeleteCourse(context.Background(), courseID)
 	assert.NoError(t, err)
 	assert.Equal(t, rowsAffected, int64(1))
 
@@ -201,7 +204,7 @@ func (s *testSuit) TestDeleteCourseTransactionFailure() {
 
 	s.dbMock.ExpectBegin().WillReturnError(errors.New("transaction begin error"))
 
-	rowsAffected, err := s.realCourseService.DeleteCourse(1)
+	rowsAffected, err := s.realCourseService.DeleteCourse(context.Background(), 1)
 	assert.Equal(t, err, fmt.Errorf("failed to begin transaction: %w", errors.New("transaction begin error")))
 	assert.Equal(t, int64(-1), rowsAffected)
 
@@ -217,13 +220,36 @@ func (s *testSuit) TestDeleteCourseDeleteRelationsFailure() {
 	s.dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "person_course" WHERE "course_id" = $1`)).WithArgs(courseID).WillReturnResult(sqlmock.NewResult(1, 5)).WillReturnError(errors.New("can't delete relations"))
 	s.dbMock.ExpectRollback()
 
-	rowsAffected, err := s.realCourseService.DeleteCourse(courseID)
+	rowsAffected, err := s.realCourseService.DeleteCourse(context.Background(), courseID)
 	assert.Equal(t, err, fmt.Errorf("failed to delete course relations: %w", errors.New("can't delete relations")))
 	assert.Equal(t, int64(-1), rowsAffected)
 
 	err = s.dbMock.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
+func (s *testSuit) TestDeleteCourseRollbackFailure() {
+	t := s.T()
+
+	// a failed rollback is logged with the request's logger, while the caller still gets the error that caused it
+	courseID := 1
+	var out bytes.Buffer
+	logger, err := logging.New(&out, logging.FormatJSON, "info")
+	assert.NoError(t, err)
+	ctx := logging.WithLogger(context.Background(), logger.With("request_id", "req-1"))
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "person_course" WHERE "course_id" = $1`)).WithArgs(courseID).WillReturnError(errors.New("can't delete relations"))
+	s.dbMock.ExpectRollback().WillReturnError(errors.New("connection lost"))
+
+	_, err = s.realCourseService.DeleteCourse(ctx, courseID)
+	assert.Equal(t, err, fmt.Errorf("failed to delete course relations: %w", errors.New("can't delete relations")))
+	assert.Contains(t, out.String(), `"msg":"failed to roll back transaction"`)
+	assert.Contains(t, out.String(), `"request_id":"req-1"`)
+	assert.Contains(t, out.String(), `"error":"connection lost"`)
+
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
 func (s *testSuit) TestDeleteCourseFailure() {
 	t := s.T()
 
@@ -235,7 +261,7 @@ func (s *testSuit) TestDeleteCourseFailure() {
 
 	s.dbMock.ExpectRollback()
 
-	rowsAffected, err := s.realCourseService.DeleteCourse(courseID)
+	rowsAffected, err := s.realCourseService.DeleteCourse(context.Background(), courseID)
 	assert.Equal(t, err, fmt.Errorf("failed to delete course with ID: %v. %w", courseID, errors.New("can't delete course")))
 	assert.Equal(t, int64(-1), rowsAffected)
 
@@ -252,7 +278,7 @@ func (s *testSuit) TestDeleteCourseCommitTransactionFailure() {
 	s.dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "course" WHERE "id" = $1`)).WithArgs(courseID).WillReturnResult(sqlmock.NewResult(int64(courseID), 1)).WillReturnError(nil)
 	s.dbMock.ExpectCommit().WillReturnError(errors.New("can't commit"))
 
-	rowsAffected, err := s.realCourseService.DeleteCourse(courseID)
+	rowsAffected, err := s.realCourseService.DeleteCourse(context.Background(), courseID)
 
 	assert.Equal(t, err, fmt.Errorf("failed to commit transaction: %w", errors.New("can't commit")))
 	assert.Equal(t, int64(-1), rowsAffected)
@@ -272,7 +298,7 @@ func (s *testSuit) TestCreateCourseSuccess() {
 		WithArgs(insertCourse.Name).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedReturnCourseID))
 
-	returnedCourse, err := s.realCourseService.CreateCourse(insertCourse)
+	returnedCourse, err := s.realCourseService.CreateCourse(context.Background(), insertCourse)
 
 	assert.Equal(t, expectedReturnCourseID, returnedCourse)
 	assert.NoError(t, err)
@@ -293,7 +319,7 @@ func (s *testSuit) TestCreateCourseFailure() {
 		WithArgs(insertCourse.Name).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1)).WillReturnError(errors.New("can't create course"))
 
-	returnedCourse, err := s.realCourseService.CreateCourse(insertCourse)
+	returnedCourse, err := s.realCourseService.CreateCourse(context.Background(), insertCourse)
 
 	assert.Equal(t, expectedReturnCourseID, returnedCourse)
 	assert.Equal(t, expectedError, err)
@@ -301,3 +327,68 @@ func (s *testSuit) TestCreateCourseFailure() {
 	err = s.dbMock.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
+
+// Tests for GetCourseRoster()
+// RosterSuccess
+// RosterTypePaginatedSuccess
+// RosterCourseNotFound
+func (s *testSuit) TestGetCourseRosterSuccess() {
+	t := s.T()
+
+	people := []PersonDTO{
+		{ID: 1, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "2"},
+		{ID: 4, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 48, Courses: "1,2"},
+	}
+	query := `SELECT * FROM "course" WHERE "id" = $1 LIMIT 1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnRows(testutil.MustStructsToRows([]models.Course{{ID: 2, Name: "Databases"}}))
+	query = `SELECT * FROM (SELECT ` + personColumns + ` FROM "person" JOIN "person_course" ON "person_course"."person_id" = "person"."id" WHERE "person_course"."course_id" = $1) AS page ORDER BY page.id`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnRows(testutil.MustStructsToRows(people))
+
+	roster, pageInfo, err := s.realCourseService.GetCourseRoster(context.Background(), 2, "", ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Roster{
+		Course:     models.Course{ID: 2, Name: "Databases"},
+		Professors: []models.Person{{ID: 4, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 48, Courses: []int{1, 2}}},
+		Students:   []models.Person{{ID: 1, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{2}}},
+	}, roster)
+	assert.Equal(t, PageInfo{Total: -1}, pageInfo)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestGetCourseRosterTypePaginatedSuccess() {
+	t := s.T()
+
+	people := []PersonDTO{
+		{ID: 1, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "2"},
+		{ID: 3, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: "2,3"},
+	}
+	query := `SELECT * FROM "course" WHERE "id" = $1 LIMIT 1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnRows(testutil.MustStructsToRows([]models.Course{{ID: 2, Name: "Databases"}}))
+	query = `SELECT COUNT(*) FROM (SELECT ` + personColumns + ` FROM "person" JOIN "person_course" ON "person_course"."person_id" = "person"."id" WHERE "person_course"."course_id" = $1 AND "person"."type" = $2) AS filtered`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, "student").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	query = `AS page ORDER BY page.id LIMIT $3`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, "student", 2).WillReturnRows(testutil.MustStructsToRows(people))
+
+	roster, pageInfo, err := s.realCourseService.GetCourseRoster(context.Background(), 2, "student", ListOptions{Limit: 1, IncludeTotal: true})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Roster{
+		Course:     models.Course{ID: 2, Name: "Databases"},
+		Professors: []models.Person{},
+		Students:   []models.Person{{ID: 1, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{2}}},
+	}, roster)
+	assert.Equal(t, PageInfo{NextCursor: encodeCursor(cursor{Keys: []any{1}}), Total: 5}, pageInfo)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestGetCourseRosterCourseNotFound() {
+	t := s.T()
+
+	query := `SELECT * FROM "course" WHERE "id" = $1 LIMIT 1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnRows(&sqlmock.Rows{})
+
+	roster, _, err := s.realCourseService.GetCourseRoster(context.Background(), 2, "", ListOptions{})
+	assert.Equal(t, ErrCourseNotFound, err)
+	assert.Equal(t, models.Roster{}, roster)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
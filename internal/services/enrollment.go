@@ -0,0 +1,114 @@
+package services
+
+//enrollment.go defines the service functions used by RealEnrollmentService structs to manage the person_course table directly, and an EnrollmentService interface for testing.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"tech-challenge/internal/models"
+)
+
+type EnrollmentService interface {
+	GetCoursesForPerson(context.Context, int) ([]models.Course, error)
+	Enroll(context.Context, int, int) (bool, error)
+	Drop(context.Context, int, int) error
+}
+
+type RealEnrollmentService struct {
+	db *sql.DB
+}
+
+func NewEnrollmentService(db *sql.DB) *RealEnrollmentService {
+	return &RealEnrollmentService{
+		db: db,
+	}
+}
+
+// GetCoursesForPerson returns every course the person with personID is enrolled in, ordered by id.
+func (e *RealEnrollmentService) GetCoursesForPerson(ctx context.Context, personID int) ([]models.Course, error) {
+	var personExists bool
+	err := e.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM "person" WHERE "id" = $1)`, personID).Scan(&personExists)
+	if err != nil {
+		return []models.Course{}, fmt.Errorf("failed to get person: %w", err)
+	}
+	if !personExists {
+		return []models.Course{}, ErrPersonNotFound
+	}
+
+	rows, err := e.db.QueryContext(ctx, `SELECT "course"."id", "course"."name" FROM "course"
+							JOIN "person_course" ON "person_course"."course_id" = "course"."id"
+							WHERE "person_course"."person_id" = $1
+							ORDER BY "course"."id"`,
+		personID)
+	if err != nil {
+		return []models.Course{}, fmt.Errorf("failed to get courses: %w", err)
+	}
+	defer rows.Close()
+
+	courses := make([]models.Course, 0)
+	for rows.Next() {
+		var course models.Course
+		if err = rows.Scan(&course.ID, &course.Name); err != nil {
+			return []models.Course{}, fmt.Errorf("failed to scan course from row: %w", err)
+		}
+		courses = append(courses, course)
+	}
+	if err = rows.Err(); err != nil {
+		return []models.Course{}, fmt.Errorf("failed to scan courses: %w", err)
+	}
+	return courses, nil
+}
+
+// Enroll adds the person with personID to the course with courseID. It returns false if they were already enrolled.
+func (e *RealEnrollmentService) Enroll(ctx context.Context, personID int, courseID int) (bool, error) {
+	var personExists, courseExists bool
+	err := e.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM "person" WHERE "id" = $1),
+							EXISTS (SELECT 1 FROM "course" WHERE "id" = $2)`,
+		personID,
+		courseID).Scan(&personExists, &courseExists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check enrollment: %w", err)
+	}
+	if !personExists {
+		return false, ErrPersonNotFound
+	}
+	if !courseExists {
+		return false, ErrCourseNotFound
+	}
+
+	result, err := e.db.ExecContext(ctx, `INSERT INTO "person_course" (person_id, course_id)
+							VALUES ($1, $2)
+							ON CONFLICT DO NOTHING`,
+		personID,
+		courseID)
+	if err != nil {
+		return false, fmt.Errorf("failed to enroll person: %w", mapDBError(err))
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get the number of affected rows: %v", err)
+	}
+	return rowsAffected == 1, nil
+}
+
+// Drop removes the person with personID from the course with courseID. It returns ErrEnrollmentNotFound if they were
+// not enrolled.
+func (e *RealEnrollmentService) Drop(ctx context.Context, personID int, courseID int) error {
+	result, err := e.db.ExecContext(ctx, `DELETE FROM "person_course"
+							WHERE person_id = $1
+							AND course_id = $2`,
+		personID,
+		courseID)
+	if err != nil {
+		return fmt.Errorf("failed to drop course: %w", mapDBError(err))
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get the number of affected rows: %v", err)
+	}
+	if rowsAffected == 0 {
+		return ErrEnrollmentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package services
+
+//enrollment_test.go tests ./enrollment.go.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/testutil"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *testSuit) TestGetCoursesForPerson() {
+	t := s.T()
+
+	courses := []models.Course{
+		{ID: 1, Name: "Programming"},
+		{ID: 3, Name: "Databases"},
+	}
+	testCases := map[string]struct {
+		personExists   bool
+		mockReturn     *sqlmock.Rows
+		mockReturnErr  error
+		expectedReturn []models.Course
+		expectedErr    error
+	}{
+		"GetSuccess": {
+			personExists:   true,
+			mockReturn:     testutil.MustStructsToRows(courses),
+			expectedReturn: courses,
+		},
+		"NoCoursesSuccess": {
+			personExists:   true,
+			mockReturn:     &sqlmock.Rows{},
+			expectedReturn: []models.Course{},
+		},
+		"PersonNotFound": {
+			personExists:   false,
+			expectedReturn: []models.Course{},
+			expectedErr:    ErrPersonNotFound,
+		},
+		"QueryError": {
+			personExists:   true,
+			mockReturn:     &sqlmock.Rows{},
+			mockReturnErr:  errors.New("can't query"),
+			expectedReturn: []models.Course{},
+			expectedErr:    fmt.Errorf("failed to get courses: %w", errors.New("can't query")),
+		},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			query := `SELECT EXISTS (SELECT 1 FROM "person" WHERE "id" = $1)`
+			s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(testConditions.personExists))
+			if testConditions.personExists {
+				query = `SELECT "course"."id", "course"."name" FROM "course" JOIN "person_course" ON "person_course"."course_id" = "course"."id" WHERE "person_course"."person_id" = $1 ORDER BY "course"."id"`
+				s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnRows(testConditions.mockReturn).WillReturnError(testConditions.mockReturnErr)
+			}
+
+			actualReturn, err := s.enrollmentService.GetCoursesForPerson(context.Background(), 2)
+			assert.Equal(t, testConditions.expectedErr, err)
+			assert.Equal(t, testConditions.expectedReturn, actualReturn)
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+func (s *testSuit) TestEnroll() {
+	t := s.T()
+
+	testCases := map[string]struct {
+		personExists   bool
+		courseExists   bool
+		rowsAffected   int64
+		expectedReturn bool
+		expectedErr    error
+	}{
+		"EnrollSuccess":          {personExists: true, courseExists: true, rowsAffected: 1, expectedReturn: true},
+		"AlreadyEnrolledSuccess": {personExists: true, courseExists: true, rowsAffected: 0, expectedReturn: false},
+		"PersonNotFound":         {personExists: false, courseExists: true, expectedErr: ErrPersonNotFound},
+		"CourseNotFound":         {personExists: true, courseExists: false, expectedErr: ErrCourseNotFound},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			query := `SELECT EXISTS (SELECT 1 FROM "person" WHERE "id" = $1), EXISTS (SELECT 1 FROM "course" WHERE "id" = $2)`
+			s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).
+				WillReturnRows(sqlmock.NewRows([]string{"person", "course"}).AddRow(testConditions.personExists, testConditions.courseExists))
+			if testConditions.personExists && testConditions.courseExists {
+				query = `INSERT INTO "person_course" (person_id, course_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+				s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnResult(sqlmock.NewResult(0, testConditions.rowsAffected))
+			}
+
+			created, err := s.enrollmentService.Enroll(context.Background(), 2, 5)
+			assert.Equal(t, testConditions.expectedErr, err)
+			assert.Equal(t, testConditions.expectedReturn, created)
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+func (s *testSuit) TestDrop() {
+	t := s.T()
+
+	testCases := map[string]struct {
+		rowsAffected  int64
+		mockReturnErr error
+		expectedErr   error
+	}{
+		"DropSuccess":        {rowsAffected: 1},
+		"EnrollmentNotFound": {rowsAffected: 0, expectedErr: ErrEnrollmentNotFound},
+		"DeleteQueryFailure": {mockReturnErr: errors.New("can't delete"), expectedErr: fmt.Errorf("failed to drop course: %w", errors.New("can't delete"))},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			query := `DELETE FROM "person_course" WHERE person_id = $1 AND course_id = $2`
+			s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(2, 5).
+				WillReturnResult(sqlmock.NewResult(0, testConditions.rowsAffected)).WillReturnError(testConditions.mockReturnErr)
+
+			err := s.enrollmentService.Drop(context.Background(), 2, 5)
+			assert.Equal(t, testConditions.expectedErr, err)
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
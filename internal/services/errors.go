@@ -0,0 +1,117 @@
+package services
+
+//errors.go defines the errors returned by the service functions. Callers should match them with errors.Is and errors.As rather than comparing messages.
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Kinds of failure a service function can report. Every error below wraps exactly one of these.
+var (
+	// ErrNotFound means the entity being read, updated or deleted does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means the change collides with existing data, such as a duplicate key.
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidReference means the change points at another entity that does not exist.
+	ErrInvalidReference = errors.New("invalid reference")
+	// ErrConstraintViolation means the change breaks a rule enforced by the database, such as a check constraint.
+	ErrConstraintViolation = errors.New("constraint violation")
+	// ErrInvalidInput means an argument was rejected before reaching the database, such as a malformed cursor.
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+var (
+	ErrPersonNotFound     error = &serviceError{msg: "person not found", kind: ErrNotFound}
+	ErrCourseNotFound     error = &serviceError{msg: "course not found", kind: ErrNotFound}
+	ErrUnknownCourse      error = &serviceError{msg: "course not found, trying to join a course that doesn't exist", kind: ErrInvalidReference}
+	ErrEnrollmentNotFound error = &serviceError{msg: "person is not enrolled in course", kind: ErrNotFound}
+	ErrInvalidCursor      error = &serviceError{msg: "invalid cursor", kind: ErrInvalidInput}
+	ErrInvalidSort        error = &serviceError{msg: "invalid sort, unknown or repeated field", kind: ErrInvalidInput}
+	ErrAPIKeyNotFound     error = &serviceError{msg: "API key not found", kind: ErrNotFound}
+	ErrInvalidAPIKey      error = &serviceError{msg: "API key is malformed, unknown or revoked", kind: ErrInvalidInput}
+)
+
+// serviceError is an error with its own message that still matches its kind with errors.Is.
+type serviceError struct {
+	msg  string
+	kind error
+}
+
+func (e *serviceError) Error() string {
+	return e.msg
+}
+func (e *serviceError) Unwrap() error {
+	return e.kind
+}
+
+// ConstraintError is returned when the database rejects a statement because it violates an integrity constraint.
+// It matches ErrConflict, ErrInvalidReference or ErrConstraintViolation with errors.Is, and the underlying
+// *pq.Error or *sqlite.Error with errors.As. SQLite does not name the violated constraint, so Constraint is empty
+// for it.
+type ConstraintError struct {
+	Kind       error
+	Constraint string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+func (e *ConstraintError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// mapDBError converts integrity constraint violations reported by Postgres or SQLite into a *ConstraintError.
+// Any other error is returned unchanged.
+func mapDBError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		var kind error
+		switch pqErr.Code.Name() {
+		case "unique_violation", "exclusion_violation":
+			kind = ErrConflict
+		case "foreign_key_violation":
+			kind = ErrInvalidReference
+		case "check_violation", "not_null_violation", "restrict_violation":
+			kind = ErrConstraintViolation
+		default:
+			return err
+		}
+		return &ConstraintError{Kind: kind, Constraint: pqErr.Constraint, Err: pqErr}
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		var kind error
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			kind = ErrConflict
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			kind = ErrInvalidReference
+		case sqlite3.SQLITE_CONSTRAINT_CHECK, sqlite3.SQLITE_CONSTRAINT_NOTNULL:
+			kind = ErrConstraintViolation
+		default:
+			return err
+		}
+		return &ConstraintError{Kind: kind, Err: sqliteErr}
+	}
+	return err
+}
+
+// ClientMessage returns a description of err that is safe to show to API clients, and whether one was found.
+// Only the messages of this package's own errors qualify. Database errors and wrapping context never do.
+func ClientMessage(err error) (string, bool) {
+	var svcErr *serviceError
+	if errors.As(err, &svcErr) {
+		return svcErr.msg, true
+	}
+	var constraintErr *ConstraintError
+	if errors.As(err, &constraintErr) {
+		return constraintErr.Kind.Error(), true
+	}
+	return "", false
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"modernc.org/sqlite"
+)
+
+func TestMapDBError(t *testing.T) {
+	testCases := map[string]struct {
+		input        error
+		expectedKind error
+	}{
+		"unique violation": {
+			input:        &pq.Error{Code: "23505", Constraint: "person_course_pkey"},
+			expectedKind: ErrConflict,
+		},
+		"foreign key violation": {
+			input:        &pq.Error{Code: "23503", Constraint: "person_course_course_id_fkey"},
+			expectedKind: ErrInvalidReference,
+		},
+		"check violation": {
+			input:        &pq.Error{Code: "23514", Constraint: "person_type_check"},
+			expectedKind: ErrConstraintViolation,
+		},
+		"wrapped foreign key violation": {
+			input:        fmt.Errorf("failed to update course list: %w", &pq.Error{Code: "23503"}),
+			expectedKind: ErrInvalidReference,
+		},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			err := mapDBError(testConditions.input)
+
+			var constraintErr *ConstraintError
+			assert.True(t, errors.As(err, &constraintErr))
+			assert.ErrorIs(t, err, testConditions.expectedKind)
+
+			var pqErr *pq.Error
+			assert.True(t, errors.As(err, &pqErr))
+		})
+	}
+}
+func TestMapDBErrorSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:?_pragma=foreign_keys(1)")
+	assert.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`CREATE TABLE course (id INTEGER PRIMARY KEY);
+		CREATE TABLE person (id INTEGER PRIMARY KEY, type TEXT CHECK (type IN ('professor', 'student')));
+		CREATE TABLE person_course (course_id INTEGER REFERENCES course (id));
+		INSERT INTO course (id) VALUES (1);`)
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		statement    string
+		expectedKind error
+	}{
+		"unique violation":      {statement: `INSERT INTO course (id) VALUES (1)`, expectedKind: ErrConflict},
+		"foreign key violation": {statement: `INSERT INTO person_course (course_id) VALUES (2)`, expectedKind: ErrInvalidReference},
+		"check violation":       {statement: `INSERT INTO person (type) VALUES ('teacher')`, expectedKind: ErrConstraintViolation},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			_, err := db.Exec(testConditions.statement)
+			err = mapDBError(err)
+
+			var constraintErr *ConstraintError
+			assert.True(t, errors.As(err, &constraintErr))
+			assert.ErrorIs(t, err, testConditions.expectedKind)
+
+			var sqliteErr *sqlite.Error
+			assert.True(t, errors.As(err, &sqliteErr))
+		})
+	}
+}
+func TestMapDBErrorPassthrough(t *testing.T) {
+	testCases := map[string]error{
+		"plain error":         errors.New("connection refused"),
+		"syntax error":        &pq.Error{Code: "42601"},
+		"already mapped kind": ErrPersonNotFound,
+	}
+	for testName, input := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, input, mapDBError(input))
+		})
+	}
+}
+func TestSentinelErrorKinds(t *testing.T) {
+	assert.ErrorIs(t, ErrPersonNotFound, ErrNotFound)
+	assert.ErrorIs(t, ErrCourseNotFound, ErrNotFound)
+	assert.ErrorIs(t, fmt.Errorf("wrapped: %w", ErrUnknownCourse), ErrInvalidReference)
+	assert.Equal(t, "person not found", ErrPersonNotFound.Error())
+}
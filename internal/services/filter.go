@@ -0,0 +1,132 @@
+package services
+
+//filter.go defines PersonFilter, which selects the people returned by GetAllPeople() in ./person.go.
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"tech-challenge/internal/models"
+)
+
+// NameMatch is how PersonFilter compares FirstName and LastName against the stored names. Comparisons ignore case.
+type NameMatch string
+
+const (
+	NameExact    NameMatch = "exact"
+	NamePrefix   NameMatch = "prefix"
+	NameContains NameMatch = "contains"
+)
+
+// PersonFilter selects people by any combination of its fields. Zero-valued fields do not filter, so PersonFilter{}
+// selects everyone.
+type PersonFilter struct {
+	FirstName string
+	LastName  string
+	// NameMatch applies to both FirstName and LastName. The zero value is NameExact.
+	NameMatch NameMatch
+	Type      string
+	Age       *int
+	MinAge    *int
+	MaxAge    *int
+	// CourseIDs selects people enrolled in at least one of the listed courses.
+	CourseIDs []int
+}
+
+// query returns a SELECT of personColumns for the rows matching f, and its arguments.
+func (f PersonFilter) query() (string, []any) {
+	var conditions []string
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if f.FirstName != "" {
+		conditions = append(conditions, f.nameCondition("first_name", f.FirstName, arg))
+	}
+	if f.LastName != "" {
+		conditions = append(conditions, f.nameCondition("last_name", f.LastName, arg))
+	}
+	if f.Type != "" {
+		conditions = append(conditions, `type = `+arg(f.Type))
+	}
+	if f.Age != nil {
+		conditions = append(conditions, `age = `+arg(*f.Age))
+	}
+	if f.MinAge != nil {
+		conditions = append(conditions, `age >= `+arg(*f.MinAge))
+	}
+	if f.MaxAge != nil {
+		conditions = append(conditions, `age <= `+arg(*f.MaxAge))
+	}
+	if len(f.CourseIDs) > 0 {
+		var courseList string
+		args, courseList = placeholders(args, f.CourseIDs)
+		conditions = append(conditions, `id IN (SELECT person_id FROM "person_course" WHERE course_id IN `+courseList+`)`)
+	}
+
+	query := `SELECT ` + personColumns + ` FROM "person"`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	return query, args
+}
+
+// nameCondition compares column with name according to f.NameMatch.
+func (f PersonFilter) nameCondition(column string, name string, arg func(any) string) string {
+	switch f.NameMatch {
+	case NamePrefix:
+		return `LOWER(` + column + `) LIKE LOWER(` + arg(escapeLike(name)+"%") + `) ESCAPE '\'`
+	case NameContains:
+		return `LOWER(` + column + `) LIKE LOWER(` + arg("%"+escapeLike(name)+"%") + `) ESCAPE '\'`
+	default:
+		return `LOWER(` + column + `) = LOWER(` + arg(name) + `)`
+	}
+}
+
+// matches reports whether person is selected by f, comparing as query does. It is used by the in-memory services in
+// ./memory.go.
+func (f PersonFilter) matches(person models.Person) bool {
+	if f.FirstName != "" && !f.nameMatches(person.FirstName, f.FirstName) {
+		return false
+	}
+	if f.LastName != "" && !f.nameMatches(person.LastName, f.LastName) {
+		return false
+	}
+	if f.Type != "" && person.Type != f.Type {
+		return false
+	}
+	if f.Age != nil && person.Age != *f.Age {
+		return false
+	}
+	if f.MinAge != nil && person.Age < *f.MinAge {
+		return false
+	}
+	if f.MaxAge != nil && person.Age > *f.MaxAge {
+		return false
+	}
+	if len(f.CourseIDs) > 0 && !slices.ContainsFunc(person.Courses, func(id int) bool { return slices.Contains(f.CourseIDs, id) }) {
+		return false
+	}
+	return true
+}
+
+// nameMatches compares a stored name with name according to f.NameMatch, ignoring case.
+func (f PersonFilter) nameMatches(stored string, name string) bool {
+	stored, name = strings.ToLower(stored), strings.ToLower(name)
+	switch f.NameMatch {
+	case NamePrefix:
+		return strings.HasPrefix(stored, name)
+	case NameContains:
+		return strings.Contains(stored, name)
+	default:
+		return stored == name
+	}
+}
+
+// escapeLike escapes the LIKE wildcards in s with backslashes so they match literally. The queries name the backslash
+// in an ESCAPE clause, since SQLite has no default escape character.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
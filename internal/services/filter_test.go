@@ -0,0 +1,58 @@
+package services
+
+//filter_test.go tests ./filter.go.
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonFilterQuery(t *testing.T) {
+	age := 22
+	minAge := 18
+	maxAge := 30
+	testCases := map[string]struct {
+		filter        PersonFilter
+		expectedQuery string
+		expectedArgs  []any
+	}{
+		"everyone": {
+			filter:        PersonFilter{},
+			expectedQuery: `SELECT ` + personColumns + ` FROM "person"`,
+			expectedArgs:  nil,
+		},
+		"full name and age": {
+			filter:        PersonFilter{FirstName: "Bubbles", LastName: "Thane", Age: &age},
+			expectedQuery: `SELECT ` + personColumns + ` FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) AND age = $3`,
+			expectedArgs:  []any{"Bubbles", "Thane", 22},
+		},
+		"last name prefix": {
+			filter:        PersonFilter{LastName: "Th", NameMatch: NamePrefix},
+			expectedQuery: `SELECT ` + personColumns + ` FROM "person" WHERE LOWER(last_name) LIKE LOWER($1) ESCAPE '\'`,
+			expectedArgs:  []any{"Th%"},
+		},
+		"first name contains with wildcards": {
+			filter:        PersonFilter{FirstName: "50%_off", NameMatch: NameContains},
+			expectedQuery: `SELECT ` + personColumns + ` FROM "person" WHERE LOWER(first_name) LIKE LOWER($1) ESCAPE '\'`,
+			expectedArgs:  []any{`%50\%\_off%`},
+		},
+		"type and age range": {
+			filter:        PersonFilter{Type: "student", MinAge: &minAge, MaxAge: &maxAge},
+			expectedQuery: `SELECT ` + personColumns + ` FROM "person" WHERE type = $1 AND age >= $2 AND age <= $3`,
+			expectedArgs:  []any{"student", 18, 30},
+		},
+		"courses": {
+			filter:        PersonFilter{Type: "professor", CourseIDs: []int{1, 3}},
+			expectedQuery: `SELECT ` + personColumns + ` FROM "person" WHERE type = $1 AND id IN (SELECT person_id FROM "person_course" WHERE course_id IN ($2, $3))`,
+			expectedArgs:  []any{"professor", 1, 3},
+		},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			query, args := testConditions.filter.query()
+			assert.Equal(t, testConditions.expectedQuery, query)
+			assert.Equal(t, testConditions.expectedArgs, args)
+		})
+	}
+}
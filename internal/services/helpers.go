@@ -2,34 +2,160 @@ package services
 
 //helpers.go defines helper functions used by ./course.go and ./person.go.
 
-import "database/sql"
-
-func dbQueryGetPeopleByName(firstName string, lastName string, db *sql.DB) (*sql.Rows, error) {
-	return db.Query(`SELECT * FROM "person" 
-					WHERE LOWER(first_name) = LOWER($1)
-					AND LOWER(last_name) = LOWER($2)`,
-		firstName,
-		lastName)
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"tech-challenge/internal/logging"
+	"tech-challenge/internal/models"
+)
+
+// personColumns selects every column of "person" followed by the ids of the person's courses, in ascending order, as
+// a comma separated list. It lets a single query return whole models.Person values instead of one extra query per
+// person. string_agg with ORDER BY works in both Postgres and SQLite, so the same query serves both databases.
+const personColumns = `"person".*, (SELECT string_agg(CAST(pc.course_id AS TEXT), ',' ORDER BY pc.course_id)
+					FROM "person_course" AS pc
+					WHERE pc.person_id = "person"."id") AS courses`
+
+// scanPerson scans a row selected with personColumns.
+func scanPerson(rows *sql.Rows) (models.Person, error) {
+	var person models.Person
+	var courseIDs sql.NullString
+	err := rows.Scan(&person.ID,
+		&person.FirstName,
+		&person.LastName,
+		&person.Type,
+		&person.Age,
+		&courseIDs,
+	)
+	if err != nil {
+		return models.Person{}, err
+	}
+	person.Courses = make([]int, 0)
+	if courseIDs.String == "" {
+		return person, nil
+	}
+	for _, courseID := range strings.Split(courseIDs.String, ",") {
+		id, err := strconv.Atoi(courseID)
+		if err != nil {
+			return models.Person{}, fmt.Errorf("invalid course id %q: %w", courseID, err)
+		}
+		person.Courses = append(person.Courses, id)
+	}
+	return person, nil
+}
+
+// placeholders appends values to args and returns their placeholders as a parenthesised list, such as ($2, $3), for
+// use with IN. It stands in for Postgres' ANY, which SQLite lacks.
+func placeholders(args []any, values []int) ([]any, string) {
+	list := make([]string, len(values))
+	for i, value := range values {
+		args = append(args, value)
+		list[i] = `$` + strconv.Itoa(len(args))
+	}
+	return args, `(` + strings.Join(list, `, `) + `)`
 }
-func dbQueryGetPeopleByNameAndAge(firstName string, lastName string, age int, db *sql.DB) (*sql.Rows, error) {
-	return db.Query(`SELECT * FROM "person" 
-					WHERE LOWER(first_name) = LOWER($1)
-					AND LOWER(last_name) = LOWER($2)
-					AND age = $3`,
-		firstName,
-		lastName,
-		age)
+
+// updatePersonCourses makes the person_course rows of personID match courses inside of tx. Rows for courses no longer
+// in the list are deleted, and rows for new courses are inserted after checking the courses exist.
+func updatePersonCourses(ctx context.Context, tx *sql.Tx, personID int, courses []int) error {
+	//1. use ID to do select of courses from person_course
+	rows, err := tx.QueryContext(ctx, `SELECT * FROM "person_course"
+						WHERE person_id = $1`,
+		personID)
+	if err != nil {
+		return fmt.Errorf("failed to retreive course list: %w", err)
+	}
+	//2. form an array of all classes they're currently in
+	currentCourses := make([]int, 0)
+	for rows.Next() {
+		var pID int
+		var cID int
+		rows.Scan(&pID, &cID)
+		currentCourses = append(currentCourses, cID)
+	}
+	rows.Close()
+	//3. do a delete query on the ones not in the new person course list
+	coursesToDelete := getDifference(currentCourses, courses)
+	if len(coursesToDelete) > 0 {
+		args, courseList := placeholders([]any{personID}, coursesToDelete)
+		_, err = tx.ExecContext(ctx, `DELETE FROM "person_course" 
+		WHERE person_id = $1 
+		AND course_id IN `+courseList,
+			args...)
+
+		if err != nil {
+			return fmt.Errorf("failed to update course list: %w", mapDBError(err))
+		}
+	}
+	//4. Validate the courses they want to be added to actually exist
+	coursesToInsert := getDifference(courses, currentCourses)
+
+	rows, err = tx.QueryContext(ctx, `SELECT id FROM "course"`)
+	if err != nil {
+		return fmt.Errorf("failed to retreive course list: %w", err)
+	}
+
+	courseIDs := make(map[int]bool)
+	for rows.Next() {
+		var courseID int
+		rows.Scan(&courseID)
+		courseIDs[courseID] = true
+	}
+	rows.Close()
+	for _, val := range coursesToInsert {
+		if !courseIDs[val] {
+			return ErrUnknownCourse
+		}
+	}
+	//5. do an insert query on the ones not currently in the table
+	var sb strings.Builder
+	if len(coursesToInsert) > 0 {
+		sb.WriteString("(" + strconv.Itoa(personID) + ", " + strconv.Itoa(coursesToInsert[0]) + ")")
+		for i := 1; i < len(coursesToInsert); i++ {
+			sb.WriteString(", (" + strconv.Itoa(personID) + ", " + strconv.Itoa(coursesToInsert[i]) + ")")
+		}
+	}
+	if sb.String() != "" {
+		query := `INSERT INTO "person_course" (person_id, course_id) VALUES ` + sb.String()
+		_, err = tx.ExecContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to update course list: %w", mapDBError(err))
+		}
+	}
+	return nil
 }
-func dbQueryGetPeopleByAge(age int, db *sql.DB) (*sql.Rows, error) {
-	return db.Query(`SELECT * FROM "person" 
-					WHERE age = $1`,
-		age)
+
+// returns the value of person in one of the PersonSortFields.
+func personSortValue(person models.Person, field string) any {
+	switch field {
+	case "first_name":
+		return person.FirstName
+	case "last_name":
+		return person.LastName
+	case "type":
+		return person.Type
+	case "age":
+		return person.Age
+	default:
+		return person.ID
+	}
 }
-func dbQueryGetPeople(db *sql.DB) (*sql.Rows, error) {
-	return db.Query(`SELECT * FROM "person"`)
+
+// returns the value of course in one of the CourseSortFields.
+func courseSortValue(course models.Course, field string) any {
+	if field == "name" {
+		return course.Name
+	}
+	return course.ID
 }
 
-// returns an []int of values that are in old, but not in new. New may contain values not in old. it is assumed items in old are unique
+// returns an []int of values that are in old, but not in new, in ascending order. New may contain values not in old. it is assumed items in old are unique
 func getDifference(old []int, new []int) []int {
 	//1. we increment all values in old as keys to a map[int][int] with a value of 2.
 	//2. we add all values in new to the map with a value of 1
@@ -47,5 +173,15 @@ func getDifference(old []int, new []int) []int {
 			result = append(result, key)
 		}
 	}
+	sort.Ints(result)
 	return result
 }
+
+// rollback rolls tx back after a failed statement. The statement's error is what the caller reports, so a failed
+// rollback is only logged to the request's logger. database/sql has already rolled back a transaction whose context
+// was cancelled.
+func rollback(ctx context.Context, tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "failed to roll back transaction", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,694 @@
+package services
+
+//memory.go defines MemoryStore and the in-memory PersonService, CourseService, EnrollmentService and APIKeyService built
+//on it. They behave like the Real services in ./person.go, ./course.go, ./enrollment.go and ./apikey.go, so the API can
+//run without a database.
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"tech-challenge/internal/auth"
+	"tech-challenge/internal/models"
+)
+
+// MemoryStore holds the people, courses and enrollments shared by the in-memory services. It is safe for concurrent
+// use, and every service function reads or writes it under a single lock so it sees a consistent state.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	people  map[int]models.Person
+	courses map[int]models.Course
+	// enrollments holds the ids of the courses each person id is enrolled in.
+	enrollments  map[int]map[int]bool
+	apiKeys      map[int]memoryAPIKey
+	lastPersonID int
+	lastCourseID int
+	lastAPIKeyID int
+}
+
+// memoryAPIKey is a stored API key with the salt and hash of its secret.
+type memoryAPIKey struct {
+	key  models.APIKey
+	salt string
+	hash string
+}
+
+// get returns a copy of the stored key that shares no memory with the store.
+func (k memoryAPIKey) get() models.APIKey {
+	key := k.key
+	key.Scopes = slices.Clone(key.Scopes)
+	if key.LastUsedAt != nil {
+		lastUsedAt := *key.LastUsedAt
+		key.LastUsedAt = &lastUsedAt
+	}
+	if key.RevokedAt != nil {
+		revokedAt := *key.RevokedAt
+		key.RevokedAt = &revokedAt
+	}
+	return key
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		people:      make(map[int]models.Person),
+		courses:     make(map[int]models.Course),
+		enrollments: make(map[int]map[int]bool),
+		apiKeys:     make(map[int]memoryAPIKey),
+	}
+}
+
+// read runs fn holding the read lock, unless ctx is already done.
+func (s *MemoryStore) read(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn()
+}
+
+// write runs fn holding the write lock, unless ctx is already done. fn must check everything that can fail before it
+// changes anything, since there is no transaction to roll back.
+func (s *MemoryStore) write(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn()
+}
+
+// person returns the stored person with id and their courses in ascending order.
+func (s *MemoryStore) person(id int) (models.Person, bool) {
+	person, ok := s.people[id]
+	if !ok {
+		return models.Person{}, false
+	}
+	person.Courses = slices.Sorted(maps.Keys(s.enrollments[id]))
+	if person.Courses == nil {
+		person.Courses = make([]int, 0)
+	}
+	return person, true
+}
+
+// allPeople returns every stored person ordered by id.
+func (s *MemoryStore) allPeople() []models.Person {
+	people := make([]models.Person, 0, len(s.people))
+	for _, id := range slices.Sorted(maps.Keys(s.people)) {
+		person, _ := s.person(id)
+		people = append(people, person)
+	}
+	return people
+}
+
+// idsByName returns the ids of the people named firstName lastName, ignoring case, in ascending order.
+func (s *MemoryStore) idsByName(firstName string, lastName string) []int {
+	filter := PersonFilter{FirstName: firstName, LastName: lastName}
+	var ids []int
+	for _, person := range s.allPeople() {
+		if filter.matches(person) {
+			ids = append(ids, person.ID)
+		}
+	}
+	return ids
+}
+
+// checkCourses returns ErrUnknownCourse unless every id in courses is a stored course.
+func (s *MemoryStore) checkCourses(courses []int) error {
+	for _, courseID := range courses {
+		if _, ok := s.courses[courseID]; !ok {
+			return ErrUnknownCourse
+		}
+	}
+	return nil
+}
+
+// setCourses replaces the enrollments of personID with courses, which must have passed checkCourses.
+func (s *MemoryStore) setCourses(personID int, courses []int) {
+	enrolled := make(map[int]bool, len(courses))
+	for _, courseID := range courses {
+		enrolled[courseID] = true
+	}
+	s.enrollments[personID] = enrolled
+}
+
+// deletePerson removes the person with id and their enrollments, and returns how many people it removed.
+func (s *MemoryStore) deletePerson(id int) int64 {
+	if _, ok := s.people[id]; !ok {
+		return 0
+	}
+	delete(s.people, id)
+	delete(s.enrollments, id)
+	return 1
+}
+
+// page returns the page of items selected by opts, ordered as paginate orders rows. sortValue returns the value of an
+// item in one of the sortable fields.
+func page[T any](items []T, opts ListOptions, sortable []string, sortValue func(T, string) any) ([]T, PageInfo, error) {
+	if err := checkSort(opts.Sort, sortable); err != nil {
+		return nil, PageInfo{}, err
+	}
+	keys := sortKeys(opts.Sort)
+	values := func(item T) []any {
+		v := make([]any, len(keys))
+		for i, key := range keys {
+			v[i] = sortValue(item, key.Field)
+		}
+		return v
+	}
+	slices.SortFunc(items, func(a T, b T) int {
+		order, _ := compareKeys(keys, values(a), values(b))
+		return order
+	})
+
+	pageInfo := PageInfo{Total: -1}
+	if opts.IncludeTotal {
+		pageInfo.Total = len(items)
+	}
+	if opts.Cursor != "" {
+		after, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		if err = checkCursor(after, keys); err != nil {
+			return nil, PageInfo{}, err
+		}
+		start := len(items)
+		for i, item := range items {
+			order, ok := compareKeys(keys, values(item), after.Keys)
+			if !ok {
+				return nil, PageInfo{}, ErrInvalidCursor
+			}
+			if order > 0 {
+				start = i
+				break
+			}
+		}
+		items = items[start:]
+	}
+	if opts.Limit > 0 && len(items) > opts.Limit+1 {
+		items = items[:opts.Limit+1]
+	}
+	items, pageInfo.NextCursor = trimPage(items, opts, sortValue)
+	return items, pageInfo, nil
+}
+
+// compareKeys compares two lists of sort key values in the order of keys. It returns false if a pair of values
+// cannot be compared, which only happens with values from a cursor made for another listing.
+func compareKeys(keys []SortField, a []any, b []any) (int, bool) {
+	for i, key := range keys {
+		var order int
+		switch x := a[i].(type) {
+		case int:
+			y, ok := b[i].(int)
+			if !ok {
+				return 0, false
+			}
+			order = cmp.Compare(x, y)
+		case string:
+			y, ok := b[i].(string)
+			if !ok {
+				return 0, false
+			}
+			order = strings.Compare(x, y)
+		default:
+			return 0, false
+		}
+		if key.Desc {
+			order = -order
+		}
+		if order != 0 {
+			return order, true
+		}
+	}
+	return 0, true
+}
+
+type MemoryPersonService struct {
+	store *MemoryStore
+}
+
+func NewMemoryPersonService(store *MemoryStore) *MemoryPersonService {
+	return &MemoryPersonService{
+		store: store,
+	}
+}
+func (p *MemoryPersonService) GetAllPeople(ctx context.Context, filter PersonFilter, opts ListOptions) ([]models.Person, PageInfo, error) {
+	var people []models.Person
+	var pageInfo PageInfo
+	err := p.store.read(ctx, func() error {
+		var matching []models.Person
+		for _, person := range p.store.allPeople() {
+			if filter.matches(person) {
+				matching = append(matching, person)
+			}
+		}
+		var err error
+		people, pageInfo, err = page(matching, opts, PersonSortFields, personSortValue)
+		return err
+	})
+	if err != nil {
+		return []models.Person{}, PageInfo{}, err
+	}
+	return people, pageInfo, nil
+}
+func (p *MemoryPersonService) GetPerson(ctx context.Context, firstName string, lastName string) (models.Person, error) {
+	var person models.Person
+	err := p.store.read(ctx, func() error {
+		if ids := p.store.idsByName(firstName, lastName); len(ids) > 0 {
+			person, _ = p.store.person(ids[0])
+		}
+		return nil
+	})
+	return person, err
+}
+
+// UpdatePerson overwrites every person named firstName lastName, like RealPersonService.UpdatePerson, and syncs the
+// course list of the first of them.
+func (p *MemoryPersonService) UpdatePerson(ctx context.Context, firstName string, lastName string, person models.Person) (models.Person, error) {
+	err := p.store.write(ctx, func() error {
+		ids := p.store.idsByName(firstName, lastName)
+		if len(ids) == 0 {
+			return ErrPersonNotFound
+		}
+		if err := p.store.checkCourses(person.Courses); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			p.store.people[id] = models.Person{ID: id, FirstName: person.FirstName, LastName: person.LastName, Type: person.Type, Age: person.Age}
+		}
+		person.ID = p.store.idsByName(person.FirstName, person.LastName)[0]
+		p.store.setCourses(person.ID, person.Courses)
+		return nil
+	})
+	if err != nil {
+		return models.Person{}, err
+	}
+	return person, nil
+}
+func (p *MemoryPersonService) CreatePerson(ctx context.Context, person models.Person) (int, error) {
+	var id int
+	err := p.store.write(ctx, func() error {
+		if err := p.store.checkCourses(person.Courses); err != nil {
+			return err
+		}
+		if len(person.Courses) != len(slices.Compact(slices.Sorted(slices.Values(person.Courses)))) {
+			// the database rejects the second row for the same course
+			return &ConstraintError{Kind: ErrConflict, Err: errors.New("person is already enrolled in course")}
+		}
+		p.store.lastPersonID++
+		id = p.store.lastPersonID
+		p.store.people[id] = models.Person{ID: id, FirstName: person.FirstName, LastName: person.LastName, Type: person.Type, Age: person.Age}
+		p.store.setCourses(id, person.Courses)
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+// DeletePerson removes the first person named firstName lastName, like RealPersonService.DeletePerson.
+func (p *MemoryPersonService) DeletePerson(ctx context.Context, firstName string, lastName string) (int64, error) {
+	var deleted int64
+	err := p.store.write(ctx, func() error {
+		ids := p.store.idsByName(firstName, lastName)
+		if len(ids) == 0 {
+			return ErrPersonNotFound
+		}
+		deleted = p.store.deletePerson(ids[0])
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+	return deleted, nil
+}
+func (p *MemoryPersonService) GetPersonByID(ctx context.Context, id int) (models.Person, error) {
+	var person models.Person
+	err := p.store.read(ctx, func() error {
+		person, _ = p.store.person(id)
+		return nil
+	})
+	return person, err
+}
+func (p *MemoryPersonService) UpdatePersonByID(ctx context.Context, id int, person models.Person) (models.Person, error) {
+	err := p.store.write(ctx, func() error {
+		if _, ok := p.store.people[id]; !ok {
+			return ErrPersonNotFound
+		}
+		if err := p.store.checkCourses(person.Courses); err != nil {
+			return err
+		}
+		person.ID = id
+		p.store.people[id] = models.Person{ID: id, FirstName: person.FirstName, LastName: person.LastName, Type: person.Type, Age: person.Age}
+		p.store.setCourses(id, person.Courses)
+		return nil
+	})
+	if err != nil {
+		return models.Person{}, err
+	}
+	return person, nil
+}
+func (p *MemoryPersonService) PatchPersonByID(ctx context.Context, id int, patch PersonPatch) (models.Person, error) {
+	var person models.Person
+	err := p.store.write(ctx, func() error {
+		stored, ok := p.store.people[id]
+		if !ok {
+			return ErrPersonNotFound
+		}
+		if patch.Courses != nil {
+			if err := p.store.checkCourses(*patch.Courses); err != nil {
+				return err
+			}
+			p.store.setCourses(id, *patch.Courses)
+		}
+		if patch.FirstName != nil {
+			stored.FirstName = *patch.FirstName
+		}
+		if patch.LastName != nil {
+			stored.LastName = *patch.LastName
+		}
+		if patch.Type != nil {
+			stored.Type = *patch.Type
+		}
+		if patch.Age != nil {
+			stored.Age = *patch.Age
+		}
+		p.store.people[id] = stored
+		person, _ = p.store.person(id)
+		return nil
+	})
+	if err != nil {
+		return models.Person{}, err
+	}
+	return person, nil
+}
+func (p *MemoryPersonService) DeletePersonByID(ctx context.Context, id int) (int64, error) {
+	var deleted int64
+	err := p.store.write(ctx, func() error {
+		deleted = p.store.deletePerson(id)
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+	return deleted, nil
+}
+
+type MemoryCourseService struct {
+	store *MemoryStore
+}
+
+func NewMemoryCourseService(store *MemoryStore) *MemoryCourseService {
+	return &MemoryCourseService{
+		store: store,
+	}
+}
+func (c *MemoryCourseService) GetAllCourses(ctx context.Context, opts ListOptions) ([]models.Course, PageInfo, error) {
+	var courses []models.Course
+	var pageInfo PageInfo
+	err := c.store.read(ctx, func() error {
+		all := slices.Collect(maps.Values(c.store.courses))
+		var err error
+		courses, pageInfo, err = page(all, opts, CourseSortFields, courseSortValue)
+		return err
+	})
+	if err != nil {
+		return []models.Course{}, PageInfo{}, err
+	}
+	return courses, pageInfo, nil
+}
+func (c *MemoryCourseService) GetCourse(ctx context.Context, id int) (models.Course, error) {
+	var course models.Course
+	err := c.store.read(ctx, func() error {
+		var ok bool
+		if course, ok = c.store.courses[id]; !ok {
+			return ErrCourseNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Course{}, err
+	}
+	return course, nil
+}
+func (c *MemoryCourseService) UpdateCourse(ctx context.Context, id int, course models.Course) (models.Course, error) {
+	err := c.store.write(ctx, func() error {
+		if _, ok := c.store.courses[id]; !ok {
+			return ErrCourseNotFound
+		}
+		course.ID = id
+		c.store.courses[id] = course
+		return nil
+	})
+	if err != nil {
+		return models.Course{}, err
+	}
+	return course, nil
+}
+func (c *MemoryCourseService) PatchCourse(ctx context.Context, id int, patch CoursePatch) (models.Course, error) {
+	if patch.Name == nil {
+		return c.GetCourse(ctx, id)
+	}
+	return c.UpdateCourse(ctx, id, models.Course{Name: *patch.Name})
+}
+func (c *MemoryCourseService) CreateCourse(ctx context.Context, course models.Course) (int, error) {
+	var id int
+	err := c.store.write(ctx, func() error {
+		c.store.lastCourseID++
+		id = c.store.lastCourseID
+		c.store.courses[id] = models.Course{ID: id, Name: course.Name}
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+// DeleteCourse removes the course with the given id and every enrollment in it. It returns the number of courses
+// deleted, which is 0 if no course has that id.
+func (c *MemoryCourseService) DeleteCourse(ctx context.Context, id int) (int64, error) {
+	var deleted int64
+	err := c.store.write(ctx, func() error {
+		if _, ok := c.store.courses[id]; !ok {
+			return nil
+		}
+		delete(c.store.courses, id)
+		for _, enrolled := range c.store.enrollments {
+			delete(enrolled, id)
+		}
+		deleted = 1
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+	return deleted, nil
+}
+func (c *MemoryCourseService) GetCourseRoster(ctx context.Context, id int, personType string, opts ListOptions) (models.Roster, PageInfo, error) {
+	var roster models.Roster
+	var pageInfo PageInfo
+	err := c.store.read(ctx, func() error {
+		course, ok := c.store.courses[id]
+		if !ok {
+			return ErrCourseNotFound
+		}
+		filter := PersonFilter{Type: personType, CourseIDs: []int{id}}
+		var enrolled []models.Person
+		for _, person := range c.store.allPeople() {
+			if filter.matches(person) {
+				enrolled = append(enrolled, person)
+			}
+		}
+		people, info, err := page(enrolled, opts, PersonSortFields, personSortValue)
+		if err != nil {
+			return err
+		}
+
+		pageInfo = info
+		roster = models.Roster{Course: course, Professors: make([]models.Person, 0), Students: make([]models.Person, 0)}
+		for _, person := range people {
+			if person.Type == "professor" {
+				roster.Professors = append(roster.Professors, person)
+			} else {
+				roster.Students = append(roster.Students, person)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Roster{}, PageInfo{}, err
+	}
+	return roster, pageInfo, nil
+}
+
+type MemoryEnrollmentService struct {
+	store *MemoryStore
+}
+
+func NewMemoryEnrollmentService(store *MemoryStore) *MemoryEnrollmentService {
+	return &MemoryEnrollmentService{
+		store: store,
+	}
+}
+func (e *MemoryEnrollmentService) GetCoursesForPerson(ctx context.Context, personID int) ([]models.Course, error) {
+	courses := make([]models.Course, 0)
+	err := e.store.read(ctx, func() error {
+		person, ok := e.store.person(personID)
+		if !ok {
+			return ErrPersonNotFound
+		}
+		for _, courseID := range person.Courses {
+			courses = append(courses, e.store.courses[courseID])
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.Course{}, err
+	}
+	return courses, nil
+}
+func (e *MemoryEnrollmentService) Enroll(ctx context.Context, personID int, courseID int) (bool, error) {
+	var created bool
+	err := e.store.write(ctx, func() error {
+		if _, ok := e.store.people[personID]; !ok {
+			return ErrPersonNotFound
+		}
+		if _, ok := e.store.courses[courseID]; !ok {
+			return ErrCourseNotFound
+		}
+		if e.store.enrollments[personID] == nil {
+			e.store.enrollments[personID] = make(map[int]bool)
+		}
+		created = !e.store.enrollments[personID][courseID]
+		e.store.enrollments[personID][courseID] = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+func (e *MemoryEnrollmentService) Drop(ctx context.Context, personID int, courseID int) error {
+	return e.store.write(ctx, func() error {
+		if !e.store.enrollments[personID][courseID] {
+			return ErrEnrollmentNotFound
+		}
+		delete(e.store.enrollments[personID], courseID)
+		return nil
+	})
+}
+
+type MemoryAPIKeyService struct {
+	store *MemoryStore
+}
+
+func NewMemoryAPIKeyService(store *MemoryStore) *MemoryAPIKeyService {
+	return &MemoryAPIKeyService{
+		store: store,
+	}
+}
+func (a *MemoryAPIKeyService) GetAllAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	keys := make([]models.APIKey, 0)
+	err := a.store.read(ctx, func() error {
+		for _, id := range slices.Sorted(maps.Keys(a.store.apiKeys)) {
+			keys = append(keys, a.store.apiKeys[id].get())
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.APIKey{}, err
+	}
+	return keys, nil
+}
+func (a *MemoryAPIKeyService) CreateAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, string, error) {
+	generated, err := auth.GenerateAPIKey()
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+	err = a.store.write(ctx, func() error {
+		a.store.lastAPIKeyID++
+		stored := memoryAPIKey{
+			key:  models.APIKey{ID: a.store.lastAPIKeyID, Name: key.Name, Prefix: generated.Prefix, Scopes: slices.Clone(key.Scopes), CreatedAt: now()},
+			salt: generated.Salt,
+			hash: generated.Hash,
+		}
+		a.store.apiKeys[stored.key.ID] = stored
+		key = stored.get()
+		return nil
+	})
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+	return key, generated.Key, nil
+}
+func (a *MemoryAPIKeyService) RotateAPIKey(ctx context.Context, id int) (models.APIKey, string, error) {
+	generated, err := auth.GenerateAPIKey()
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+	var key models.APIKey
+	err = a.store.write(ctx, func() error {
+		stored, ok := a.store.apiKeys[id]
+		if !ok || stored.key.RevokedAt != nil {
+			return ErrAPIKeyNotFound
+		}
+		stored.key.Prefix, stored.key.CreatedAt, stored.key.LastUsedAt = generated.Prefix, now(), nil
+		stored.salt, stored.hash = generated.Salt, generated.Hash
+		a.store.apiKeys[id] = stored
+		key = stored.get()
+		return nil
+	})
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+	return key, generated.Key, nil
+}
+func (a *MemoryAPIKeyService) RevokeAPIKey(ctx context.Context, id int) error {
+	return a.store.write(ctx, func() error {
+		stored, ok := a.store.apiKeys[id]
+		if !ok {
+			return ErrAPIKeyNotFound
+		}
+		if stored.key.RevokedAt == nil {
+			revokedAt := now()
+			stored.key.RevokedAt = &revokedAt
+			a.store.apiKeys[id] = stored
+		}
+		return nil
+	})
+}
+func (a *MemoryAPIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (models.APIKey, error) {
+	prefix, ok := auth.APIKeyPrefix(key)
+	if !ok {
+		return models.APIKey{}, ErrInvalidAPIKey
+	}
+	var authenticated models.APIKey
+	err := a.store.write(ctx, func() error {
+		for id, stored := range a.store.apiKeys {
+			if stored.key.Prefix != prefix {
+				continue
+			}
+			if !auth.CheckAPIKey(key, stored.salt, stored.hash) || stored.key.RevokedAt != nil {
+				return ErrInvalidAPIKey
+			}
+			if usedAt := now(); stored.key.LastUsedAt == nil || usedAt.Sub(*stored.key.LastUsedAt) >= lastUsedInterval {
+				stored.key.LastUsedAt = &usedAt
+				a.store.apiKeys[id] = stored
+			}
+			authenticated = stored.get()
+			return nil
+		}
+		return ErrInvalidAPIKey
+	})
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	return authenticated, nil
+}
@@ -0,0 +1,207 @@
+package services
+
+//memory_test.go tests ./memory.go. No database is involved, so these tests do not use the sqlmock suite in ./setup_test.go.
+
+import (
+	"context"
+	"sync"
+	"tech-challenge/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newMemoryServices returns in-memory services sharing a store holding the courses Programming (1) and Databases (2).
+func newMemoryServices(t *testing.T) (*MemoryPersonService, *MemoryCourseService, *MemoryEnrollmentService) {
+	store := NewMemoryStore()
+	courses := NewMemoryCourseService(store)
+	for _, name := range []string{"Programming", "Databases"} {
+		_, err := courses.CreateCourse(context.Background(), models.Course{Name: name})
+		assert.NoError(t, err)
+	}
+	return NewMemoryPersonService(store), courses, NewMemoryEnrollmentService(store)
+}
+
+func TestMemoryCreateAndGetPerson(t *testing.T) {
+	ctx := context.Background()
+	people, _, _ := newMemoryServices(t)
+
+	id, err := people.CreatePerson(ctx, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{2, 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+
+	person, err := people.GetPerson(ctx, "TIM", "rogers")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{ID: 1, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1, 2}}, person)
+
+	person, err = people.GetPerson(ctx, "Jill", "Rogers")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{}, person)
+}
+
+func TestMemoryCreatePersonFailures(t *testing.T) {
+	testCases := map[string]struct {
+		courses     []int
+		expectedErr error
+	}{
+		"unknown course":   {courses: []int{1, 3}, expectedErr: ErrUnknownCourse},
+		"duplicate course": {courses: []int{1, 1}, expectedErr: ErrConflict},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			ctx := context.Background()
+			people, _, _ := newMemoryServices(t)
+
+			id, err := people.CreatePerson(ctx, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: testVars.courses})
+			assert.ErrorIs(t, err, testVars.expectedErr)
+			assert.Equal(t, -1, id)
+
+			all, _, err := people.GetAllPeople(ctx, PersonFilter{}, ListOptions{})
+			assert.NoError(t, err)
+			assert.Empty(t, all)
+		})
+	}
+}
+
+func TestMemoryPatchPersonUnknownCourseChangesNothing(t *testing.T) {
+	ctx := context.Background()
+	people, _, _ := newMemoryServices(t)
+	id, err := people.CreatePerson(ctx, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1}})
+	assert.NoError(t, err)
+
+	age := 23
+	courses := []int{1, 3}
+	_, err = people.PatchPersonByID(ctx, id, PersonPatch{Age: &age, Courses: &courses})
+	assert.Equal(t, ErrUnknownCourse, err)
+
+	person, err := people.GetPersonByID(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{ID: id, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1}}, person)
+}
+
+func TestMemoryDeleteCourseRemovesEnrollments(t *testing.T) {
+	ctx := context.Background()
+	people, courses, enrollments := newMemoryServices(t)
+	id, err := people.CreatePerson(ctx, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1, 2}})
+	assert.NoError(t, err)
+
+	deleted, err := courses.DeleteCourse(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	deleted, err = courses.DeleteCourse(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+
+	enrolled, err := enrollments.GetCoursesForPerson(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Course{{ID: 2, Name: "Databases"}}, enrolled)
+}
+
+func TestMemoryDeletePersonRemovesEnrollments(t *testing.T) {
+	ctx := context.Background()
+	people, courses, _ := newMemoryServices(t)
+	_, err := people.CreatePerson(ctx, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1}})
+	assert.NoError(t, err)
+
+	deleted, err := people.DeletePerson(ctx, "tim", "ROGERS")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	_, err = people.DeletePerson(ctx, "tim", "ROGERS")
+	assert.Equal(t, ErrPersonNotFound, err)
+
+	roster, _, err := courses.GetCourseRoster(ctx, 1, "", ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Roster{Course: models.Course{ID: 1, Name: "Programming"}, Professors: []models.Person{}, Students: []models.Person{}}, roster)
+}
+
+func TestMemoryGetAllPeoplePaginated(t *testing.T) {
+	ctx := context.Background()
+	people, _, _ := newMemoryServices(t)
+	for _, person := range []models.Person{
+		{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22},
+		{FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 30},
+		{FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 22},
+		{FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 48},
+	} {
+		_, err := people.CreatePerson(ctx, person)
+		assert.NoError(t, err)
+	}
+
+	opts := ListOptions{Limit: 2, IncludeTotal: true, Sort: []SortField{{Field: "age", Desc: true}}}
+	first, pageInfo, err := people.GetAllPeople(ctx, PersonFilter{}, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 2}, personIDs(first))
+	assert.Equal(t, 4, pageInfo.Total)
+
+	opts.Cursor = pageInfo.NextCursor
+	second, pageInfo, err := people.GetAllPeople(ctx, PersonFilter{}, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, personIDs(second))
+	assert.Equal(t, "", pageInfo.NextCursor)
+
+	filtered, _, err := people.GetAllPeople(ctx, PersonFilter{LastName: "rog", NameMatch: NamePrefix}, ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, personIDs(filtered))
+
+	_, _, err = people.GetAllPeople(ctx, PersonFilter{}, ListOptions{Sort: []SortField{{Field: "salary"}}})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func personIDs(people []models.Person) []int {
+	ids := make([]int, len(people))
+	for i, person := range people {
+		ids[i] = person.ID
+	}
+	return ids
+}
+
+func TestMemoryEnrollAndDrop(t *testing.T) {
+	ctx := context.Background()
+	people, _, enrollments := newMemoryServices(t)
+	id, err := people.CreatePerson(ctx, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22})
+	assert.NoError(t, err)
+
+	created, err := enrollments.Enroll(ctx, id, 2)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	created, err = enrollments.Enroll(ctx, id, 2)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	_, err = enrollments.Enroll(ctx, id, 3)
+	assert.Equal(t, ErrCourseNotFound, err)
+	_, err = enrollments.Enroll(ctx, 99, 2)
+	assert.Equal(t, ErrPersonNotFound, err)
+
+	assert.NoError(t, enrollments.Drop(ctx, id, 2))
+	assert.Equal(t, ErrEnrollmentNotFound, enrollments.Drop(ctx, id, 2))
+}
+
+func TestMemoryConcurrentCreates(t *testing.T) {
+	ctx := context.Background()
+	people, _, _ := newMemoryServices(t)
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := people.CreatePerson(ctx, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1}})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	all, _, err := people.GetAllPeople(ctx, PersonFilter{}, ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 50)
+	assert.Equal(t, 50, all[49].ID)
+}
+
+func TestMemoryContextCanceled(t *testing.T) {
+	people, _, _ := newMemoryServices(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := people.CreatePerson(ctx, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22})
+	assert.ErrorIs(t, err, context.Canceled)
+}
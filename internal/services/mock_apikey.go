@@ -0,0 +1,35 @@
+package services
+
+//mock_apikey.go is used for testing purposes in ../handlers/apikey_test.go and ../handlers/auth_test.go
+
+import (
+	"context"
+	"tech-challenge/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (s *MockAPIKeyService) GetAllAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	args := s.Called(ctx)
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+func (s *MockAPIKeyService) CreateAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, string, error) {
+	args := s.Called(ctx, key)
+	return args.Get(0).(models.APIKey), args.String(1), args.Error(2)
+}
+func (s *MockAPIKeyService) RotateAPIKey(ctx context.Context, id int) (models.APIKey, string, error) {
+	args := s.Called(ctx, id)
+	return args.Get(0).(models.APIKey), args.String(1), args.Error(2)
+}
+func (s *MockAPIKeyService) RevokeAPIKey(ctx context.Context, id int) error {
+	args := s.Called(ctx, id)
+	return args.Error(0)
+}
+func (s *MockAPIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (models.APIKey, error) {
+	args := s.Called(ctx, key)
+	return args.Get(0).(models.APIKey), args.Error(1)
+}
@@ -3,6 +3,7 @@ package services
 //mock_course.go is used for testing purposes in ../handlers/course_test.go
 
 import (
+	"context"
 	"tech-challenge/internal/models"
 
 	"github.com/stretchr/testify/mock"
@@ -12,23 +13,31 @@ type MockCourseService struct {
 	mock.Mock
 }
 
-func (s *MockCourseService) GetAllCourses() ([]models.Course, error) {
-	args := s.Called()
-	return args.Get(0).([]models.Course), args.Error(1)
+func (s *MockCourseService) GetAllCourses(ctx context.Context, opts ListOptions) ([]models.Course, PageInfo, error) {
+	args := s.Called(ctx, opts)
+	return args.Get(0).([]models.Course), args.Get(1).(PageInfo), args.Error(2)
 }
-func (s *MockCourseService) GetCourse(id int) (models.Course, error) {
-	args := s.Called(id)
+func (s *MockCourseService) GetCourse(ctx context.Context, id int) (models.Course, error) {
+	args := s.Called(ctx, id)
 	return args.Get(0).(models.Course), args.Error(1)
 }
-func (s *MockCourseService) UpdateCourse(id int, course models.Course) (models.Course, error) {
-	args := s.Called(id, course)
+func (s *MockCourseService) UpdateCourse(ctx context.Context, id int, course models.Course) (models.Course, error) {
+	args := s.Called(ctx, id, course)
 	return args.Get(0).(models.Course), args.Error(1)
 }
-func (s *MockCourseService) CreateCourse(course models.Course) (int, error) {
-	args := s.Called(course)
+func (s *MockCourseService) CreateCourse(ctx context.Context, course models.Course) (int, error) {
+	args := s.Called(ctx, course)
 	return args.Get(0).(int), args.Error(1)
 }
-func (s *MockCourseService) DeleteCourse(id int) (int64, error) {
-	args := s.Called(id)
+func (s *MockCourseService) DeleteCourse(ctx context.Context, id int) (int64, error) {
+	args := s.Called(ctx, id)
 	return args.Get(0).(int64), args.Error(1)
 }
+func (s *MockCourseService) PatchCourse(ctx context.Context, id int, patch CoursePatch) (models.Course, error) {
+	args := s.Called(ctx, id, patch)
+	return args.Get(0).(models.Course), args.Error(1)
+}
+func (s *MockCourseService) GetCourseRoster(ctx context.Context, id int, personType string, opts ListOptions) (models.Roster, PageInfo, error) {
+	args := s.Called(ctx, id, personType, opts)
+	return args.Get(0).(models.Roster), args.Get(1).(PageInfo), args.Error(2)
+}
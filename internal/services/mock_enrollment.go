@@ -0,0 +1,27 @@
+package services
+
+//mock_enrollment.go is used for testing purposes in ../handlers/enrollment_test.go
+
+import (
+	"context"
+	"tech-challenge/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEnrollmentService struct {
+	mock.Mock
+}
+
+func (s *MockEnrollmentService) GetCoursesForPerson(ctx context.Context, personID int) ([]models.Course, error) {
+	args := s.Called(ctx, personID)
+	return args.Get(0).([]models.Course), args.Error(1)
+}
+func (s *MockEnrollmentService) Enroll(ctx context.Context, personID int, courseID int) (bool, error) {
+	args := s.Called(ctx, personID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+func (s *MockEnrollmentService) Drop(ctx context.Context, personID int, courseID int) error {
+	args := s.Called(ctx, personID, courseID)
+	return args.Error(0)
+}
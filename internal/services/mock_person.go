@@ -3,6 +3,7 @@ package services
 //mock_person.go is used for testing purposes in ../handlers/person_test.go
 
 import (
+	"context"
 	"tech-challenge/internal/models"
 
 	"github.com/stretchr/testify/mock"
@@ -12,23 +13,39 @@ type MockPersonService struct {
 	mock.Mock
 }
 
-func (s *MockPersonService) GetAllPeople(age int, firstName string, lastName string) ([]models.Person, error) {
-	args := s.Called(age, firstName, lastName)
-	return args.Get(0).([]models.Person), args.Error(1)
+func (s *MockPersonService) GetAllPeople(ctx context.Context, filter PersonFilter, opts ListOptions) ([]models.Person, PageInfo, error) {
+	args := s.Called(ctx, filter, opts)
+	return args.Get(0).([]models.Person), args.Get(1).(PageInfo), args.Error(2)
 }
-func (s *MockPersonService) GetPerson(firstName string, lastName string) (models.Person, error) {
-	args := s.Called(firstName, lastName)
+func (s *MockPersonService) GetPerson(ctx context.Context, firstName string, lastName string) (models.Person, error) {
+	args := s.Called(ctx, firstName, lastName)
 	return args.Get(0).(models.Person), args.Error(1)
 }
-func (s *MockPersonService) UpdatePerson(firstName string, lastName string, person models.Person) (models.Person, error) {
-	args := s.Called(firstName, lastName, person)
+func (s *MockPersonService) UpdatePerson(ctx context.Context, firstName string, lastName string, person models.Person) (models.Person, error) {
+	args := s.Called(ctx, firstName, lastName, person)
 	return args.Get(0).(models.Person), args.Error(1)
 }
-func (s *MockPersonService) CreatePerson(person models.Person) (int, error) {
-	args := s.Called(person)
+func (s *MockPersonService) CreatePerson(ctx context.Context, person models.Person) (int, error) {
+	args := s.Called(ctx, person)
 	return args.Get(0).(int), args.Error(1)
 }
-func (s *MockPersonService) DeletePerson(firstName string, lastName string) (int64, error) {
-	args := s.Called(firstName, lastName)
+func (s *MockPersonService) DeletePerson(ctx context.Context, firstName string, lastName string) (int64, error) {
+	args := s.Called(ctx, firstName, lastName)
 	return args.Get(0).(int64), args.Error(1)
 }
+func (s *MockPersonService) GetPersonByID(ctx context.Context, id int) (models.Person, error) {
+	args := s.Called(ctx, id)
+	return args.Get(0).(models.Person), args.Error(1)
+}
+func (s *MockPersonService) UpdatePersonByID(ctx context.Context, id int, person models.Person) (models.Person, error) {
+	args := s.Called(ctx, id, person)
+	return args.Get(0).(models.Person), args.Error(1)
+}
+func (s *MockPersonService) DeletePersonByID(ctx context.Context, id int) (int64, error) {
+	args := s.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (s *MockPersonService) PatchPersonByID(ctx context.Context, id int, patch PersonPatch) (models.Person, error) {
+	args := s.Called(ctx, id, patch)
+	return args.Get(0).(models.Person), args.Error(1)
+}
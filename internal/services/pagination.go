@@ -0,0 +1,214 @@
+package services
+
+//pagination.go defines the keyset pagination and sorting shared by the listing service functions in ./course.go and ./person.go.
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ListOptions selects one page of a listing.
+type ListOptions struct {
+	// Limit is the maximum number of items to return. 0 returns every remaining item.
+	Limit int
+	// Cursor is the PageInfo.NextCursor of the previous page, or empty for the first page. It is only valid with the
+	// same Sort it was returned for.
+	Cursor string
+	// IncludeTotal requests the number of items across all pages in PageInfo.Total.
+	IncludeTotal bool
+	// Sort orders the listing by each field in turn. Ties are always broken by id, and an empty Sort orders by id alone.
+	Sort []SortField
+}
+
+// SortField orders a listing by one column, named as in the JSON representation of the listed model.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// PageInfo describes the page returned by a listing.
+type PageInfo struct {
+	// NextCursor fetches the following page when passed as ListOptions.Cursor. It is empty on the last page.
+	NextCursor string
+	// Total is the number of items across all pages, or -1 if ListOptions.IncludeTotal was not set.
+	Total int
+}
+
+// cursor holds the sort key values of the last item on a page. The next page starts after them. It is sent to
+// clients as opaque base64.
+type cursor struct {
+	Keys []any `json:"keys"`
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+func decodeCursor(s string) (cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var c cursor
+	if err = decoder.Decode(&c); err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+	for i, key := range c.Keys {
+		switch value := key.(type) {
+		case string:
+		case json.Number:
+			n, err := strconv.Atoi(value.String())
+			if err != nil {
+				return cursor{}, ErrInvalidCursor
+			}
+			c.Keys[i] = n
+		default:
+			return cursor{}, ErrInvalidCursor
+		}
+	}
+	return c, nil
+}
+
+// intSortFields are the sort fields holding integers. Every other sort field holds a string.
+var intSortFields = []string{"id", "age"}
+
+// checkCursor returns ErrInvalidCursor unless c holds a value of the right type for each of keys, as a cursor made
+// for another sort may not.
+func checkCursor(c cursor, keys []SortField) error {
+	if len(c.Keys) != len(keys) {
+		return ErrInvalidCursor
+	}
+	for i, key := range keys {
+		_, isInt := c.Keys[i].(int)
+		if isInt != slices.Contains(intSortFields, key.Field) {
+			return ErrInvalidCursor
+		}
+	}
+	return nil
+}
+
+// sortColumn returns the column of key in the page query. Text is compared bytewise with the "C" collation, so
+// Postgres orders names like SQLite and MemoryStore rather than by the locale of its server.
+func sortColumn(key SortField) string {
+	if slices.Contains(intSortFields, key.Field) {
+		return `page.` + key.Field
+	}
+	return `page.` + key.Field + ` COLLATE "C"`
+}
+
+// sortKeys returns the columns a listing sorted by sort is ordered by, ending with id so the order is total.
+func sortKeys(sort []SortField) []SortField {
+	keys := append([]SortField{}, sort...)
+	for _, key := range keys {
+		if key.Field == "id" {
+			return keys
+		}
+	}
+	return append(keys, SortField{Field: "id"})
+}
+
+// checkSort returns ErrInvalidSort if sort names a field missing from sortable, or names a field twice.
+func checkSort(sort []SortField, sortable []string) error {
+	seen := make(map[string]bool)
+	for _, key := range sort {
+		if !slices.Contains(sortable, key.Field) || seen[key.Field] {
+			return ErrInvalidSort
+		}
+		seen[key.Field] = true
+	}
+	return nil
+}
+
+// paginate wraps query, a SELECT returning every column in sortable, so it returns the page of rows selected by opts
+// in the order of opts.Sort. One row more than opts.Limit is fetched so trimPage can tell whether another page follows.
+func paginate(query string, args []any, opts ListOptions, sortable []string) (string, []any, error) {
+	if err := checkSort(opts.Sort, sortable); err != nil {
+		return "", nil, err
+	}
+	keys := sortKeys(opts.Sort)
+
+	pageQuery := `SELECT * FROM (` + query + `) AS page`
+	pageArgs := append([]any{}, args...)
+	if opts.Cursor != "" {
+		after, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		if err = checkCursor(after, keys); err != nil {
+			return "", nil, err
+		}
+		placeholders := make([]string, len(keys))
+		for i, value := range after.Keys {
+			pageArgs = append(pageArgs, value)
+			placeholders[i] = `$` + strconv.Itoa(len(pageArgs))
+		}
+		// a row is after the cursor if it is past it on some key and level with it on every key before that one
+		alternatives := make([]string, len(keys))
+		for i, key := range keys {
+			var terms []string
+			for j := 0; j < i; j++ {
+				terms = append(terms, `page.`+keys[j].Field+` = `+placeholders[j])
+			}
+			operator := ` > `
+			if key.Desc {
+				operator = ` < `
+			}
+			terms = append(terms, sortColumn(key)+operator+placeholders[i])
+			alternatives[i] = strings.Join(terms, ` AND `)
+		}
+		if len(alternatives) == 1 {
+			pageQuery += ` WHERE ` + alternatives[0]
+		} else {
+			pageQuery += ` WHERE (` + alternatives[0]
+			for _, alternative := range alternatives[1:] {
+				pageQuery += ` OR (` + alternative + `)`
+			}
+			pageQuery += `)`
+		}
+	}
+
+	orderBy := make([]string, len(keys))
+	for i, key := range keys {
+		orderBy[i] = sortColumn(key)
+		if key.Desc {
+			orderBy[i] += ` DESC`
+		}
+	}
+	pageQuery += ` ORDER BY ` + strings.Join(orderBy, `, `)
+	if opts.Limit > 0 {
+		pageArgs = append(pageArgs, opts.Limit+1)
+		pageQuery += ` LIMIT $` + strconv.Itoa(len(pageArgs))
+	}
+	return pageQuery, pageArgs, nil
+}
+
+// countRows returns the number of rows query selects across all pages.
+func countRows(ctx context.Context, db *sql.DB, query string, args []any) (int, error) {
+	var total int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+query+`) AS filtered`, args...).Scan(&total)
+	return total, err
+}
+
+// trimPage drops the extra item fetched by paginate and returns the cursor of the page after items, if there is one.
+// sortValue returns the value of an item in one of the sortable columns.
+func trimPage[T any](items []T, opts ListOptions, sortValue func(T, string) any) ([]T, string) {
+	if opts.Limit <= 0 || len(items) <= opts.Limit {
+		return items, ""
+	}
+	items = items[:opts.Limit]
+	last := items[opts.Limit-1]
+	keys := sortKeys(opts.Sort)
+	next := cursor{Keys: make([]any, len(keys))}
+	for i, key := range keys {
+		next.Keys[i] = sortValue(last, key.Field)
+	}
+	return items, encodeCursor(next)
+}
@@ -0,0 +1,175 @@
+package services
+
+//pagination_test.go tests ./pagination.go and the paginated listings in ./course.go and ./person.go.
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/testutil"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate(t *testing.T) {
+	baseQuery := `SELECT * FROM "person" WHERE age = $1`
+	testCases := map[string]struct {
+		opts          ListOptions
+		expectedQuery string
+		expectedArgs  []any
+		expectedErr   error
+	}{
+		"no options": {
+			opts:          ListOptions{},
+			expectedQuery: `SELECT * FROM (SELECT * FROM "person" WHERE age = $1) AS page ORDER BY page.id`,
+			expectedArgs:  []any{22},
+		},
+		"limit": {
+			opts:          ListOptions{Limit: 10},
+			expectedQuery: `SELECT * FROM (SELECT * FROM "person" WHERE age = $1) AS page ORDER BY page.id LIMIT $2`,
+			expectedArgs:  []any{22, 11},
+		},
+		"limit and cursor": {
+			opts:          ListOptions{Limit: 10, Cursor: encodeCursor(cursor{Keys: []any{7}})},
+			expectedQuery: `SELECT * FROM (SELECT * FROM "person" WHERE age = $1) AS page WHERE page.id > $2 ORDER BY page.id LIMIT $3`,
+			expectedArgs:  []any{22, 7, 11},
+		},
+		"sorted": {
+			opts:          ListOptions{Limit: 10, Sort: []SortField{{Field: "last_name"}, {Field: "age", Desc: true}}},
+			expectedQuery: `SELECT * FROM (SELECT * FROM "person" WHERE age = $1) AS page ORDER BY page.last_name COLLATE "C", page.age DESC, page.id LIMIT $2`,
+			expectedArgs:  []any{22, 11},
+		},
+		"sorted by id descending": {
+			opts:          ListOptions{Sort: []SortField{{Field: "id", Desc: true}}, Cursor: encodeCursor(cursor{Keys: []any{7}})},
+			expectedQuery: `SELECT * FROM (SELECT * FROM "person" WHERE age = $1) AS page WHERE page.id < $2 ORDER BY page.id DESC`,
+			expectedArgs:  []any{22, 7},
+		},
+		"sorted with cursor": {
+			opts: ListOptions{
+				Limit:  10,
+				Sort:   []SortField{{Field: "last_name"}, {Field: "age", Desc: true}},
+				Cursor: encodeCursor(cursor{Keys: []any{"Rogers", 22, 7}}),
+			},
+			expectedQuery: `SELECT * FROM (SELECT * FROM "person" WHERE age = $1) AS page WHERE (page.last_name COLLATE "C" > $2 ` +
+				`OR (page.last_name = $2 AND page.age < $3) OR (page.last_name = $2 AND page.age = $3 AND page.id > $4)) ` +
+				`ORDER BY page.last_name COLLATE "C", page.age DESC, page.id LIMIT $5`,
+			expectedArgs: []any{22, "Rogers", 22, 7, 11},
+		},
+		"unknown sort field": {
+			opts:        ListOptions{Sort: []SortField{{Field: "password"}}},
+			expectedErr: ErrInvalidSort,
+		},
+		"repeated sort field": {
+			opts:        ListOptions{Sort: []SortField{{Field: "age"}, {Field: "age", Desc: true}}},
+			expectedErr: ErrInvalidSort,
+		},
+		"cursor from another sort": {
+			opts:        ListOptions{Sort: []SortField{{Field: "age"}}, Cursor: encodeCursor(cursor{Keys: []any{7}})},
+			expectedErr: ErrInvalidCursor,
+		},
+		"cursor with a key of the wrong type": {
+			opts:        ListOptions{Sort: []SortField{{Field: "age"}}, Cursor: encodeCursor(cursor{Keys: []any{"Rogers", 7}})},
+			expectedErr: ErrInvalidCursor,
+		},
+		"malformed cursor": {
+			opts:        ListOptions{Cursor: "not a cursor!"},
+			expectedErr: ErrInvalidCursor,
+		},
+		"cursor that is not json": {
+			opts:        ListOptions{Cursor: "bm90IGpzb24"},
+			expectedErr: ErrInvalidCursor,
+		},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			query, args, err := paginate(baseQuery, []any{22}, testConditions.opts, PersonSortFields)
+			assert.Equal(t, testConditions.expectedErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, testConditions.expectedQuery, query)
+			assert.Equal(t, testConditions.expectedArgs, args)
+		})
+	}
+}
+func TestTrimPage(t *testing.T) {
+	people := []models.Person{
+		{ID: 4, LastName: "Rogers", Age: 30},
+		{ID: 2, LastName: "Rogers", Age: 22},
+		{ID: 9, LastName: "Thane", Age: 18},
+	}
+
+	items, next := trimPage(people, ListOptions{Limit: 2, Sort: []SortField{{Field: "last_name"}, {Field: "age", Desc: true}}}, personSortValue)
+	assert.Equal(t, people[:2], items)
+	decoded, err := decodeCursor(next)
+	assert.NoError(t, err)
+	assert.Equal(t, cursor{Keys: []any{"Rogers", 22, 2}}, decoded)
+
+	items, next = trimPage(people, ListOptions{Limit: 3}, personSortValue)
+	assert.Equal(t, people, items)
+	assert.Equal(t, "", next)
+
+	items, next = trimPage(people, ListOptions{}, personSortValue)
+	assert.Equal(t, people, items)
+	assert.Equal(t, "", next)
+}
+func TestInvalidCursorKind(t *testing.T) {
+	assert.True(t, errors.Is(ErrInvalidCursor, ErrInvalidInput))
+	msg, ok := ClientMessage(ErrInvalidCursor)
+	assert.True(t, ok)
+	assert.Equal(t, "invalid cursor", msg)
+}
+
+func (s *testSuit) TestGetAllCoursesPaginated() {
+	t := s.T()
+
+	courses := []models.Course{
+		{ID: 3, Name: "Unit Testing 101"},
+		{ID: 4, Name: "Table Driven Testing"},
+		{ID: 5, Name: "Database Transactions and Hot Chocolate"},
+	}
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM (SELECT * FROM "course") AS filtered`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(6))
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM (SELECT * FROM "course") AS page WHERE page.id > $1 ORDER BY page.id LIMIT $2`)).
+		WithArgs(2, 3).
+		WillReturnRows(testutil.MustStructsToRows(courses))
+
+	opts := ListOptions{Limit: 2, Cursor: encodeCursor(cursor{Keys: []any{2}}), IncludeTotal: true}
+	result, pageInfo, err := s.realCourseService.GetAllCourses(context.Background(), opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, courses[:2], result)
+	assert.Equal(t, PageInfo{NextCursor: encodeCursor(cursor{Keys: []any{4}}), Total: 6}, pageInfo)
+	assert.NoError(t, s.dbMock.ExpectationsWereMet())
+}
+func (s *testSuit) TestGetAllPeoplePaginatedLastPage() {
+	t := s.T()
+
+	people := []PersonDTO{
+		{ID: 8, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "1"},
+	}
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM (SELECT `+personColumns+` FROM "person" WHERE age = $1) AS page WHERE page.id > $2 ORDER BY page.id LIMIT $3`)).
+		WithArgs(22, 5, 3).
+		WillReturnRows(testutil.MustStructsToRows(people))
+
+	opts := ListOptions{Limit: 2, Cursor: encodeCursor(cursor{Keys: []any{5}})}
+	age := 22
+	result, pageInfo, err := s.personService.GetAllPeople(context.Background(), PersonFilter{Age: &age}, opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Person{{ID: 8, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1}}}, result)
+	assert.Equal(t, PageInfo{NextCursor: "", Total: -1}, pageInfo)
+	assert.NoError(t, s.dbMock.ExpectationsWereMet())
+}
+func (s *testSuit) TestGetAllPeopleInvalidCursor() {
+	t := s.T()
+
+	result, _, err := s.personService.GetAllPeople(context.Background(), PersonFilter{}, ListOptions{Cursor: "%%%"})
+
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	assert.Equal(t, []models.Person{}, result)
+	assert.NoError(t, s.dbMock.ExpectationsWereMet())
+}
@@ -0,0 +1,59 @@
+package services
+
+//patch.go defines the partial updates accepted by PatchPersonByID() in ./person.go and PatchCourse() in ./course.go.
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PersonPatch lists the changes to make to a person. Nil fields are left as they are.
+type PersonPatch struct {
+	FirstName *string
+	LastName  *string
+	Type      *string
+	Age       *int
+	// Courses replaces the person's course list. Only the enrollments that differ from the stored list are written.
+	Courses *[]int
+}
+
+// CoursePatch lists the changes to make to a course. Nil fields are left as they are.
+type CoursePatch struct {
+	Name *string
+}
+
+// column is one column written by a partial update.
+type column struct {
+	name  string
+	value any
+}
+
+// setClause returns the SET list of an UPDATE that writes only columns, with placeholders numbered from $1, and
+// the matching arguments.
+func setClause(columns []column) (string, []any) {
+	sets := make([]string, 0, len(columns))
+	args := make([]any, 0, len(columns))
+	for _, c := range columns {
+		args = append(args, c.value)
+		sets = append(sets, `"`+c.name+`" = $`+strconv.Itoa(len(args)))
+	}
+	return strings.Join(sets, ", "), args
+}
+
+// columns returns the person columns p changes, in table order.
+func (p PersonPatch) columns() []column {
+	var columns []column
+	if p.FirstName != nil {
+		columns = append(columns, column{"first_name", *p.FirstName})
+	}
+	if p.LastName != nil {
+		columns = append(columns, column{"last_name", *p.LastName})
+	}
+	if p.Type != nil {
+		columns = append(columns, column{"type", *p.Type})
+	}
+	if p.Age != nil {
+		columns = append(columns, column{"age", *p.Age})
+	}
+	return columns
+}
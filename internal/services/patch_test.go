@@ -0,0 +1,157 @@
+package services
+
+//patch_test.go tests ./patch.go along with PatchPersonByID() in ./person.go and PatchCourse() in ./course.go.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/testutil"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetClause(t *testing.T) {
+	age := 30
+	lastName := "Thane"
+	sets, args := setClause(PersonPatch{LastName: &lastName, Age: &age}.columns())
+	assert.Equal(t, `"last_name" = $1, "age" = $2`, sets)
+	assert.Equal(t, []any{"Thane", 30}, args)
+
+	sets, args = setClause(PersonPatch{}.columns())
+	assert.Equal(t, "", sets)
+	assert.Equal(t, []any{}, args)
+}
+
+// Tests for PatchPersonByID()
+// ColumnsOnlySuccess
+// CoursesOnlySuccess
+// NotFoundFailure
+// CoursesOnlyNotFoundFailure
+func (s *testSuit) TestPatchPersonByIDColumnsOnlySuccess() {
+	t := s.T()
+
+	age := 19
+	storedPerson := []PersonDTO{{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 19, Courses: "1"}}
+
+	s.dbMock.ExpectBegin()
+	query := `UPDATE "person" SET "age" = $1 WHERE "id" = $2`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(19, 3).WillReturnResult(sqlmock.NewResult(3, 1))
+	s.dbMock.ExpectCommit()
+	query = `SELECT ` + personColumns + ` FROM "person" WHERE "id" = $1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(storedPerson))
+
+	patchedPerson, err := s.personService.PatchPersonByID(context.Background(), 3, PersonPatch{Age: &age})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 19, Courses: []int{1}}, patchedPerson)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestPatchPersonByIDCoursesOnlySuccess() {
+	t := s.T()
+
+	courses := []int{1, 4}
+	storedPerson := []PersonDTO{{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "1,4"}}
+	person_course := []Person_Course{{PersonID: 3, CourseID: 1}}
+
+	s.dbMock.ExpectBegin()
+	query := `SELECT id FROM "person" WHERE "id" = $1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 3}}))
+	query = `SELECT * FROM "person_course" WHERE person_id = $1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(person_course))
+	query = `SELECT id FROM "course"`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 1}, {ID: 4}}))
+	query = `INSERT INTO "person_course" (person_id, course_id) VALUES (3, 4)`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.dbMock.ExpectCommit()
+	query = `SELECT ` + personColumns + ` FROM "person" WHERE "id" = $1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(storedPerson))
+
+	patchedPerson, err := s.personService.PatchPersonByID(context.Background(), 3, PersonPatch{Courses: &courses})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: []int{1, 4}}, patchedPerson)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestPatchPersonByIDNotFoundFailure() {
+	t := s.T()
+
+	firstName := "Bubbly"
+
+	s.dbMock.ExpectBegin()
+	query := `UPDATE "person" SET "first_name" = $1 WHERE "id" = $2`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("Bubbly", 3).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.dbMock.ExpectRollback()
+
+	patchedPerson, err := s.personService.PatchPersonByID(context.Background(), 3, PersonPatch{FirstName: &firstName})
+	assert.Equal(t, ErrPersonNotFound, err)
+	assert.Equal(t, models.Person{}, patchedPerson)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestPatchPersonByIDCoursesOnlyNotFoundFailure() {
+	t := s.T()
+
+	courses := []int{1}
+
+	s.dbMock.ExpectBegin()
+	query := `SELECT id FROM "person" WHERE "id" = $1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnError(sql.ErrNoRows)
+	s.dbMock.ExpectRollback()
+
+	patchedPerson, err := s.personService.PatchPersonByID(context.Background(), 3, PersonPatch{Courses: &courses})
+	assert.Equal(t, ErrPersonNotFound, err)
+	assert.Equal(t, models.Person{}, patchedPerson)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func (s *testSuit) TestPatchCourse() {
+	t := s.T()
+
+	name := "Advanced Hot Chocolate"
+	testCases := map[string]struct {
+		patch          CoursePatch
+		mockReturn     *sqlmock.Rows
+		mockReturnErr  error
+		expectedReturn models.Course
+		expectedErr    error
+	}{
+		"PatchSuccess": {
+			patch:          CoursePatch{Name: &name},
+			mockReturn:     testutil.MustStructsToRows([]models.Course{{ID: 2, Name: name}}),
+			expectedReturn: models.Course{ID: 2, Name: name},
+		},
+		"NotFound": {
+			patch:          CoursePatch{Name: &name},
+			mockReturn:     &sqlmock.Rows{},
+			mockReturnErr:  sql.ErrNoRows,
+			expectedReturn: models.Course{},
+			expectedErr:    ErrCourseNotFound,
+		},
+		"QueryError": {
+			patch:          CoursePatch{Name: &name},
+			mockReturn:     &sqlmock.Rows{},
+			mockReturnErr:  errors.New("can't update"),
+			expectedReturn: models.Course{},
+			expectedErr:    fmt.Errorf("failed to update course: %w", errors.New("can't update")),
+		},
+	}
+	for testName, testConditions := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			query := `UPDATE "course" SET "name" = $1 WHERE "id" = $2 RETURNING "id", "name"`
+			s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(name, 2).WillReturnRows(testConditions.mockReturn).WillReturnError(testConditions.mockReturnErr)
+
+			actualReturn, err := s.realCourseService.PatchCourse(context.Background(), 2, testConditions.patch)
+			assert.Equal(t, testConditions.expectedErr, err)
+			assert.Equal(t, testConditions.expectedReturn, actualReturn)
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
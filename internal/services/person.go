@@ -7,26 +7,33 @@ course.go defines the service functions and logic used by RealPersonService stru
 
 UpdatePerson() and DeletePerson() functions are defined as specified in the tech challenge github repo. However, querying based off
 of a non-unique value (firstName and lastName) is poor design that would lead to unintended outcomes for real users.
-I recommend refactoring to query these by the student's ID like models.Course objects.
+GetPersonByID(), UpdatePersonByID() and DeletePersonByID() query by the person's primary key instead and should be preferred
+by any caller that knows which person it wants.
 */
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strconv"
 	"strings"
 	"tech-challenge/internal/models"
-
-	"github.com/lib/pq"
 )
 
 type PersonService interface {
-	GetAllPeople(int, string, string) ([]models.Person, error)
-	GetPerson(string, string) (models.Person, error)
-	UpdatePerson(string, string, models.Person) (models.Person, error)
-	CreatePerson(models.Person) (int, error)
-	DeletePerson(string, string) (int64, error)
+	GetAllPeople(context.Context, PersonFilter, ListOptions) ([]models.Person, PageInfo, error)
+	GetPerson(context.Context, string, string) (models.Person, error)
+	UpdatePerson(context.Context, string, string, models.Person) (models.Person, error)
+	CreatePerson(context.Context, models.Person) (int, error)
+	DeletePerson(context.Context, string, string) (int64, error)
+	GetPersonByID(context.Context, int) (models.Person, error)
+	UpdatePersonByID(context.Context, int, models.Person) (models.Person, error)
+	DeletePersonByID(context.Context, int) (int64, error)
+	PatchPersonByID(context.Context, int, PersonPatch) (models.Person, error)
 }
 
+// PersonSortFields are the fields GetAllPeople can sort by.
+var PersonSortFields = []string{"id", "first_name", "last_name", "type", "age"}
+
 type RealPersonService struct {
 	db *sql.DB
 }
@@ -36,61 +43,44 @@ func NewPersonService(db *sql.DB) *RealPersonService {
 		db: db,
 	}
 }
-func (p *RealPersonService) GetAllPeople(age int, firstName string, lastName string) ([]models.Person, error) {
-	var err error
-
-	var rows *sql.Rows
-	if age != -1 && (firstName != "" && lastName != "") {
-		rows, err = dbQueryGetPeopleByNameAndAge(firstName, lastName, age, p.db)
-	} else if age != -1 {
-		rows, err = dbQueryGetPeopleByAge(age, p.db)
-	} else if firstName != "" && lastName != "" {
-		rows, err = dbQueryGetPeopleByName(firstName, lastName, p.db)
-	} else {
-		rows, err = dbQueryGetPeople(p.db)
+func (p *RealPersonService) GetAllPeople(ctx context.Context, filter PersonFilter, opts ListOptions) ([]models.Person, PageInfo, error) {
+	query, args := filter.query()
+
+	pageQuery, pageArgs, err := paginate(query, args, opts, PersonSortFields)
+	if err != nil {
+		return []models.Person{}, PageInfo{}, err
+	}
+	pageInfo := PageInfo{Total: -1}
+	if opts.IncludeTotal {
+		pageInfo.Total, err = countRows(ctx, p.db, query, args)
+		if err != nil {
+			return []models.Person{}, PageInfo{}, fmt.Errorf("failed to count people: %w", err)
+		}
 	}
 
+	rows, err := p.db.QueryContext(ctx, pageQuery, pageArgs...)
 	if err != nil {
-		return []models.Person{}, fmt.Errorf("failed to get people: %w", err)
+		return []models.Person{}, PageInfo{}, fmt.Errorf("failed to get people: %w", err)
 	}
 	defer rows.Close()
 
 	var people []models.Person
 	for rows.Next() {
-		var person models.Person
-		err = rows.Scan(&person.ID,
-			&person.FirstName,
-			&person.LastName,
-			&person.Type,
-			&person.Age,
-		)
+		person, err := scanPerson(rows)
 		if err != nil {
-			return []models.Person{}, fmt.Errorf("failed to scan person from row: %w", err)
+			return []models.Person{}, PageInfo{}, fmt.Errorf("failed to scan person from row: %w", err)
 		}
-
-		courseRows, err := p.db.Query(`SELECT * FROM "person_course"
-				WHERE person_id = $1`,
-			person.ID)
-		if err != nil {
-			return []models.Person{}, fmt.Errorf("failed to get courses for person: %w", err)
-		}
-		var personCourses = make([]int, 0)
-		for courseRows.Next() {
-			var personID int
-			var courseID int
-			courseRows.Scan(&personID, &courseID)
-			personCourses = append(personCourses, courseID)
-		}
-		person.Courses = personCourses
 		people = append(people, person)
 	}
 	if err = rows.Err(); err != nil {
-		return []models.Person{}, fmt.Errorf("failed to scan people: %w", err)
+		return []models.Person{}, PageInfo{}, fmt.Errorf("failed to scan people: %w", err)
 	}
-	return people, nil
+
+	people, pageInfo.NextCursor = trimPage(people, opts, personSortValue)
+	return people, pageInfo, nil
 }
-func (p *RealPersonService) GetPerson(firstName string, lastName string) (models.Person, error) {
-	rows, err := p.db.Query(`SELECT * FROM "person" 
+func (p *RealPersonService) GetPerson(ctx context.Context, firstName string, lastName string) (models.Person, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT `+personColumns+` FROM "person" 
 	WHERE LOWER(first_name) = LOWER($1)
 	AND LOWER(last_name) = LOWER($2)
 	LIMIT 1`,
@@ -99,52 +89,31 @@ func (p *RealPersonService) GetPerson(firstName string, lastName string) (models
 	if err != nil {
 		return models.Person{}, fmt.Errorf("failed to get person: %w", err)
 	}
+	defer rows.Close()
 
-	var person models.Person
 	if isEmpty := !rows.Next(); isEmpty {
-		return person, nil
+		return models.Person{}, nil
 	}
-
-	err = rows.Scan(&person.ID,
-		&person.FirstName,
-		&person.LastName,
-		&person.Type,
-		&person.Age,
-	)
+	person, err := scanPerson(rows)
 	if err != nil {
 		return models.Person{}, fmt.Errorf("failed to scan person: %w", err)
 	}
-	courseRows, err := p.db.Query(`SELECT * FROM "person_course"
-				WHERE person_id = $1`,
-		person.ID)
-	if err != nil {
-		return models.Person{}, fmt.Errorf("failed to get courses for person: %w", err)
-	}
-	var personCourses = make([]int, 0)
-	for courseRows.Next() {
-		var personID int
-		var courseID int
-		courseRows.Scan(&personID, &courseID)
-		personCourses = append(personCourses, courseID)
-	}
-	person.Courses = personCourses
-
 	return person, nil
 }
 
 // This is really bad architecture. Because firstName and lastName do not constitute a unique key, this function could update the wrong user.
-func (p *RealPersonService) UpdatePerson(firstName string, lastName string, person models.Person) (models.Person, error) {
-	tx, err := p.db.Begin()
+func (p *RealPersonService) UpdatePerson(ctx context.Context, firstName string, lastName string, person models.Person) (models.Person, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
 		return models.Person{}, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func() {
 		if err != nil {
-			tx.Rollback()
+			rollback(ctx, tx)
 		}
 	}()
 
-	row, err := tx.Exec(`UPDATE "person" 
+	row, err := tx.ExecContext(ctx, `UPDATE "person" 
 	SET "first_name" = $1,
 		"last_name" = $2,
 		"type" = $3,
@@ -159,119 +128,61 @@ func (p *RealPersonService) UpdatePerson(firstName string, lastName string, pers
 		lastName,
 	)
 	if err != nil {
-		return models.Person{}, fmt.Errorf("failed to update person: %w", err)
+		return models.Person{}, fmt.Errorf("failed to update person: %w", mapDBError(err))
 	}
 	rowsAffected, err := row.RowsAffected()
 	if err != nil {
-		return models.Person{}, fmt.Errorf("failed to update person: %w", err)
+		return models.Person{}, fmt.Errorf("failed to update person: %w", mapDBError(err))
 	}
 	if rowsAffected == 0 {
-		return models.Person{}, fmt.Errorf("person not found")
+		err = ErrPersonNotFound
+		return models.Person{}, err
 	}
 
 	//removing and adding courses to person_course
 
 	//1. do a select to get ID
-	rows, err := tx.Query(`SELECT id FROM "person"
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM "person"
 						WHERE LOWER(first_name) = LOWER($1)
 						AND LOWER(last_name) = LOWER($2)
 						LIMIT 1`,
 		person.FirstName,
 		person.LastName)
 	if err != nil || !rows.Next() {
-		return models.Person{}, fmt.Errorf("failed to retreive id: %w", err)
+		err = fmt.Errorf("failed to retreive id: %w", err)
+		return models.Person{}, err
 	}
 	rows.Scan(&person.ID)
 	rows.Close()
-	//2. use ID to do select of courses from person_course
-	rows, err = tx.Query(`SELECT * FROM "person_course"
-						WHERE person_id = $1`,
-		person.ID)
-	if err != nil {
-		return models.Person{}, fmt.Errorf("failed to retreive course list: %w", err)
-	}
-	//3. form an array of all classes they're currently in
-	currentCourses := make([]int, 0)
-	for rows.Next() {
-		var pID int
-		var cID int
-		rows.Scan(&pID, &cID)
-		currentCourses = append(currentCourses, cID)
-	}
-	rows.Close()
-	//4. do a delete query on the ones not in the new person course list
-	coursesToDelete := getDifference(currentCourses, person.Courses)
-	if len(coursesToDelete) > 0 {
-		_, err = tx.Exec(`DELETE FROM "person_course" 
-		WHERE person_id = $1 
-		AND course_id = ANY ($2::int[])`,
-			person.ID,
-			pq.Array(coursesToDelete))
-
-		if err != nil {
-			return models.Person{}, fmt.Errorf("failed to update course list: %w", err)
-		}
-	}
-	//5. Validate the courses they want to be added to actually exist
-	coursesToInsert := getDifference(person.Courses, currentCourses)
-
-	rows, err = tx.Query(`SELECT id FROM "course"`)
-	if err != nil {
-		return models.Person{}, fmt.Errorf("failed to retreive course list: %w", err)
-	}
-
-	courseIDs := make(map[int]bool)
-	for rows.Next() {
-		var courseID int
-		rows.Scan(&courseID)
-		courseIDs[courseID] = true
-	}
-	for _, val := range coursesToInsert {
-		if !courseIDs[val] {
-			return models.Person{}, fmt.Errorf("course not found, trying to join a course that doesn't exist")
-		}
-	}
-	rows.Close()
-	//6. do an insert query on the ones not currently in the table
-	var sb strings.Builder
-	if len(coursesToInsert) > 0 {
-		sb.WriteString("(" + strconv.Itoa(person.ID) + ", " + strconv.Itoa(coursesToInsert[0]) + ")")
-		for i := 1; i < len(coursesToInsert); i++ {
-			sb.WriteString(", (" + strconv.Itoa(person.ID) + ", " + strconv.Itoa(coursesToInsert[i]) + ")")
-		}
-	}
-	if sb.String() != "" {
-		query := `INSERT INTO "person_course" (person_id, course_id) VALUES ` + sb.String()
-		_, err = tx.Exec(query)
-		if err != nil {
-			return models.Person{}, fmt.Errorf("failed to update course list: %w", err)
-		}
+	//2-6. sync the person's rows in person_course with the requested course list
+	if err = updatePersonCourses(ctx, tx, person.ID, person.Courses); err != nil {
+		return models.Person{}, err
 	}
 
 	if err = tx.Commit(); err != nil {
-		return models.Person{}, fmt.Errorf("failed to commit transaction: %w", err)
+		return models.Person{}, fmt.Errorf("failed to commit transaction: %w", mapDBError(err))
 	}
 	return person, nil
 }
-func (p *RealPersonService) CreatePerson(person models.Person) (int, error) {
-	tx, err := p.db.Begin()
+func (p *RealPersonService) CreatePerson(ctx context.Context, person models.Person) (int, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
 		return -1, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func() {
 		if err != nil {
-			tx.Rollback()
+			rollback(ctx, tx)
 		}
 	}()
 	//insert person into table
-	row, err := tx.Query(`INSERT INTO "person" (first_name, last_name, type, age)
+	row, err := tx.QueryContext(ctx, `INSERT INTO "person" (first_name, last_name, type, age)
 							VALUES ($1, $2, $3, $4) RETURNING id`,
 		person.FirstName,
 		person.LastName,
 		person.Type,
 		person.Age)
 	if err != nil {
-		return -1, fmt.Errorf("failed to create person: %w", err)
+		return -1, fmt.Errorf("failed to create person: %w", mapDBError(err))
 	}
 	var lastInsertedID = -1
 	row.Next()
@@ -281,7 +192,7 @@ func (p *RealPersonService) CreatePerson(person models.Person) (int, error) {
 	}
 	row.Close()
 	//validate all courses to insert exist
-	rows, err := tx.Query(`SELECT id FROM "course"`)
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM "course"`)
 	if err != nil {
 		return -1, fmt.Errorf("failed to retreive course list: %w", err)
 	}
@@ -295,7 +206,8 @@ func (p *RealPersonService) CreatePerson(person models.Person) (int, error) {
 	rows.Close()
 	for _, val := range person.Courses {
 		if !courseIDs[val] {
-			return -1, fmt.Errorf("course not found, trying to join a course that doesn't exist")
+			err = ErrUnknownCourse
+			return -1, err
 		}
 	}
 	//inserting to person_courses. For this iteration, we assume the person is not
@@ -310,33 +222,33 @@ func (p *RealPersonService) CreatePerson(person models.Person) (int, error) {
 	}
 	if sb.String() != "" {
 		query := `INSERT INTO "person_course" (person_id, course_id) VALUES ` + sb.String()
-		_, err = tx.Exec(query)
+		_, err = tx.ExecContext(ctx, query)
 		if err != nil {
-			return -1, fmt.Errorf("failed to update course list: %w", err)
+			return -1, fmt.Errorf("failed to update course list: %w", mapDBError(err))
 		}
 	}
 
 	if err = tx.Commit(); err != nil {
-		return -1, fmt.Errorf("failed to commit transaction: %w", err)
+		return -1, fmt.Errorf("failed to commit transaction: %w", mapDBError(err))
 	}
 
 	return lastInsertedID, nil
 }
 
 // This is really bad architecture. Because firstName and lastName do not constitute a unique key, this function could delete multiple users.
-func (p *RealPersonService) DeletePerson(firstName string, lastName string) (int64, error) {
-	tx, err := p.db.Begin()
+func (p *RealPersonService) DeletePerson(ctx context.Context, firstName string, lastName string) (int64, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
 		return -1, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func() {
 		if err != nil {
-			tx.Rollback()
+			rollback(ctx, tx)
 		}
 	}()
 
 	//get person's id
-	rows, err := tx.Query(`SELECT id FROM "person"
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM "person"
 						WHERE LOWER("first_name") = LOWER($1)
 						AND LOWER("last_name") = LOWER($2)
 						LIMIT 1`, firstName, lastName)
@@ -346,7 +258,8 @@ func (p *RealPersonService) DeletePerson(firstName string, lastName string) (int
 	}
 	var personID int
 	if !rows.Next() {
-		return -1, fmt.Errorf("person not found")
+		err = ErrPersonNotFound
+		return -1, err
 	}
 	rows.Scan(&personID)
 	rows.Close()
@@ -357,19 +270,178 @@ func (p *RealPersonService) DeletePerson(firstName string, lastName string) (int
 	//we will delete the wrong one.
 	//In the future, this API should change to using id since it is the table's primary key or have another way to uniquely identify person entities.
 
-	_, err = tx.Exec(`DELETE FROM "person_course"
+	_, err = tx.ExecContext(ctx, `DELETE FROM "person_course"
 						WHERE "person_id" = $1`,
 		personID)
 	if err != nil {
-		return -1, fmt.Errorf("failed to delete course relations: %w", err)
+		return -1, fmt.Errorf("failed to delete course relations: %w", mapDBError(err))
 	}
 	//delete from person
 
-	result, err := tx.Exec(`DELETE FROM "person"
+	result, err := tx.ExecContext(ctx, `DELETE FROM "person"
 						WHERE "id" = $1`,
 		personID)
 	if err != nil {
-		return -1, fmt.Errorf("failed to delete person with ID: %v. %w", personID, err)
+		return -1, fmt.Errorf("failed to delete person with ID: %v. %w", personID, mapDBError(err))
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return -1, fmt.Errorf("failed to get the number of affected rows: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return -1, fmt.Errorf("failed to commit transaction: %w", mapDBError(err))
+	}
+	return rowsAffected, nil
+}
+
+// GetPersonByID returns the person with the given id. An empty models.Person is returned if no person has that id.
+func (p *RealPersonService) GetPersonByID(ctx context.Context, id int) (models.Person, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT `+personColumns+` FROM "person"
+	WHERE "id" = $1
+	LIMIT 1`,
+		id)
+	if err != nil {
+		return models.Person{}, fmt.Errorf("failed to get person: %w", err)
+	}
+	defer rows.Close()
+
+	if isEmpty := !rows.Next(); isEmpty {
+		return models.Person{}, nil
+	}
+	person, err := scanPerson(rows)
+	if err != nil {
+		return models.Person{}, fmt.Errorf("failed to scan person: %w", err)
+	}
+	return person, nil
+}
+
+// UpdatePersonByID overwrites the person with the given id and syncs their course list.
+func (p *RealPersonService) UpdatePersonByID(ctx context.Context, id int, person models.Person) (models.Person, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Person{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx)
+		}
+	}()
+
+	row, err := tx.ExecContext(ctx, `UPDATE "person" 
+	SET "first_name" = $1,
+		"last_name" = $2,
+		"type" = $3,
+		"age" = $4
+	WHERE "id" = $5`,
+		person.FirstName,
+		person.LastName,
+		person.Type,
+		person.Age,
+		id,
+	)
+	if err != nil {
+		return models.Person{}, fmt.Errorf("failed to update person: %w", mapDBError(err))
+	}
+	rowsAffected, err := row.RowsAffected()
+	if err != nil {
+		return models.Person{}, fmt.Errorf("failed to update person: %w", mapDBError(err))
+	}
+	if rowsAffected == 0 {
+		err = ErrPersonNotFound
+		return models.Person{}, err
+	}
+
+	person.ID = id
+	if err = updatePersonCourses(ctx, tx, person.ID, person.Courses); err != nil {
+		return models.Person{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.Person{}, fmt.Errorf("failed to commit transaction: %w", mapDBError(err))
+	}
+	return person, nil
+}
+
+// PatchPersonByID writes only the columns and enrollments patch changes on the person with the given id, then
+// returns the stored person.
+func (p *RealPersonService) PatchPersonByID(ctx context.Context, id int, patch PersonPatch) (models.Person, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Person{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx)
+		}
+	}()
+
+	if columns := patch.columns(); len(columns) > 0 {
+		sets, args := setClause(columns)
+		args = append(args, id)
+		var row sql.Result
+		row, err = tx.ExecContext(ctx, `UPDATE "person" SET `+sets+` WHERE "id" = $`+strconv.Itoa(len(args)), args...)
+		if err != nil {
+			return models.Person{}, fmt.Errorf("failed to update person: %w", mapDBError(err))
+		}
+		var rowsAffected int64
+		rowsAffected, err = row.RowsAffected()
+		if err != nil {
+			return models.Person{}, fmt.Errorf("failed to update person: %w", mapDBError(err))
+		}
+		if rowsAffected == 0 {
+			err = ErrPersonNotFound
+			return models.Person{}, err
+		}
+	} else {
+		var found int
+		err = tx.QueryRowContext(ctx, `SELECT id FROM "person" WHERE "id" = $1`, id).Scan(&found)
+		if err == sql.ErrNoRows {
+			err = ErrPersonNotFound
+			return models.Person{}, err
+		}
+		if err != nil {
+			return models.Person{}, fmt.Errorf("failed to get person: %w", err)
+		}
+	}
+
+	if patch.Courses != nil {
+		if err = updatePersonCourses(ctx, tx, id, *patch.Courses); err != nil {
+			return models.Person{}, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.Person{}, fmt.Errorf("failed to commit transaction: %w", mapDBError(err))
+	}
+	return p.GetPersonByID(ctx, id)
+}
+
+// DeletePersonByID removes the person with the given id and all of their course relations. It returns the number of
+// people deleted, which is 0 if no person has that id.
+func (p *RealPersonService) DeletePersonByID(ctx context.Context, id int) (int64, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx)
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM "person_course"
+						WHERE "person_id" = $1`,
+		id)
+	if err != nil {
+		return -1, fmt.Errorf("failed to delete course relations: %w", mapDBError(err))
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM "person"
+						WHERE "id" = $1`,
+		id)
+	if err != nil {
+		return -1, fmt.Errorf("failed to delete person with ID: %v. %w", id, mapDBError(err))
 	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
@@ -377,7 +449,7 @@ func (p *RealPersonService) DeletePerson(firstName string, lastName string) (int
 	}
 
 	if err = tx.Commit(); err != nil {
-		return -1, fmt.Errorf("failed to commit transaction: %w", err)
+		return -1, fmt.Errorf("failed to commit transaction: %w", mapDBError(err))
 	}
 	return rowsAffected, nil
 }
@@ -4,15 +4,16 @@ package services
 //While TBTs would reduce repeated code, they would contain an overabundance of if statements and be less accessible to understand.
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"fmt"
 	"regexp"
 	"tech-challenge/internal/models"
 	"tech-challenge/internal/testutil"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,45 +24,28 @@ import (
 // NoArgsSuccess
 // EmptyListSuccess
 // FailedToGetPeople
-// FailedToGetCoursesForPerson
+// FailedToScanPerson
+// ContextCanceledFailure
 func (s *testSuit) TestGetAllPeopleNameAgeSuccess() {
 	t := s.T()
 
 	people := []PersonDTO{
-		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222},
-		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18},
-	}
-
-	person_course := []Person_Course{
-		{PersonID: 0, CourseID: 1},
-		{PersonID: 0, CourseID: 2},
-		{PersonID: 0, CourseID: 3},
-		{PersonID: 1, CourseID: 1},
-		{PersonID: 1, CourseID: 2},
-		{PersonID: 1, CourseID: 3},
-		{PersonID: 2, CourseID: 1},
-		{PersonID: 2, CourseID: 2},
-		{PersonID: 2, CourseID: 3},
-		{PersonID: 3, CourseID: 1},
-		{PersonID: 3, CourseID: 2},
-		{PersonID: 3, CourseID: 3},
+		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222, Courses: "1,2,3"},
+		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "1,2,3"},
 	}
 
 	returnRowsPersonQuery := testutil.MustStructsToRows(people[3:])
-	returnRowsMapQuery := testutil.MustStructsToRows(person_course[9:])
 	firstName := "Bubbles"
 	lastName := "Thane"
 	age := 18
 	returnFinal := []models.Person{{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: []int{1, 2, 3}}}
 
-	query := `SELECT * FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) AND age = $3`
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) AND age = $3`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(firstName, lastName, age).WillReturnRows(returnRowsPersonQuery).WillReturnError(nil)
-	query = `SELECT * FROM "person_course" WHERE person_id = $1`
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery).WillReturnError(nil)
 
-	result, err := s.personService.GetAllPeople(age, firstName, lastName)
+	result, _, err := s.personService.GetAllPeople(context.Background(), PersonFilter{FirstName: firstName, LastName: lastName, Age: &age}, ListOptions{})
 
 	assert.Equal(t, returnFinal, result)
 	assert.Equal(t, err, nil)
@@ -72,39 +56,20 @@ func (s *testSuit) TestGetAllPeopleNameSuccess() {
 	t := s.T()
 
 	people := []PersonDTO{
-		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222},
-		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18},
-	}
-
-	person_course := []Person_Course{
-		{PersonID: 0, CourseID: 1},
-		{PersonID: 0, CourseID: 2},
-		{PersonID: 0, CourseID: 3},
-		{PersonID: 1, CourseID: 1},
-		{PersonID: 1, CourseID: 2},
-		{PersonID: 1, CourseID: 3},
-		{PersonID: 2, CourseID: 1},
-		{PersonID: 2, CourseID: 2},
-		{PersonID: 2, CourseID: 3},
-		{PersonID: 3, CourseID: 1},
-		{PersonID: 3, CourseID: 2},
-		{PersonID: 3, CourseID: 3},
+		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222, Courses: "1,2,3"},
+		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "1,2,3"},
 	}
 
 	returnRowsPersonQuery := testutil.MustStructsToRows(people[3:])
-	returnRowsMapQuery := testutil.MustStructsToRows(person_course[9:])
 	firstName := "Bubbles"
 	lastName := "Thane"
-	age := -1
 	returnFinal := []models.Person{{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: []int{1, 2, 3}}}
 
-	query := `SELECT * FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2)`
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2)`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(firstName, lastName).WillReturnRows(returnRowsPersonQuery).WillReturnError(nil)
-	query = `SELECT * FROM "person_course" WHERE person_id = $1`
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery).WillReturnError(nil)
-	result, err := s.personService.GetAllPeople(age, firstName, lastName)
+	result, _, err := s.personService.GetAllPeople(context.Background(), PersonFilter{FirstName: firstName, LastName: lastName}, ListOptions{})
 
 	assert.Equal(t, returnFinal, result)
 	assert.Equal(t, err, nil)
@@ -115,43 +80,21 @@ func (s *testSuit) TestGetAllPeopleAgeSuccess() {
 	t := s.T()
 
 	people := []PersonDTO{
-		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222},
-		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18},
-	}
-
-	person_course := []Person_Course{
-		{PersonID: 0, CourseID: 1},
-		{PersonID: 0, CourseID: 2},
-		{PersonID: 0, CourseID: 3},
-		{PersonID: 1, CourseID: 1},
-		{PersonID: 1, CourseID: 2},
-		{PersonID: 1, CourseID: 3},
-		{PersonID: 2, CourseID: 1},
-		{PersonID: 2, CourseID: 2},
-		{PersonID: 2, CourseID: 3},
-		{PersonID: 3, CourseID: 1},
-		{PersonID: 3, CourseID: 2},
-		{PersonID: 3, CourseID: 3},
+		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222, Courses: "1,2,3"},
+		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "1,2,3"},
 	}
 
 	returnRowsPersonQuery := testutil.MustStructsToRows(people[:2])
-	returnRowsMapQuery1 := testutil.MustStructsToRows(person_course[:3])
-	returnRowsMapQuery2 := testutil.MustStructsToRows(person_course[3:6])
-	firstName := ""
-	lastName := ""
 	age := 22
 	returnFinal := []models.Person{{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1, 2, 3}},
 		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1, 2, 3}},
 	}
 
-	query := `SELECT * FROM "person" WHERE age = $1`
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE age = $1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(age).WillReturnRows(returnRowsPersonQuery).WillReturnError(nil)
-	query = `SELECT * FROM "person_course" WHERE person_id = $1`
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery1).WillReturnError(nil)
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery2).WillReturnError(nil)
-	result, err := s.personService.GetAllPeople(age, firstName, lastName)
+	result, _, err := s.personService.GetAllPeople(context.Background(), PersonFilter{Age: &age}, ListOptions{})
 
 	assert.Equal(t, returnFinal, result)
 	assert.Equal(t, err, nil)
@@ -162,49 +105,22 @@ func (s *testSuit) TestGetAllPeopleNoArgsSuccess() {
 	t := s.T()
 
 	people := []PersonDTO{
-		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222},
-		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18},
-	}
-
-	person_course := []Person_Course{
-		{PersonID: 0, CourseID: 1},
-		{PersonID: 0, CourseID: 2},
-		{PersonID: 0, CourseID: 3},
-		{PersonID: 1, CourseID: 1},
-		{PersonID: 1, CourseID: 2},
-		{PersonID: 1, CourseID: 3},
-		{PersonID: 2, CourseID: 1},
-		{PersonID: 2, CourseID: 2},
-		{PersonID: 2, CourseID: 3},
-		{PersonID: 3, CourseID: 1},
-		{PersonID: 3, CourseID: 2},
-		{PersonID: 3, CourseID: 3},
+		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222, Courses: "1,2,3"},
+		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "1,2,3"},
 	}
 
 	returnRowsPersonQuery := testutil.MustStructsToRows(people)
-	returnRowsMapQuery1 := testutil.MustStructsToRows(person_course[:3])
-	returnRowsMapQuery2 := testutil.MustStructsToRows(person_course[3:6])
-	returnRowsMapQuery3 := testutil.MustStructsToRows(person_course[6:9])
-	returnRowsMapQuery4 := testutil.MustStructsToRows(person_course[9:12])
-	firstName := ""
-	lastName := ""
-	age := -1
 	returnFinal := []models.Person{{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1, 2, 3}},
 		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{1, 2, 3}},
 		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222, Courses: []int{1, 2, 3}},
 		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: []int{1, 2, 3}},
 	}
 
-	query := `SELECT * FROM "person"`
+	query := `SELECT ` + personColumns + ` FROM "person"`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsPersonQuery).WillReturnError(nil)
-	query = `SELECT * FROM "person_course" WHERE person_id = $1`
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery1).WillReturnError(nil)
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery2).WillReturnError(nil)
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery3).WillReturnError(nil)
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery4).WillReturnError(nil)
-	result, err := s.personService.GetAllPeople(age, firstName, lastName)
+	result, _, err := s.personService.GetAllPeople(context.Background(), PersonFilter{}, ListOptions{})
 
 	assert.Equal(t, returnFinal, result)
 	assert.Equal(t, err, nil)
@@ -214,14 +130,11 @@ func (s *testSuit) TestGetAllPeopleNoArgsSuccess() {
 func (s *testSuit) TestGetAllPeopleEmptySuccess() {
 	t := s.T()
 
-	firstName := ""
-	lastName := ""
-	age := -1
 	returnFinal := []models.Person(nil)
 
-	query := `SELECT * FROM "person"`
+	query := `SELECT ` + personColumns + ` FROM "person"`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(&sqlmock.Rows{}).WillReturnError(nil)
-	result, err := s.personService.GetAllPeople(age, firstName, lastName)
+	result, _, err := s.personService.GetAllPeople(context.Background(), PersonFilter{}, ListOptions{})
 
 	assert.Equal(t, returnFinal, result)
 	assert.Equal(t, err, nil)
@@ -232,71 +145,59 @@ func (s *testSuit) TestGetAllPeopleGetPeopleFailure() {
 	t := s.T()
 
 	people := []PersonDTO{
-		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222},
-		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18},
+		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222, Courses: "1,2,3"},
+		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "1,2,3"},
 	}
 
 	returnRowsPersonQuery := testutil.MustStructsToRows(people[:2])
-	firstName := ""
-	lastName := ""
 	age := 22
 	returnFinal := []models.Person{}
 	returnErr := fmt.Errorf("failed to get people: %w", errors.New("can't get people"))
 
-	query := `SELECT * FROM "person" WHERE age = $1`
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE age = $1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(age).WillReturnRows(returnRowsPersonQuery).WillReturnError(errors.New("can't get people"))
-	result, err := s.personService.GetAllPeople(age, firstName, lastName)
+	result, _, err := s.personService.GetAllPeople(context.Background(), PersonFilter{Age: &age}, ListOptions{})
 
 	assert.Equal(t, returnFinal, result)
 	assert.Equal(t, err, returnErr)
 	err = s.dbMock.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
-func (s *testSuit) TestGetAllPeopleGetCoursesFailure() {
+func (s *testSuit) TestGetAllPeopleScanFailure() {
 	t := s.T()
 
 	people := []PersonDTO{
-		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222},
-		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18},
-	}
-
-	person_course := []Person_Course{
-		{PersonID: 0, CourseID: 1},
-		{PersonID: 0, CourseID: 2},
-		{PersonID: 0, CourseID: 3},
-		{PersonID: 1, CourseID: 1},
-		{PersonID: 1, CourseID: 2},
-		{PersonID: 1, CourseID: 3},
-		{PersonID: 2, CourseID: 1},
-		{PersonID: 2, CourseID: 2},
-		{PersonID: 2, CourseID: 3},
-		{PersonID: 3, CourseID: 1},
-		{PersonID: 3, CourseID: 2},
-		{PersonID: 3, CourseID: 3},
+		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "not a list"},
+		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: "not a list"},
+		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222, Courses: "1,2,3"},
+		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "not a list"},
 	}
 
 	returnRowsPersonQuery := testutil.MustStructsToRows(people[:2])
-	returnRowsMapQuery1 := testutil.MustStructsToRows(person_course[:3])
-	returnRowsMapQuery2 := testutil.MustStructsToRows(person_course[3:6])
-	firstName := ""
-	lastName := ""
 	age := 22
 	returnFinal := []models.Person{}
-	returnErr := fmt.Errorf("failed to get courses for person: %w", errors.New("can't get courses"))
 
-	query := `SELECT * FROM "person" WHERE age = $1`
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE age = $1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(age).WillReturnRows(returnRowsPersonQuery).WillReturnError(nil)
-	query = `SELECT * FROM "person_course" WHERE person_id = $1`
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery1).WillReturnError(nil)
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery2).WillReturnError(errors.New("can't get courses"))
-	result, err := s.personService.GetAllPeople(age, firstName, lastName)
+	result, _, err := s.personService.GetAllPeople(context.Background(), PersonFilter{Age: &age}, ListOptions{})
 
 	assert.Equal(t, returnFinal, result)
-	assert.Equal(t, returnErr, err)
+	assert.ErrorContains(t, err, "failed to scan person from row")
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func (s *testSuit) TestGetAllPeopleContextCanceledFailure() {
+	t := s.T()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, _, err := s.personService.GetAllPeople(ctx, PersonFilter{}, ListOptions{})
+
+	assert.Equal(t, []models.Person{}, result)
+	assert.ErrorIs(t, err, context.Canceled)
 	err = s.dbMock.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
@@ -305,43 +206,25 @@ func (s *testSuit) TestGetAllPeopleGetCoursesFailure() {
 // Get Person Exists Success
 // Get Person Empty Success
 // Get Person Get Person Fail
-// Get Person Get Course Fail
+// Get Person Scan Fail
 func (s *testSuit) TestGetPersonExistsSuccess() {
 	t := s.T()
 
 	people := []PersonDTO{
-		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222},
-		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18},
-	}
-
-	person_course := []Person_Course{
-		{PersonID: 0, CourseID: 1},
-		{PersonID: 0, CourseID: 2},
-		{PersonID: 0, CourseID: 3},
-		{PersonID: 1, CourseID: 1},
-		{PersonID: 1, CourseID: 2},
-		{PersonID: 1, CourseID: 3},
-		{PersonID: 2, CourseID: 1},
-		{PersonID: 2, CourseID: 2},
-		{PersonID: 2, CourseID: 3},
-		{PersonID: 3, CourseID: 1},
-		{PersonID: 3, CourseID: 2},
-		{PersonID: 3, CourseID: 3},
+		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222, Courses: "1,2,3"},
+		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "1,2,3"},
 	}
 
 	returnRowsPersonQuery := testutil.MustStructsToRows(people[3:])
-	returnRowsMapQuery := testutil.MustStructsToRows(person_course[9:])
 	firstName := "Bubbles"
 	lastName := "Thane"
 	returnFinal := models.Person{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: []int{1, 2, 3}}
 
-	query := `SELECT * FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) LIMIT 1`
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) LIMIT 1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(firstName, lastName).WillReturnRows(returnRowsPersonQuery).WillReturnError(nil)
-	query = `SELECT * FROM "person_course" WHERE person_id = $1`
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery).WillReturnError(nil)
-	result, err := s.personService.GetPerson(firstName, lastName)
+	result, err := s.personService.GetPerson(context.Background(), firstName, lastName)
 
 	assert.Equal(t, returnFinal, result)
 	assert.Equal(t, nil, err)
@@ -355,9 +238,9 @@ func (s *testSuit) TestGetPersonDoesntExistSuccess() {
 	lastName := "NotAProfessor"
 	returnFinal := models.Person{}
 
-	query := `SELECT * FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) LIMIT 1`
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) LIMIT 1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(firstName, lastName).WillReturnRows(&sqlmock.Rows{}).WillReturnError(nil)
-	result, err := s.personService.GetPerson(firstName, lastName)
+	result, err := s.personService.GetPerson(context.Background(), firstName, lastName)
 
 	assert.Equal(t, returnFinal, result)
 	assert.Equal(t, nil, err)
@@ -368,10 +251,10 @@ func (s *testSuit) TestGetPersonGetPersonFailure() {
 	t := s.T()
 
 	people := []PersonDTO{
-		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222},
-		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18},
+		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: "1,2,3"},
+		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222, Courses: "1,2,3"},
+		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "1,2,3"},
 	}
 
 	returnRowsPersonQuery := testutil.MustStructsToRows(people[3:])
@@ -380,55 +263,36 @@ func (s *testSuit) TestGetPersonGetPersonFailure() {
 	returnFinal := models.Person{}
 	returnErr := fmt.Errorf("failed to get person: %w", errors.New("can't get person"))
 
-	query := `SELECT * FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) LIMIT 1`
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) LIMIT 1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(firstName, lastName).WillReturnRows(returnRowsPersonQuery).WillReturnError(errors.New("can't get person"))
-	result, err := s.personService.GetPerson(firstName, lastName)
+	result, err := s.personService.GetPerson(context.Background(), firstName, lastName)
 
 	assert.Equal(t, returnFinal, result)
 	assert.Equal(t, returnErr, err)
 	err = s.dbMock.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
-func (s *testSuit) TestGetPersonGetCourseFailure() {
+func (s *testSuit) TestGetPersonScanFailure() {
 	t := s.T()
 
 	people := []PersonDTO{
-		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22},
-		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222},
-		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18},
-	}
-
-	person_course := []Person_Course{
-		{PersonID: 0, CourseID: 1},
-		{PersonID: 0, CourseID: 2},
-		{PersonID: 0, CourseID: 3},
-		{PersonID: 1, CourseID: 1},
-		{PersonID: 1, CourseID: 2},
-		{PersonID: 1, CourseID: 3},
-		{PersonID: 2, CourseID: 1},
-		{PersonID: 2, CourseID: 2},
-		{PersonID: 2, CourseID: 3},
-		{PersonID: 3, CourseID: 1},
-		{PersonID: 3, CourseID: 2},
-		{PersonID: 3, CourseID: 3},
+		{ID: 0, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: "not a list"},
+		{ID: 1, FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 22, Courses: "not a list"},
+		{ID: 2, FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 222, Courses: "1,2,3"},
+		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "not a list"},
 	}
 
 	returnRowsPersonQuery := testutil.MustStructsToRows(people[3:])
-	returnRowsMapQuery := testutil.MustStructsToRows(person_course[9:])
 	firstName := "Bubbles"
 	lastName := "Thane"
 	returnFinal := models.Person{}
-	returnErr := fmt.Errorf("failed to get courses for person: %w", errors.New("can't get course"))
 
-	query := `SELECT * FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) LIMIT 1`
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) LIMIT 1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(firstName, lastName).WillReturnRows(returnRowsPersonQuery).WillReturnError(nil)
-	query = `SELECT * FROM "person_course" WHERE person_id = $1`
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(returnRowsMapQuery).WillReturnError(errors.New("can't get course"))
-	result, err := s.personService.GetPerson(firstName, lastName)
+	result, err := s.personService.GetPerson(context.Background(), firstName, lastName)
 
 	assert.Equal(t, returnFinal, result)
-	assert.Equal(t, returnErr, err)
+	assert.ErrorContains(t, err, "failed to scan person")
 	err = s.dbMock.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
@@ -489,15 +353,15 @@ func (s *testSuit) TestUpdatePersonChangeAllSuccess() {
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Bubbly", "Thane").WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 3}}))
 	query = `SELECT * FROM "person_course" WHERE person_id = $1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(person_course[9:]))
-	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id = ANY ($2::int[])`
-	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, pq.Array([]int{1, 2})).WillReturnResult(sqlmock.NewResult(1, 1))
+	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id IN ($2, $3)`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, 1, 2).WillReturnResult(sqlmock.NewResult(1, 1))
 	query = `SELECT id FROM "course"`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}))
 	query = `INSERT INTO "person_course" (person_id, course_id) VALUES (3, 4), (3, 5)`
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(1, 1))
 	s.dbMock.ExpectCommit()
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", inputPerson)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", inputPerson)
 	assert.Equal(t, returnPerson, updatedPerson)
 	assert.NoError(t, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -508,14 +372,14 @@ func (s *testSuit) TestUpdatePersonNotFoundFailure() {
 
 	personInput := models.Person{ID: 3, FirstName: "Bubbly", LastName: "Thane", Type: "student", Age: 19, Courses: []int{3, 4, 5}}
 	updateInput := []driver.Value{"Bubbly", "Thane", "student", 19, "Bubbles", "Thane"}
-	returnErr := fmt.Errorf("person not found")
+	returnErr := ErrPersonNotFound
 	returnPerson := models.Person{}
 
 	s.dbMock.ExpectBegin()
 	query := `UPDATE "person" SET "first_name" = $1, "last_name" = $2, "type" = $3, "age" = $4 WHERE LOWER(first_name) = LOWER($5) AND LOWER(last_name) = LOWER($6)`
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(updateInput...).WillReturnResult(sqlmock.NewResult(3, 0))
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", personInput)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", personInput)
 	assert.Equal(t, returnPerson, updatedPerson)
 	assert.Equal(t, returnErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -533,7 +397,7 @@ func (s *testSuit) TestUpdatePersonUpdatePersonFailure() {
 	query := `UPDATE "person" SET "first_name" = $1, "last_name" = $2, "type" = $3, "age" = $4 WHERE LOWER(first_name) = LOWER($5) AND LOWER(last_name) = LOWER($6)`
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(updateInput...).WillReturnResult(sqlmock.NewResult(3, 0)).WillReturnError(errors.New("can't update person"))
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", personInput)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", personInput)
 	assert.Equal(t, returnPerson, updatedPerson)
 	assert.Equal(t, returnErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -553,7 +417,7 @@ func (s *testSuit) TestUpdatePersonGetIDFailure() {
 	query = `SELECT id FROM "person" WHERE LOWER(first_name) = LOWER($1) AND LOWER(last_name) = LOWER($2) LIMIT 1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Bubbly", "Thane").WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 3}})).WillReturnError(errors.New("can't get ID"))
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", inputPerson)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", inputPerson)
 	assert.Equal(t, returnPerson, updatedPerson)
 	assert.Equal(t, returnErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -590,7 +454,7 @@ func (s *testSuit) TestUpdatePersonGetMapListFailure() {
 	query = `SELECT * FROM "person_course" WHERE person_id = $1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(person_course[9:])).WillReturnError(errors.New("can't get map"))
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", inputPerson)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", inputPerson)
 	assert.Equal(t, returnPerson, updatedPerson)
 	assert.Error(t, returnErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -626,10 +490,10 @@ func (s *testSuit) TestUpdatePersonDeleteCoursesFailure() {
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Bubbly", "Thane").WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 3}}))
 	query = `SELECT * FROM "person_course" WHERE person_id = $1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(person_course[9:]))
-	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id = ANY ($2::int[])`
-	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, pq.Array([]int{1, 2})).WillReturnResult(sqlmock.NewResult(1, 1)).WillReturnError(errors.New("can't delete"))
+	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id IN ($2, $3)`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, 1, 2).WillReturnResult(sqlmock.NewResult(1, 1)).WillReturnError(errors.New("can't delete"))
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", inputPerson)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", inputPerson)
 	assert.Equal(t, returnPerson, updatedPerson)
 	assert.Equal(t, returnErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -665,12 +529,12 @@ func (s *testSuit) TestUpdatePersonGetCourseListFailure() {
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Bubbly", "Thane").WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 3}}))
 	query = `SELECT * FROM "person_course" WHERE person_id = $1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(person_course[9:]))
-	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id = ANY ($2::int[])`
-	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, pq.Array([]int{1, 2})).WillReturnResult(sqlmock.NewResult(1, 1))
+	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id IN ($2, $3)`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, 1, 2).WillReturnResult(sqlmock.NewResult(1, 1))
 	query = `SELECT id FROM "course"`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}})).WillReturnError(errors.New("can't get courses"))
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", inputPerson)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", inputPerson)
 	assert.Equal(t, returnPerson, updatedPerson)
 	assert.Equal(t, returnErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -697,7 +561,7 @@ func (s *testSuit) TestUpdatePersonCourseNotFoundFailure() {
 	inputPerson := models.Person{ID: 3, FirstName: "Bubbly", LastName: "Thane", Type: "student", Age: 19, Courses: []int{3, 4, 5, 6}}
 	updateInput := []driver.Value{"Bubbly", "Thane", "student", 19, "Bubbles", "Thane"}
 	returnPerson := models.Person{}
-	returnErr := fmt.Errorf("course not found, trying to join a course that doesn't exist")
+	returnErr := ErrUnknownCourse
 
 	s.dbMock.ExpectBegin()
 	query := `UPDATE "person" SET "first_name" = $1, "last_name" = $2, "type" = $3, "age" = $4 WHERE LOWER(first_name) = LOWER($5) AND LOWER(last_name) = LOWER($6)`
@@ -706,12 +570,12 @@ func (s *testSuit) TestUpdatePersonCourseNotFoundFailure() {
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Bubbly", "Thane").WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 3}}))
 	query = `SELECT * FROM "person_course" WHERE person_id = $1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(person_course[9:]))
-	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id = ANY ($2::int[])`
-	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, pq.Array([]int{1, 2})).WillReturnResult(sqlmock.NewResult(1, 1))
+	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id IN ($2, $3)`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, 1, 2).WillReturnResult(sqlmock.NewResult(1, 1))
 	query = `SELECT id FROM "course"`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}))
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", inputPerson)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", inputPerson)
 	assert.Equal(t, returnPerson, updatedPerson)
 	assert.Equal(t, returnErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -747,14 +611,14 @@ func (s *testSuit) TestUpdatePersonUpdateCoursesFailure() {
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Bubbly", "Thane").WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 3}}))
 	query = `SELECT * FROM "person_course" WHERE person_id = $1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(person_course[9:]))
-	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id = ANY ($2::int[])`
-	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, pq.Array([]int{1, 2})).WillReturnResult(sqlmock.NewResult(1, 1))
+	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id IN ($2, $3)`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, 1, 2).WillReturnResult(sqlmock.NewResult(1, 1))
 	query = `SELECT id FROM "course"`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}))
 	query = `INSERT INTO "person_course" (person_id, course_id) VALUES (3, 4), (3, 5)`
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(1, 1)).WillReturnError(errors.New("can't update courses"))
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", inputPerson)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", inputPerson)
 	assert.Equal(t, returnPerson, updatedPerson)
 	assert.Equal(t, returnErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -769,7 +633,7 @@ func (s *testSuit) TestUpdatePersonTransactionBeginFailure() {
 
 	s.dbMock.ExpectBegin().WillReturnError(errors.New("can't begin transaction"))
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", inputPerson)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", inputPerson)
 	assert.Equal(t, resultPerson, updatedPerson)
 	assert.Equal(t, resultErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -805,15 +669,15 @@ func (s *testSuit) TestUpdatePersonTransactionCommitFailure() {
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Bubbly", "Thane").WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 3}}))
 	query = `SELECT * FROM "person_course" WHERE person_id = $1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(person_course[9:]))
-	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id = ANY ($2::int[])`
-	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, pq.Array([]int{1, 2})).WillReturnResult(sqlmock.NewResult(1, 1))
+	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id IN ($2, $3)`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, 1, 2).WillReturnResult(sqlmock.NewResult(1, 1))
 	query = `SELECT id FROM "course"`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}))
 	query = `INSERT INTO "person_course" (person_id, course_id) VALUES (3, 4), (3, 5)`
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(1, 1))
 	s.dbMock.ExpectCommit().WillReturnError(errors.New("commit failed"))
 
-	updatedPerson, err := s.personService.UpdatePerson("Bubbles", "Thane", inputPerson)
+	updatedPerson, err := s.personService.UpdatePerson(context.Background(), "Bubbles", "Thane", inputPerson)
 	assert.Equal(t, returnPerson, updatedPerson)
 	assert.Equal(t, returnErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -846,7 +710,7 @@ func (s *testSuit) TestCreatePersonSuccess() {
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(1, 5))
 	s.dbMock.ExpectCommit()
 
-	insertedID, err := s.personService.CreatePerson(inputPerson)
+	insertedID, err := s.personService.CreatePerson(context.Background(), inputPerson)
 	assert.Equal(t, expectedInsertedID, insertedID)
 	assert.NoError(t, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -865,7 +729,7 @@ func (s *testSuit) TestCreatePersonFailure() {
 		WithArgs(inputPerson.FirstName, inputPerson.LastName, inputPerson.Type, inputPerson.Age).
 		WillReturnRows(sqlmock.NewRows([]string{"id"})).WillReturnError(errors.New("can't create person"))
 
-	insertedID, err := s.personService.CreatePerson(inputPerson)
+	insertedID, err := s.personService.CreatePerson(context.Background(), inputPerson)
 	assert.Equal(t, expectedInsertedID, insertedID)
 	assert.Equal(t, expectedErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -886,7 +750,7 @@ func (s *testSuit) TestCreatePersonGetCoursesFailure() {
 	query = `SELECT id FROM "course"`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}})).WillReturnError(errors.New("can't get courses"))
 
-	insertedID, err := s.personService.CreatePerson(inputPerson)
+	insertedID, err := s.personService.CreatePerson(context.Background(), inputPerson)
 	assert.Equal(t, expectedInsertedID, insertedID)
 	assert.Equal(t, expectedErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -897,7 +761,7 @@ func (s *testSuit) TestCreatePersonCourseNotFoundFailure() {
 
 	inputPerson := models.Person{FirstName: "Juniper", LastName: "Scott", Type: "student", Age: 25, Courses: []int{8}}
 	expectedInsertedID := -1
-	expectedErr := fmt.Errorf("course not found, trying to join a course that doesn't exist")
+	expectedErr := ErrUnknownCourse
 
 	s.dbMock.ExpectBegin()
 	query := `INSERT INTO "person" (first_name, last_name, type, age) VALUES ($1, $2, $3, $4) RETURNING id`
@@ -907,7 +771,7 @@ func (s *testSuit) TestCreatePersonCourseNotFoundFailure() {
 	query = `SELECT id FROM "course"`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}))
 
-	insertedID, err := s.personService.CreatePerson(inputPerson)
+	insertedID, err := s.personService.CreatePerson(context.Background(), inputPerson)
 	assert.Equal(t, expectedInsertedID, insertedID)
 	assert.Equal(t, expectedErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -930,7 +794,7 @@ func (s *testSuit) TestCreatePersonUpdateCourseFailure() {
 	query = `INSERT INTO "person_course" (person_id, course_id) VALUES (4, 1), (4, 2), (4, 3), (4, 4), (4, 5)`
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(int64(4), 5)).WillReturnError(errors.New("can't update courses"))
 
-	insertedID, err := s.personService.CreatePerson(inputPerson)
+	insertedID, err := s.personService.CreatePerson(context.Background(), inputPerson)
 	assert.Equal(t, expectedInsertedID, insertedID)
 	assert.Equal(t, expectedErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -943,7 +807,7 @@ func (s *testSuit) TestCreatePersonTransactionBeginFailure() {
 	expectedErr := fmt.Errorf("failed to begin transaction: %w", errors.New("can't begin transaction"))
 
 	s.dbMock.ExpectBegin().WillReturnError(errors.New("can't begin transaction"))
-	insertedID, err := s.personService.CreatePerson(inputPerson)
+	insertedID, err := s.personService.CreatePerson(context.Background(), inputPerson)
 	assert.Equal(t, -1, insertedID)
 	assert.Equal(t, expectedErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -968,7 +832,7 @@ func (s *testSuit) TestCreatePersonTransactionCommitFailure() {
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(1, 5))
 	s.dbMock.ExpectCommit().WillReturnError(errors.New("can't commit transaction"))
 
-	insertedID, err := s.personService.CreatePerson(inputPerson)
+	insertedID, err := s.personService.CreatePerson(context.Background(), inputPerson)
 	assert.Equal(t, expectedInsertedID, insertedID)
 	assert.Equal(t, expectedErr, err)
 	err = s.dbMock.ExpectationsWereMet()
@@ -1002,7 +866,7 @@ func (s *testSuit) TestDeletePersonSuccess() {
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(personID).WillReturnResult(sqlmock.NewResult(1, 1))
 	s.dbMock.ExpectCommit()
 
-	rowsAffected, err := s.personService.DeletePerson(firstName, lastName)
+	rowsAffected, err := s.personService.DeletePerson(context.Background(), firstName, lastName)
 	assert.NoError(t, err)
 	assert.Equal(t, rowsAffected, expectedRowsAffected)
 
@@ -1023,7 +887,7 @@ func (s *testSuit) TestDeletePersonGetIDFailure() {
 	query := `SELECT id FROM "person" WHERE LOWER("first_name") = LOWER($1) AND LOWER("last_name") = LOWER($2) LIMIT 1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(firstName, lastName).WillReturnRows(queryReturn).WillReturnError(errors.New("can't get IDs"))
 
-	rowsAffected, err := s.personService.DeletePerson(firstName, lastName)
+	rowsAffected, err := s.personService.DeletePerson(context.Background(), firstName, lastName)
 	assert.Equal(t, expectedErr, err)
 	assert.Equal(t, rowsAffected, expectedRowsAffected)
 
@@ -1037,13 +901,13 @@ func (s *testSuit) TestDeletePersonNotFoundFailure() {
 	lastName := "Thane"
 	expectedRowsAffected := int64(-1)
 	queryReturn := &sqlmock.Rows{}
-	expectedErr := fmt.Errorf("person not found")
+	expectedErr := ErrPersonNotFound
 
 	s.dbMock.ExpectBegin()
 	query := `SELECT id FROM "person" WHERE LOWER("first_name") = LOWER($1) AND LOWER("last_name") = LOWER($2) LIMIT 1`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(firstName, lastName).WillReturnRows(queryReturn)
 
-	rowsAffected, err := s.personService.DeletePerson(firstName, lastName)
+	rowsAffected, err := s.personService.DeletePerson(context.Background(), firstName, lastName)
 	assert.Equal(t, expectedErr, err)
 	assert.Equal(t, rowsAffected, expectedRowsAffected)
 
@@ -1066,7 +930,7 @@ func (s *testSuit) TestDeletePersonDeleteCourseFailure() {
 	query = `DELETE FROM "person_course" WHERE "person_id" = $1`
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(personID).WillReturnResult(sqlmock.NewResult(1, 1)).WillReturnError(errors.New("can't delete courses"))
 
-	rowsAffected, err := s.personService.DeletePerson(firstName, lastName)
+	rowsAffected, err := s.personService.DeletePerson(context.Background(), firstName, lastName)
 	assert.Equal(t, expectedErr, err)
 	assert.Equal(t, rowsAffected, expectedRowsAffected)
 
@@ -1091,7 +955,7 @@ func (s *testSuit) TestDeletePersonFailure() {
 	query = `DELETE FROM "person" WHERE "id" = $1`
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(personID).WillReturnResult(sqlmock.NewResult(1, 1)).WillReturnError(errors.New("can't delete person"))
 
-	rowsAffected, err := s.personService.DeletePerson(firstName, lastName)
+	rowsAffected, err := s.personService.DeletePerson(context.Background(), firstName, lastName)
 	assert.Equal(t, expectedErr, err)
 	assert.Equal(t, rowsAffected, expectedRowsAffected)
 
@@ -1107,7 +971,7 @@ func (s *testSuit) TestDeletePersonTransactionBeginFailure() {
 	expectedRowsAffected := int64(-1)
 
 	s.dbMock.ExpectBegin().WillReturnError(errors.New("can't begin transaction"))
-	rowsAffected, err := s.personService.DeletePerson(firstName, lastName)
+	rowsAffected, err := s.personService.DeletePerson(context.Background(), firstName, lastName)
 
 	assert.Equal(t, expectedRowsAffected, rowsAffected)
 	assert.Equal(t, expectedErr, err)
@@ -1133,10 +997,177 @@ func (s *testSuit) TestDeletePersonTransactionCommitFailure() {
 	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(personID).WillReturnResult(sqlmock.NewResult(1, 1))
 	s.dbMock.ExpectCommit().WillReturnError(errors.New("can't commit transaction"))
 
-	rowsAffected, err := s.personService.DeletePerson(firstName, lastName)
+	rowsAffected, err := s.personService.DeletePerson(context.Background(), firstName, lastName)
 	assert.Equal(t, expectedErr, err)
 	assert.Equal(t, rowsAffected, expectedRowsAffected)
 
 	err = s.dbMock.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
+
+// Tests for GetPersonByID()
+// GetPersonByIDExistsSuccess
+// GetPersonByIDDoesntExistSuccess
+// GetPersonByIDFailure
+func (s *testSuit) TestGetPersonByIDExistsSuccess() {
+	t := s.T()
+
+	people := []PersonDTO{
+		{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: "1,2"},
+	}
+	returnFinal := models.Person{ID: 3, FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 18, Courses: []int{1, 2}}
+
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE "id" = $1 LIMIT 1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(people))
+
+	result, err := s.personService.GetPersonByID(context.Background(), 3)
+	assert.Equal(t, returnFinal, result)
+	assert.NoError(t, err)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestGetPersonByIDDoesntExistSuccess() {
+	t := s.T()
+
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE "id" = $1 LIMIT 1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(99).WillReturnRows(&sqlmock.Rows{})
+
+	result, err := s.personService.GetPersonByID(context.Background(), 99)
+	assert.Equal(t, models.Person{}, result)
+	assert.NoError(t, err)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestGetPersonByIDFailure() {
+	t := s.T()
+
+	expectedErr := fmt.Errorf("failed to get person: %w", errors.New("can't get person"))
+
+	query := `SELECT ` + personColumns + ` FROM "person" WHERE "id" = $1 LIMIT 1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnError(errors.New("can't get person"))
+
+	result, err := s.personService.GetPersonByID(context.Background(), 3)
+	assert.Equal(t, models.Person{}, result)
+	assert.Equal(t, expectedErr, err)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+// Tests for UpdatePersonByID()
+// UpdatePersonByIDSuccess
+// UpdatePersonByIDNotFoundFailure
+// UpdatePersonByIDDeadlineExceededFailure
+func (s *testSuit) TestUpdatePersonByIDSuccess() {
+	t := s.T()
+
+	person_course := []Person_Course{
+		{PersonID: 3, CourseID: 1},
+		{PersonID: 3, CourseID: 2},
+		{PersonID: 3, CourseID: 3},
+	}
+	inputPerson := models.Person{ID: 8, FirstName: "Bubbly", LastName: "Thane", Type: "student", Age: 19, Courses: []int{3, 4}}
+	updateInput := []driver.Value{"Bubbly", "Thane", "student", 19, 3}
+	returnPerson := models.Person{ID: 3, FirstName: "Bubbly", LastName: "Thane", Type: "student", Age: 19, Courses: []int{3, 4}}
+
+	s.dbMock.ExpectBegin()
+	query := `UPDATE "person" SET "first_name" = $1, "last_name" = $2, "type" = $3, "age" = $4 WHERE "id" = $5`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(updateInput...).WillReturnResult(sqlmock.NewResult(3, 1))
+	query = `SELECT * FROM "person_course" WHERE person_id = $1`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(testutil.MustStructsToRows(person_course))
+	query = `DELETE FROM "person_course" WHERE person_id = $1 AND course_id IN ($2, $3)`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, 1, 2).WillReturnResult(sqlmock.NewResult(1, 2))
+	query = `SELECT id FROM "course"`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(testutil.MustStructsToRows([]ID{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}))
+	query = `INSERT INTO "person_course" (person_id, course_id) VALUES (3, 4)`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.dbMock.ExpectCommit()
+
+	updatedPerson, err := s.personService.UpdatePersonByID(context.Background(), 3, inputPerson)
+	assert.Equal(t, returnPerson, updatedPerson)
+	assert.NoError(t, err)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestUpdatePersonByIDNotFoundFailure() {
+	t := s.T()
+
+	inputPerson := models.Person{FirstName: "Bubbly", LastName: "Thane", Type: "student", Age: 19, Courses: []int{3, 4}}
+	updateInput := []driver.Value{"Bubbly", "Thane", "student", 19, 3}
+
+	s.dbMock.ExpectBegin()
+	query := `UPDATE "person" SET "first_name" = $1, "last_name" = $2, "type" = $3, "age" = $4 WHERE "id" = $5`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(updateInput...).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.dbMock.ExpectRollback()
+
+	updatedPerson, err := s.personService.UpdatePersonByID(context.Background(), 3, inputPerson)
+	assert.Equal(t, models.Person{}, updatedPerson)
+	assert.Equal(t, ErrPersonNotFound, err)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestUpdatePersonByIDDeadlineExceededFailure() {
+	t := s.T()
+
+	inputPerson := models.Person{FirstName: "Bubbly", LastName: "Thane", Type: "student", Age: 19, Courses: []int{3, 4}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	updatedPerson, err := s.personService.UpdatePersonByID(ctx, 3, inputPerson)
+	assert.Equal(t, models.Person{}, updatedPerson)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+// Tests for DeletePersonByID()
+// DeletePersonByIDSuccess
+// DeletePersonByIDNotFoundSuccess
+// DeletePersonByIDDeleteCourseFailure
+func (s *testSuit) TestDeletePersonByIDSuccess() {
+	t := s.T()
+
+	s.dbMock.ExpectBegin()
+	query := `DELETE FROM "person_course" WHERE "person_id" = $1`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(2).WillReturnResult(sqlmock.NewResult(1, 3))
+	query = `DELETE FROM "person" WHERE "id" = $1`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(2).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.dbMock.ExpectCommit()
+
+	rowsAffected, err := s.personService.DeletePersonByID(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), rowsAffected)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestDeletePersonByIDNotFoundSuccess() {
+	t := s.T()
+
+	s.dbMock.ExpectBegin()
+	query := `DELETE FROM "person_course" WHERE "person_id" = $1`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+	query = `DELETE FROM "person" WHERE "id" = $1`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.dbMock.ExpectCommit()
+
+	rowsAffected, err := s.personService.DeletePersonByID(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), rowsAffected)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+func (s *testSuit) TestDeletePersonByIDDeleteCourseFailure() {
+	t := s.T()
+
+	expectedErr := fmt.Errorf("failed to delete course relations: %w", errors.New("can't delete relations"))
+
+	s.dbMock.ExpectBegin()
+	query := `DELETE FROM "person_course" WHERE "person_id" = $1`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(errors.New("can't delete relations"))
+	s.dbMock.ExpectRollback()
+
+	rowsAffected, err := s.personService.DeletePersonByID(context.Background(), 2)
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, int64(-1), rowsAffected)
+	err = s.dbMock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
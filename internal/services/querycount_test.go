@@ -0,0 +1,141 @@
+package services
+
+//querycount_test.go checks that reading people costs a constant number of queries, whatever the number of people.
+//It uses countingDriver, a database/sql driver that counts the queries it is sent and answers each with generated
+//person rows, because sqlmock needs every query to be expected up front.
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingDriver opens connections whose queries each return rows people, and counts those queries. It is also its
+// own driver.Connector, so sql.OpenDB can use it without registering it.
+type countingDriver struct {
+	rows    int
+	queries atomic.Int64
+}
+
+func (d *countingDriver) Open(string) (driver.Conn, error) {
+	return &countingConn{driver: d}, nil
+}
+func (d *countingDriver) Connect(context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+func (d *countingDriver) Driver() driver.Driver {
+	return d
+}
+
+type countingConn struct {
+	driver *countingDriver
+}
+
+func (c *countingConn) Prepare(query string) (driver.Stmt, error) {
+	return &countingStmt{driver: c.driver}, nil
+}
+func (c *countingConn) Close() error {
+	return nil
+}
+func (c *countingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type countingStmt struct {
+	driver *countingDriver
+}
+
+func (s *countingStmt) Close() error {
+	return nil
+}
+func (s *countingStmt) NumInput() int {
+	return -1
+}
+func (s *countingStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec is not supported")
+}
+func (s *countingStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.driver.queries.Add(1)
+	return &countingRows{count: s.driver.rows}, nil
+}
+
+// countingRows returns count rows of personColumns, each person enrolled in three courses.
+type countingRows struct {
+	count int
+	next  int
+}
+
+func (r *countingRows) Columns() []string {
+	return []string{"id", "first_name", "last_name", "type", "age", "courses"}
+}
+func (r *countingRows) Close() error {
+	return nil
+}
+func (r *countingRows) Next(dest []driver.Value) error {
+	if r.next == r.count {
+		return io.EOF
+	}
+	r.next++
+	dest[0] = int64(r.next)
+	dest[1] = "Tim"
+	dest[2] = "Rogers"
+	dest[3] = "student"
+	dest[4] = int64(22)
+	dest[5] = []byte("1,2,3")
+	return nil
+}
+
+// newCountingService returns a RealPersonService whose queries each return rows people, and the driver counting them.
+func newCountingService(rows int) (*RealPersonService, *countingDriver) {
+	d := &countingDriver{rows: rows}
+	return NewPersonService(sql.OpenDB(d)), d
+}
+
+func TestGetAllPeopleQueryCount(t *testing.T) {
+	for _, rows := range []int{0, 1, 10, 1000} {
+		t.Run(fmt.Sprint(rows), func(t *testing.T) {
+			service, d := newCountingService(rows)
+			defer service.db.Close()
+
+			people, _, err := service.GetAllPeople(context.Background(), PersonFilter{}, ListOptions{})
+			assert.NoError(t, err)
+			assert.Len(t, people, rows)
+			assert.Equal(t, int64(1), d.queries.Load())
+		})
+	}
+}
+
+func BenchmarkGetAllPeople(b *testing.B) {
+	for _, rows := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprint(rows), func(b *testing.B) {
+			service, d := newCountingService(rows)
+			defer service.db.Close()
+
+			for range b.N {
+				if _, _, err := service.GetAllPeople(context.Background(), PersonFilter{}, ListOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(d.queries.Load())/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func BenchmarkGetPersonByID(b *testing.B) {
+	service, d := newCountingService(1)
+	defer service.db.Close()
+
+	for range b.N {
+		if _, err := service.GetPersonByID(context.Background(), 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(d.queries.Load())/float64(b.N), "queries/op")
+}
@@ -0,0 +1,537 @@
+// Package servicetest is a conformance suite for implementations of the ../services interfaces. A backend passes it
+// when it behaves like the Postgres backed Real services, so handlers can use either one interchangeably.
+package servicetest
+
+//servicetest.go defines the suites. ./servicetest_test.go runs them against every backend in this repository.
+
+import (
+	"context"
+	"strings"
+	"tech-challenge/internal/models"
+	"tech-challenge/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Services is one backend's implementation of each service interface, all sharing the same data.
+type Services struct {
+	People      services.PersonService
+	Courses     services.CourseService
+	Enrollments services.EnrollmentService
+	APIKeys     services.APIKeyService
+}
+
+// Factory returns the services of a backend holding no people, courses or API keys. It is called once per test, and may
+// use t to register cleanup.
+type Factory func(t *testing.T) Services
+
+// RunPersonServiceSuite checks that the PersonService returned by factory behaves like services.RealPersonService.
+func RunPersonServiceSuite(t *testing.T, factory Factory) {
+	t.Run("CreateAndGet", func(t *testing.T) { testCreateAndGetPerson(t, factory(t)) })
+	t.Run("GetMissing", func(t *testing.T) { testGetMissingPerson(t, factory(t)) })
+	t.Run("CreateUnknownCourse", func(t *testing.T) { testCreatePersonUnknownCourse(t, factory(t)) })
+	t.Run("UpdateByName", func(t *testing.T) { testUpdatePerson(t, factory(t)) })
+	t.Run("UpdateByID", func(t *testing.T) { testUpdatePersonByID(t, factory(t)) })
+	t.Run("Patch", func(t *testing.T) { testPatchPerson(t, factory(t)) })
+	t.Run("DeleteByName", func(t *testing.T) { testDeletePerson(t, factory(t)) })
+	t.Run("DeleteByIDCascades", func(t *testing.T) { testDeletePersonByID(t, factory(t)) })
+	t.Run("ListFilterSortPage", func(t *testing.T) { testGetAllPeople(t, factory(t)) })
+	t.Run("SortMixedCase", func(t *testing.T) { testSortPeopleMixedCase(t, factory(t)) })
+}
+
+// RunCourseServiceSuite checks that the CourseService returned by factory behaves like services.RealCourseService.
+func RunCourseServiceSuite(t *testing.T, factory Factory) {
+	t.Run("CreateGetUpdate", func(t *testing.T) { testCreateGetUpdateCourse(t, factory(t)) })
+	t.Run("Missing", func(t *testing.T) { testMissingCourse(t, factory(t)) })
+	t.Run("DeleteCascades", func(t *testing.T) { testDeleteCourse(t, factory(t)) })
+	t.Run("ListSortPage", func(t *testing.T) { testGetAllCourses(t, factory(t)) })
+	t.Run("Roster", func(t *testing.T) { testGetCourseRoster(t, factory(t)) })
+}
+
+// RunEnrollmentServiceSuite checks that the EnrollmentService returned by factory behaves like
+// services.RealEnrollmentService.
+func RunEnrollmentServiceSuite(t *testing.T, factory Factory) {
+	t.Run("EnrollAndDrop", func(t *testing.T) { testEnrollAndDrop(t, factory(t)) })
+	t.Run("Missing", func(t *testing.T) { testEnrollmentMissing(t, factory(t)) })
+}
+
+// RunAPIKeyServiceSuite checks that the APIKeyService returned by factory behaves like services.RealAPIKeyService.
+func RunAPIKeyServiceSuite(t *testing.T, factory Factory) {
+	t.Run("CreateListAuthenticate", func(t *testing.T) { testCreateAndAuthenticateAPIKey(t, factory(t)) })
+	t.Run("Rotate", func(t *testing.T) { testRotateAPIKey(t, factory(t)) })
+	t.Run("Revoke", func(t *testing.T) { testRevokeAPIKey(t, factory(t)) })
+}
+
+// createCourses creates a course for each name and returns their ids in the same order.
+func createCourses(t *testing.T, s Services, names ...string) []int {
+	ids := make([]int, len(names))
+	for i, name := range names {
+		id, err := s.Courses.CreateCourse(context.Background(), models.Course{Name: name})
+		require.NoError(t, err)
+		ids[i] = id
+	}
+	return ids
+}
+
+// createPerson creates person and returns it as stored, with its id and its courses in ascending order.
+func createPerson(t *testing.T, s Services, person models.Person) models.Person {
+	id, err := s.People.CreatePerson(context.Background(), person)
+	require.NoError(t, err)
+	stored, err := s.People.GetPersonByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, id, stored.ID)
+	return stored
+}
+
+func ids(people []models.Person) []int {
+	result := make([]int, len(people))
+	for i, person := range people {
+		result[i] = person.ID
+	}
+	return result
+}
+
+func testCreateAndGetPerson(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming", "Databases")
+
+	person := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{courses[1], courses[0]}})
+	assert.Equal(t, models.Person{ID: person.ID, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: courses}, person)
+
+	byName, err := s.People.GetPerson(ctx, "tIM", "ROGERS")
+	assert.NoError(t, err)
+	assert.Equal(t, person, byName)
+
+	noCourses := createPerson(t, s, models.Person{FirstName: "Jill", LastName: "Rogers", Type: "professor", Age: 40})
+	assert.Equal(t, []int{}, noCourses.Courses)
+}
+
+func testGetMissingPerson(t *testing.T, s Services) {
+	ctx := context.Background()
+
+	person, err := s.People.GetPersonByID(ctx, 404)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{}, person)
+
+	person, err = s.People.GetPerson(ctx, "No", "One")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{}, person)
+}
+
+func testCreatePersonUnknownCourse(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming")
+
+	_, err := s.People.CreatePerson(ctx, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{courses[0], courses[0] + 100}})
+	assert.ErrorIs(t, err, services.ErrUnknownCourse)
+
+	person, err := s.People.GetPerson(ctx, "Tim", "Rogers")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{}, person, "a failed create must not leave the person behind")
+}
+
+func testUpdatePerson(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming", "Databases")
+	person := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{courses[0]}})
+
+	updated, err := s.People.UpdatePerson(ctx, "TIM", "rogers", models.Person{FirstName: "Timothy", LastName: "Rogers", Type: "student", Age: 23, Courses: []int{courses[1]}})
+	assert.NoError(t, err)
+	assert.Equal(t, person.ID, updated.ID)
+
+	stored, err := s.People.GetPersonByID(ctx, person.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{ID: person.ID, FirstName: "Timothy", LastName: "Rogers", Type: "student", Age: 23, Courses: []int{courses[1]}}, stored)
+
+	_, err = s.People.UpdatePerson(ctx, "Tim", "Rogers", models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22})
+	assert.ErrorIs(t, err, services.ErrPersonNotFound)
+}
+
+func testUpdatePersonByID(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming", "Databases", "UI Design")
+	person := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{courses[0], courses[1]}})
+
+	_, err := s.People.UpdatePersonByID(ctx, person.ID, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "professor", Age: 50, Courses: []int{courses[1], courses[2]}})
+	assert.NoError(t, err)
+	stored, err := s.People.GetPersonByID(ctx, person.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{ID: person.ID, FirstName: "Tim", LastName: "Rogers", Type: "professor", Age: 50, Courses: []int{courses[1], courses[2]}}, stored)
+
+	_, err = s.People.UpdatePersonByID(ctx, person.ID, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "professor", Age: 50, Courses: []int{courses[2] + 100}})
+	assert.ErrorIs(t, err, services.ErrUnknownCourse)
+	_, err = s.People.UpdatePersonByID(ctx, person.ID+100, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22})
+	assert.ErrorIs(t, err, services.ErrPersonNotFound)
+}
+
+func testPatchPerson(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming", "Databases")
+	person := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{courses[0]}})
+
+	age := 23
+	patched, err := s.People.PatchPersonByID(ctx, person.ID, services.PersonPatch{Age: &age})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{ID: person.ID, FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 23, Courses: []int{courses[0]}}, patched)
+
+	newCourses := []int{courses[1], courses[0]}
+	patched, err = s.People.PatchPersonByID(ctx, person.ID, services.PersonPatch{Courses: &newCourses})
+	assert.NoError(t, err)
+	assert.Equal(t, courses, patched.Courses)
+
+	firstName := "Timothy"
+	unknown := []int{courses[1] + 100}
+	_, err = s.People.PatchPersonByID(ctx, person.ID, services.PersonPatch{FirstName: &firstName, Courses: &unknown})
+	assert.ErrorIs(t, err, services.ErrUnknownCourse)
+	stored, err := s.People.GetPersonByID(ctx, person.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, patched, stored, "a failed patch must change nothing")
+
+	_, err = s.People.PatchPersonByID(ctx, person.ID+100, services.PersonPatch{Age: &age})
+	assert.ErrorIs(t, err, services.ErrPersonNotFound)
+}
+
+func testDeletePerson(t *testing.T, s Services) {
+	ctx := context.Background()
+	person := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22})
+
+	deleted, err := s.People.DeletePerson(ctx, "tim", "ROGERS")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	stored, err := s.People.GetPersonByID(ctx, person.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Person{}, stored)
+
+	_, err = s.People.DeletePerson(ctx, "Tim", "Rogers")
+	assert.ErrorIs(t, err, services.ErrPersonNotFound)
+}
+
+func testDeletePersonByID(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming")
+	person := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: courses})
+
+	deleted, err := s.People.DeletePersonByID(ctx, person.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	deleted, err = s.People.DeletePersonByID(ctx, person.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+
+	roster, _, err := s.Courses.GetCourseRoster(ctx, courses[0], "", services.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, roster.Students, "deleting a person must remove their enrollments")
+}
+
+func testGetAllPeople(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming", "Databases")
+	tim := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{courses[0]}})
+	jill := createPerson(t, s, models.Person{FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 30, Courses: []int{courses[1]}})
+	jack := createPerson(t, s, models.Person{FirstName: "Jack", LastName: "Daniels", Type: "student", Age: 22})
+	bubbles := createPerson(t, s, models.Person{FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 48, Courses: courses})
+	noOne := 99
+
+	testCases := map[string]struct {
+		filter   services.PersonFilter
+		expected []int
+	}{
+		"everyone":         {filter: services.PersonFilter{}, expected: ids([]models.Person{tim, jill, jack, bubbles})},
+		"exact name":       {filter: services.PersonFilter{FirstName: "JILL", LastName: "rogers"}, expected: []int{jill.ID}},
+		"name prefix":      {filter: services.PersonFilter{LastName: "rog", NameMatch: services.NamePrefix}, expected: []int{tim.ID, jill.ID}},
+		"name contains":    {filter: services.PersonFilter{FirstName: "A", NameMatch: services.NameContains}, expected: []int{jack.ID}},
+		"type":             {filter: services.PersonFilter{Type: "professor"}, expected: []int{bubbles.ID}},
+		"age range":        {filter: services.PersonFilter{MinAge: &tim.Age, MaxAge: &jill.Age}, expected: []int{tim.ID, jill.ID, jack.ID}},
+		"enrolled in any":  {filter: services.PersonFilter{CourseIDs: []int{courses[1]}}, expected: []int{jill.ID, bubbles.ID}},
+		"nothing matching": {filter: services.PersonFilter{Age: &noOne}, expected: []int{}},
+	}
+	for test, testVars := range testCases {
+		t.Run(test, func(t *testing.T) {
+			people, _, err := s.People.GetAllPeople(ctx, testVars.filter, services.ListOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, testVars.expected, ids(people))
+		})
+	}
+
+	opts := services.ListOptions{Limit: 2, IncludeTotal: true, Sort: []services.SortField{{Field: "age"}, {Field: "first_name", Desc: true}}}
+	page, pageInfo, err := s.People.GetAllPeople(ctx, services.PersonFilter{}, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{tim.ID, jack.ID}, ids(page))
+	assert.Equal(t, 4, pageInfo.Total)
+	opts.Cursor = pageInfo.NextCursor
+	page, pageInfo, err = s.People.GetAllPeople(ctx, services.PersonFilter{}, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{jill.ID, bubbles.ID}, ids(page))
+	assert.Equal(t, "", pageInfo.NextCursor)
+
+	_, _, err = s.People.GetAllPeople(ctx, services.PersonFilter{}, services.ListOptions{Sort: []services.SortField{{Field: "salary"}}})
+	assert.ErrorIs(t, err, services.ErrInvalidInput)
+	_, _, err = s.People.GetAllPeople(ctx, services.PersonFilter{}, services.ListOptions{Cursor: "not a cursor"})
+	assert.ErrorIs(t, err, services.ErrInvalidInput)
+
+	// a cursor is only valid with the sort it was made for
+	byLastName := services.ListOptions{Limit: 1, Sort: []services.SortField{{Field: "last_name"}}}
+	_, pageInfo, err = s.People.GetAllPeople(ctx, services.PersonFilter{}, byLastName)
+	assert.NoError(t, err)
+	byAge := services.ListOptions{Limit: 1, Cursor: pageInfo.NextCursor, Sort: []services.SortField{{Field: "age"}}}
+	_, _, err = s.People.GetAllPeople(ctx, services.PersonFilter{}, byAge)
+	assert.ErrorIs(t, err, services.ErrInvalidCursor)
+	_, _, err = s.People.GetAllPeople(ctx, services.PersonFilter{Age: &noOne}, byAge)
+	assert.ErrorIs(t, err, services.ErrInvalidCursor)
+}
+
+func testSortPeopleMixedCase(t *testing.T, s Services) {
+	ctx := context.Background()
+	adams := createPerson(t, s, models.Person{FirstName: "Ann", LastName: "adams", Type: "student", Age: 20})
+	emile := createPerson(t, s, models.Person{FirstName: "Eve", LastName: "Émile", Type: "student", Age: 21})
+	baker := createPerson(t, s, models.Person{FirstName: "Bob", LastName: "Baker", Type: "student", Age: 22})
+	zimmer := createPerson(t, s, models.Person{FirstName: "Zoe", LastName: "Zimmer", Type: "student", Age: 23})
+	// names sort bytewise, so upper case comes before lower case and accented letters last, whatever the locale
+	expected := []int{baker.ID, zimmer.ID, adams.ID, emile.ID}
+
+	opts := services.ListOptions{Sort: []services.SortField{{Field: "last_name"}}}
+	people, _, err := s.People.GetAllPeople(ctx, services.PersonFilter{}, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, ids(people))
+
+	opts.Limit = 1
+	paged := make([]int, 0, len(expected))
+	for range expected {
+		page, pageInfo, err := s.People.GetAllPeople(ctx, services.PersonFilter{}, opts)
+		require.NoError(t, err)
+		paged = append(paged, ids(page)...)
+		opts.Cursor = pageInfo.NextCursor
+	}
+	assert.Equal(t, expected, paged)
+	assert.Equal(t, "", opts.Cursor)
+}
+
+func testCreateGetUpdateCourse(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming")
+
+	course, err := s.Courses.GetCourse(ctx, courses[0])
+	assert.NoError(t, err)
+	assert.Equal(t, models.Course{ID: courses[0], Name: "Programming"}, course)
+
+	updated, err := s.Courses.UpdateCourse(ctx, courses[0], models.Course{Name: "Advanced Programming"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Course{ID: courses[0], Name: "Advanced Programming"}, updated)
+
+	name := "Programming II"
+	patched, err := s.Courses.PatchCourse(ctx, courses[0], services.CoursePatch{Name: &name})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Course{ID: courses[0], Name: name}, patched)
+	patched, err = s.Courses.PatchCourse(ctx, courses[0], services.CoursePatch{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Course{ID: courses[0], Name: name}, patched)
+}
+
+func testMissingCourse(t *testing.T, s Services) {
+	ctx := context.Background()
+	name := "Programming"
+
+	_, err := s.Courses.GetCourse(ctx, 404)
+	assert.ErrorIs(t, err, services.ErrCourseNotFound)
+	_, err = s.Courses.UpdateCourse(ctx, 404, models.Course{Name: name})
+	assert.ErrorIs(t, err, services.ErrCourseNotFound)
+	_, err = s.Courses.PatchCourse(ctx, 404, services.CoursePatch{Name: &name})
+	assert.ErrorIs(t, err, services.ErrCourseNotFound)
+	_, _, err = s.Courses.GetCourseRoster(ctx, 404, "", services.ListOptions{})
+	assert.ErrorIs(t, err, services.ErrCourseNotFound)
+	deleted, err := s.Courses.DeleteCourse(ctx, 404)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+}
+
+func testDeleteCourse(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming", "Databases")
+	person := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: courses})
+
+	deleted, err := s.Courses.DeleteCourse(ctx, courses[0])
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	stored, err := s.People.GetPersonByID(ctx, person.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{courses[1]}, stored.Courses, "deleting a course must remove its enrollments")
+}
+
+func testGetAllCourses(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming", "Databases", "UI Design")
+
+	all, pageInfo, err := s.Courses.GetAllCourses(ctx, services.ListOptions{IncludeTotal: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Course{{ID: courses[0], Name: "Programming"}, {ID: courses[1], Name: "Databases"}, {ID: courses[2], Name: "UI Design"}}, all)
+	assert.Equal(t, services.PageInfo{Total: 3}, pageInfo)
+
+	opts := services.ListOptions{Limit: 2, Sort: []services.SortField{{Field: "name"}}}
+	page, pageInfo, err := s.Courses.GetAllCourses(ctx, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Course{{ID: courses[1], Name: "Databases"}, {ID: courses[0], Name: "Programming"}}, page)
+	opts.Cursor = pageInfo.NextCursor
+	page, pageInfo, err = s.Courses.GetAllCourses(ctx, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Course{{ID: courses[2], Name: "UI Design"}}, page)
+	assert.Equal(t, services.PageInfo{Total: -1}, pageInfo)
+}
+
+func testGetCourseRoster(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming", "Databases")
+	tim := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: courses})
+	createPerson(t, s, models.Person{FirstName: "Jill", LastName: "Rogers", Type: "student", Age: 30, Courses: []int{courses[1]}})
+	bubbles := createPerson(t, s, models.Person{FirstName: "Bubbles", LastName: "Thane", Type: "professor", Age: 48, Courses: []int{courses[0]}})
+
+	roster, pageInfo, err := s.Courses.GetCourseRoster(ctx, courses[0], "", services.ListOptions{IncludeTotal: true})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Roster{
+		Course:     models.Course{ID: courses[0], Name: "Programming"},
+		Professors: []models.Person{bubbles},
+		Students:   []models.Person{tim},
+	}, roster)
+	assert.Equal(t, 2, pageInfo.Total)
+
+	roster, _, err = s.Courses.GetCourseRoster(ctx, courses[0], "student", services.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Person{}, roster.Professors)
+	assert.Equal(t, []models.Person{tim}, roster.Students)
+}
+
+func testEnrollAndDrop(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming", "Databases")
+	person := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22, Courses: []int{courses[1]}})
+
+	created, err := s.Enrollments.Enroll(ctx, person.ID, courses[0])
+	assert.NoError(t, err)
+	assert.True(t, created)
+	created, err = s.Enrollments.Enroll(ctx, person.ID, courses[0])
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	enrolled, err := s.Enrollments.GetCoursesForPerson(ctx, person.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Course{{ID: courses[0], Name: "Programming"}, {ID: courses[1], Name: "Databases"}}, enrolled)
+
+	assert.NoError(t, s.Enrollments.Drop(ctx, person.ID, courses[1]))
+	assert.ErrorIs(t, s.Enrollments.Drop(ctx, person.ID, courses[1]), services.ErrEnrollmentNotFound)
+	enrolled, err = s.Enrollments.GetCoursesForPerson(ctx, person.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Course{{ID: courses[0], Name: "Programming"}}, enrolled)
+}
+
+func testEnrollmentMissing(t *testing.T, s Services) {
+	ctx := context.Background()
+	courses := createCourses(t, s, "Programming")
+	person := createPerson(t, s, models.Person{FirstName: "Tim", LastName: "Rogers", Type: "student", Age: 22})
+
+	_, err := s.Enrollments.Enroll(ctx, person.ID+100, courses[0])
+	assert.ErrorIs(t, err, services.ErrPersonNotFound)
+	_, err = s.Enrollments.Enroll(ctx, person.ID, courses[0]+100)
+	assert.ErrorIs(t, err, services.ErrCourseNotFound)
+	_, err = s.Enrollments.GetCoursesForPerson(ctx, person.ID+100)
+	assert.ErrorIs(t, err, services.ErrPersonNotFound)
+
+	enrolled, err := s.Enrollments.GetCoursesForPerson(ctx, person.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Course{}, enrolled)
+}
+
+func testCreateAndAuthenticateAPIKey(t *testing.T, s Services) {
+	ctx := context.Background()
+	start := time.Now().Add(-time.Second)
+	batch, batchKey, err := s.APIKeys.CreateAPIKey(ctx, models.APIKey{Name: "batch", Scopes: []string{"person:read", "course:read"}})
+	require.NoError(t, err)
+	partner, partnerKey, err := s.APIKeys.CreateAPIKey(ctx, models.APIKey{Name: "partner", Scopes: []string{"course:write"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "batch", batch.Name)
+	assert.Equal(t, []string{"person:read", "course:read"}, batch.Scopes)
+	assert.True(t, strings.HasPrefix(batchKey, batch.Prefix+"_"))
+	assert.NotEqual(t, batch.Prefix, partner.Prefix)
+	assert.WithinRange(t, batch.CreatedAt, start, time.Now().Add(time.Second))
+	assert.Nil(t, batch.LastUsedAt)
+	assert.Nil(t, batch.RevokedAt)
+
+	keys, err := s.APIKeys.GetAllAPIKeys(ctx)
+	assert.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, []int{batch.ID, partner.ID}, []int{keys[0].ID, keys[1].ID})
+	assert.Equal(t, batch.Prefix, keys[0].Prefix)
+	assert.Equal(t, batch.Scopes, keys[0].Scopes)
+	assert.True(t, batch.CreatedAt.Equal(keys[0].CreatedAt))
+
+	authenticated, err := s.APIKeys.AuthenticateAPIKey(ctx, partnerKey)
+	assert.NoError(t, err)
+	assert.Equal(t, partner.ID, authenticated.ID)
+	assert.Equal(t, []string{"course:write"}, authenticated.Scopes)
+	require.NotNil(t, authenticated.LastUsedAt)
+	keys, err = s.APIKeys.GetAllAPIKeys(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, keys[0].LastUsedAt)
+	require.NotNil(t, keys[1].LastUsedAt)
+	assert.True(t, authenticated.LastUsedAt.Equal(*keys[1].LastUsedAt))
+
+	for _, key := range []string{"", "not-a-key", batch.Prefix + "_wrong", partner.Prefix + batchKey[len(batch.Prefix):], "tc_000000000000_secret"} {
+		_, err = s.APIKeys.AuthenticateAPIKey(ctx, key)
+		assert.ErrorIs(t, err, services.ErrInvalidAPIKey, key)
+	}
+	_, err = s.APIKeys.AuthenticateAPIKey(ctx, batchKey)
+	assert.NoError(t, err)
+}
+
+func testRotateAPIKey(t *testing.T, s Services) {
+	ctx := context.Background()
+	created, oldKey, err := s.APIKeys.CreateAPIKey(ctx, models.APIKey{Name: "batch", Scopes: []string{"person:write"}})
+	require.NoError(t, err)
+	_, err = s.APIKeys.AuthenticateAPIKey(ctx, oldKey)
+	require.NoError(t, err)
+
+	rotated, newKey, err := s.APIKeys.RotateAPIKey(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, rotated.ID)
+	assert.Equal(t, "batch", rotated.Name)
+	assert.Equal(t, []string{"person:write"}, rotated.Scopes)
+	assert.NotEqual(t, created.Prefix, rotated.Prefix)
+	assert.True(t, strings.HasPrefix(newKey, rotated.Prefix+"_"))
+	assert.Nil(t, rotated.LastUsedAt)
+
+	_, err = s.APIKeys.AuthenticateAPIKey(ctx, oldKey)
+	assert.ErrorIs(t, err, services.ErrInvalidAPIKey)
+	authenticated, err := s.APIKeys.AuthenticateAPIKey(ctx, newKey)
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, authenticated.ID)
+
+	_, _, err = s.APIKeys.RotateAPIKey(ctx, created.ID+100)
+	assert.ErrorIs(t, err, services.ErrAPIKeyNotFound)
+}
+
+func testRevokeAPIKey(t *testing.T, s Services) {
+	ctx := context.Background()
+	created, key, err := s.APIKeys.CreateAPIKey(ctx, models.APIKey{Name: "partner", Scopes: []string{"course:read"}})
+	require.NoError(t, err)
+
+	assert.NoError(t, s.APIKeys.RevokeAPIKey(ctx, created.ID))
+	_, err = s.APIKeys.AuthenticateAPIKey(ctx, key)
+	assert.ErrorIs(t, err, services.ErrInvalidAPIKey)
+	keys, err := s.APIKeys.GetAllAPIKeys(ctx)
+	assert.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.NotNil(t, keys[0].RevokedAt)
+	revokedAt := *keys[0].RevokedAt
+
+	// revoking again keeps the first revocation, and a revoked key cannot be brought back by rotating it
+	assert.NoError(t, s.APIKeys.RevokeAPIKey(ctx, created.ID))
+	keys, err = s.APIKeys.GetAllAPIKeys(ctx)
+	assert.NoError(t, err)
+	assert.True(t, revokedAt.Equal(*keys[0].RevokedAt))
+	_, _, err = s.APIKeys.RotateAPIKey(ctx, created.ID)
+	assert.ErrorIs(t, err, services.ErrAPIKeyNotFound)
+	assert.ErrorIs(t, s.APIKeys.RevokeAPIKey(ctx, created.ID+100), services.ErrAPIKeyNotFound)
+}
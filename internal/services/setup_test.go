@@ -12,6 +12,8 @@ type testSuit struct {
 	suite.Suite
 	realCourseService *RealCourseService
 	personService     *RealPersonService
+	enrollmentService *RealEnrollmentService
+	apiKeyService     *RealAPIKeyService
 	dbMock            sqlmock.Sqlmock
 }
 type Person_Course struct {
@@ -24,6 +26,8 @@ type PersonDTO struct {
 	LastName  string `json:"last_name" validate:"required"`
 	Type      string `json:"type" validate:"required,ValidateType"`
 	Age       int    `json:"age" validate:"required,gt=0"`
+	// Courses is the courses column of personColumns as the database returns it, such as "1,2,3".
+	Courses string
 }
 type ID struct {
 	ID int
@@ -40,8 +44,11 @@ func (s *testSuit) SetupSuite() {
 	s.dbMock = mock
 	s.realCourseService = NewCourseService(db)
 	s.personService = NewPersonService(db)
+	s.enrollmentService = NewEnrollmentService(db)
+	s.apiKeyService = NewAPIKeyService(db)
 }
 func (s *testSuit) TearDownSuite() {
 	s.realCourseService.db.Close()
 	s.personService.db.Close()
+	s.enrollmentService.db.Close()
 }